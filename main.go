@@ -3,6 +3,8 @@ package main
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/zhengkyl/review-ssh/server"
 )
@@ -13,7 +15,87 @@ func main() {
 		log.Fatal("TMDB_API_KEY missing")
 	}
 
-	server.RunServer(tmdbKey)
+	historyPath, ok := os.LookupEnv("HISTORY_PATH")
+	if !ok {
+		historyPath = ".data/search_history.json"
+	}
+
+	settingsPath, ok := os.LookupEnv("SETTINGS_PATH")
+	if !ok {
+		settingsPath = ".data/settings.json"
+	}
+
+	groupsPath, ok := os.LookupEnv("GROUPS_PATH")
+	if !ok {
+		groupsPath = ".data/groups.json"
+	}
+
+	diaryPath, ok := os.LookupEnv("DIARY_PATH")
+	if !ok {
+		diaryPath = ".data/diary.json"
+	}
+
+	sessionsPath, ok := os.LookupEnv("SESSIONS_PATH")
+	if !ok {
+		sessionsPath = ".data/sessions.json"
+	}
+
+	// Kept apart from the data it encrypts
+	sessionKeyPath, ok := os.LookupEnv("SESSION_KEY_PATH")
+	if !ok {
+		sessionKeyPath = ".ssh/session_key"
+	}
+
+	adminKeysPath, ok := os.LookupEnv("ADMIN_KEYS_PATH")
+	if !ok {
+		adminKeysPath = ".ssh/admin_keys"
+	}
+
+	server.RunServer(server.Config{
+		TMDBKey:      tmdbKey,
+		HistoryPath:  historyPath,
+		SettingsPath: settingsPath,
+		GroupsPath:   groupsPath,
+		DiaryPath:    diaryPath,
+		SessionsPath: sessionsPath,
+
+		SessionKey:     os.Getenv("SESSION_KEY"),
+		SessionKeyPath: sessionKeyPath,
+
+		MaxSessions:      intEnv("MAX_SESSIONS", 100),
+		MaxSessionsPerIP: intEnv("MAX_SESSIONS_PER_IP", 5),
+		IdleTimeout:      durationEnv("IDLE_TIMEOUT", 30*time.Minute),
+		ShutdownNotice:   durationEnv("SHUTDOWN_NOTICE", 20*time.Second),
+		MetricsAddr:      os.Getenv("METRICS_ADDR"),
+		AuditLogPath:     os.Getenv("AUDIT_LOG"),
+		AuditLogMaxBytes: int64(intEnv("AUDIT_LOG_MAX_MB", 10)) << 20,
+		AdminKeysPath:    adminKeysPath,
+	})
+}
+
+func intEnv(name string, fallback int) int {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("%s should be a number, got %q", name, value)
+	}
+	return n
+}
+
+// Like 30m or 90s
+func durationEnv(name string, fallback time.Duration) time.Duration {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("%s should be a duration like 30m, got %q", name, value)
+	}
+	return d
 }
 
 // func runLocal() {
@@ -41,8 +123,22 @@ func main() {
 
 // 			ReviewMap:  map[int]common.Review{},
 // 			FilmCache:  common.Cache[common.Film]{},
+// 			GenreMap:   map[int]string{},
 // 			KeyMap:     keymap.DefaultKeyMap(),
 // 			HttpClient: httpClient,
+//
+// 			ProviderCache: common.Cache[common.WatchProviders]{},
+// 			UserCache:     common.Cache[common.User]{},
+//
+// 			SearchHistory: common.NewMemoryHistory(),
+// 			Settings:      common.NewMemorySettings(),
+// 			Groups:        common.NewMemoryGroups(),
+// 			Diary:         common.NewMemoryDiary(),
+// 			Sessions:      common.NoSessions{},
+// 			SignIns:       common.NoLimit{},
+// 			Guest:         common.NewGuestReviews(),
+// 			Outbox:        &common.Outbox{},
+// 			Output:        termenv.DefaultOutput(),
 // 		},
 // 	}
 
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"runtime"
+)
+
+var (
+	ActiveSessions = NewGauge("reviewssh_active_sessions", "Sessions open right now, by kind (tui or command).", "kind")
+	SessionSeconds = NewHistogram("reviewssh_session_duration_seconds", "How long sessions lasted, by kind.",
+		[]float64{10, 30, 60, 300, 900, 1800, 3600, 7200}, "kind")
+	SessionsRefused = NewCounter("reviewssh_sessions_refused_total", "Sessions turned away by the session limits, by limit (total or ip).", "limit")
+
+	PageViews    = NewCounter("reviewssh_page_views_total", "Pages opened in the TUI, by page.", "page")
+	FrameSeconds = NewHistogram("reviewssh_frame_render_seconds", "Time to render a frame of the TUI.",
+		[]float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25})
+
+	Requests       = NewCounter("reviewssh_upstream_requests_total", "Requests to tmdb, images or review-api, by result (2xx, 3xx, 4xx, 5xx or error).", "upstream", "result")
+	RequestSeconds = NewHistogram("reviewssh_upstream_request_duration_seconds", "Time to get response headers from an upstream.",
+		[]float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}, "upstream")
+
+	// A lookup is a hit if the data is there, loading if it's on its way, otherwise a miss
+	CacheLookups = NewCounter("reviewssh_cache_lookups_total", "Cache lookups by cache (film, providers, user or poster) and result (hit, loading or miss).", "cache", "result")
+)
+
+func init() {
+	// Memory is what the fly.io VM runs out of first
+	NewGaugeFunc("reviewssh_heap_bytes", "Bytes of allocated heap objects.", func() float64 {
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		return float64(stats.HeapAlloc)
+	})
+	NewGaugeFunc("reviewssh_sys_bytes", "Bytes of memory obtained from the OS.", func() float64 {
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		return float64(stats.Sys)
+	})
+	NewGaugeFunc("reviewssh_goroutines", "Goroutines running.", func() float64 {
+		return float64(runtime.NumGoroutine())
+	})
+}
@@ -0,0 +1,119 @@
+// Package metrics wraps the Prometheus client for what review-ssh exports.
+// Metrics are created once in metrics.go. A bad call site is logged, never a panic,
+// since it runs inside someone's session.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/charmbracelet/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+)
+
+var registry = prometheus.NewRegistry()
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+func register(c prometheus.Collector) {
+	if err := registry.Register(c); err != nil {
+		log.Error("could not register metric", "err", err)
+	}
+}
+
+// Counter only goes up
+type Counter struct {
+	name string
+	vec  *prometheus.CounterVec
+}
+
+func NewCounter(name, help string, labels ...string) *Counter {
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labels)
+	register(vec)
+	return &Counter{name, vec}
+}
+
+func (c *Counter) get(labelValues []string) prometheus.Counter {
+	counter, err := c.vec.GetMetricWithLabelValues(labelValues...)
+	if err != nil {
+		log.Error("bad metric labels", "metric", c.name, "err", err)
+	}
+	return counter
+}
+
+func (c *Counter) Inc(labelValues ...string) {
+	if counter := c.get(labelValues); counter != nil {
+		counter.Inc()
+	}
+}
+
+// Value is the count so far for labelValues, eg for the admin console
+func (c *Counter) Value(labelValues ...string) float64 {
+	counter := c.get(labelValues)
+	if counter == nil {
+		return 0
+	}
+	var m dto.Metric
+	if err := counter.Write(&m); err != nil {
+		return 0
+	}
+	return m.GetCounter().GetValue()
+}
+
+// Gauge goes up and down
+type Gauge struct {
+	name string
+	vec  *prometheus.GaugeVec
+}
+
+func NewGauge(name, help string, labels ...string) *Gauge {
+	vec := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, labels)
+	register(vec)
+	return &Gauge{name, vec}
+}
+
+func (g *Gauge) add(delta float64, labelValues []string) {
+	gauge, err := g.vec.GetMetricWithLabelValues(labelValues...)
+	if err != nil {
+		log.Error("bad metric labels", "metric", g.name, "err", err)
+		return
+	}
+	gauge.Add(delta)
+}
+
+func (g *Gauge) Inc(labelValues ...string) {
+	g.add(1, labelValues)
+}
+
+func (g *Gauge) Dec(labelValues ...string) {
+	g.add(-1, labelValues)
+}
+
+// NewGaugeFunc registers a gauge that calls read when scraped
+func NewGaugeFunc(name, help string, read func() float64) {
+	register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help}, read))
+}
+
+// Histogram counts observations into buckets by upper bound
+type Histogram struct {
+	name string
+	vec  *prometheus.HistogramVec
+}
+
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: buckets}, labels)
+	register(vec)
+	return &Histogram{name, vec}
+}
+
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	observer, err := h.vec.GetMetricWithLabelValues(labelValues...)
+	if err != nil {
+		log.Error("bad metric labels", "metric", h.name, "err", err)
+		return
+	}
+	observer.Observe(v)
+}
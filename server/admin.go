@@ -0,0 +1,176 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/zhengkyl/review-ssh/metrics"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+const adminUsage = `usage: ssh <host> admin <command>
+
+commands:
+  sessions                    who's connected, from where and on what page
+  kick <id> [reason]          close a session
+  broadcast <message>         show a message in every session's banner
+  caches                      cache lookups and sizes
+  flush                       empty every session's film, provider and user caches
+  maintenance on [message]    turn away everyone but admins
+  maintenance off
+`
+
+// loadAdminKeys reads path in authorized_keys format. No file means no admins.
+func loadAdminKeys(path string) []ssh.PublicKey {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		log.Error("could not read admin keys", "path", path, "err", err)
+		return nil
+	}
+
+	var keys []ssh.PublicKey
+	for len(strings.TrimSpace(string(data))) > 0 {
+		key, _, _, rest, err := gossh.ParseAuthorizedKey(data)
+		if err != nil {
+			log.Error("could not parse admin keys", "path", path, "err", err)
+			break
+		}
+		keys = append(keys, key)
+		data = rest
+	}
+	log.Info("loaded admin keys", "count", len(keys))
+	return keys
+}
+
+// runAdmin is the admin console, for sessions with a key from Config.AdminKeysPath
+func runAdmin(s ssh.Session, sh *shared, args []string) {
+	if len(args) == 0 {
+		wish.Fatal(s, adminUsage)
+		return
+	}
+
+	audit := sessionAudit(sh.audit, s.Context()).With("admin", provenFingerprint(s))
+	r := sh.registry
+
+	switch args[0] {
+	case "sessions":
+		adminSessions(s, r)
+	case "kick":
+		if len(args) < 2 {
+			wish.Fatal(s, adminUsage)
+			return
+		}
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			wish.Fatalf(s, "session id should be a number, got %q\n", args[1])
+			return
+		}
+		reason := strings.Join(args[2:], " ")
+		if reason == "" {
+			reason = "no reason given"
+		}
+		if !r.kick(id, reason) {
+			wish.Fatalf(s, "no session %d\n", id)
+			return
+		}
+		audit.Info("admin.kick", "kicked", id, "reason", reason)
+		wish.Printf(s, "kicked session %d\n", id)
+	case "broadcast":
+		message := strings.Join(args[1:], " ")
+		if message == "" {
+			wish.Fatal(s, adminUsage)
+			return
+		}
+		sent := r.send(common.Broadcast(message))
+		audit.Info("admin.broadcast", "message", message, "sessions", sent)
+		wish.Printf(s, "sent to %d sessions\n", sent)
+	case "caches":
+		adminCaches(s, r)
+	case "flush":
+		sent := r.send(common.FlushCaches{})
+		audit.Info("admin.flush", "sessions", sent)
+		wish.Printf(s, "flushed the caches of %d sessions\n", sent)
+	case "maintenance":
+		adminMaintenance(s, r, audit, args[1:])
+	default:
+		wish.Fatalf(s, "unknown admin command %q\n\n%s", args[0], adminUsage)
+	}
+}
+
+func adminSessions(s ssh.Session, r *registry) {
+	w := tabwriter.NewWriter(s, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tUSER\tIP\tPAGE\tFOR")
+	for _, e := range r.list() {
+		user := "-"
+		if e.userId != 0 {
+			user = fmt.Sprintf("%s (%d)", e.user, e.userId)
+		}
+		page := e.page
+		if e.program == nil {
+			page = "$ " + e.kind
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", e.id, user, e.ip, page, time.Since(e.started).Round(time.Second))
+	}
+	w.Flush()
+}
+
+func adminCaches(s ssh.Session, r *registry) {
+	w := tabwriter.NewWriter(s, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "CACHE\tHITS\tLOADING\tMISSES\tHIT RATE\t")
+	for _, cache := range []string{"film", "providers", "user", "poster"} {
+		hits := metrics.CacheLookups.Value(cache, "hit")
+		loading := metrics.CacheLookups.Value(cache, "loading")
+		misses := metrics.CacheLookups.Value(cache, "miss")
+		rate := "-"
+		if total := hits + loading + misses; total > 0 {
+			rate = fmt.Sprintf("%.1f%%", hits/total*100)
+		}
+		fmt.Fprintf(w, "%s\t%.0f\t%.0f\t%.0f\t%s\t\n", cache, hits, loading, misses, rate)
+	}
+	w.Flush()
+
+	films, tuis := 0, 0
+	for _, e := range r.list() {
+		if e.program != nil {
+			films += e.films
+			tuis++
+		}
+	}
+	wish.Printf(s, "\n%d films cached across %d sessions, as of their last page change\n", films, tuis)
+}
+
+func adminMaintenance(s ssh.Session, r *registry, audit *log.Logger, args []string) {
+	if len(args) == 0 {
+		wish.Fatal(s, adminUsage)
+		return
+	}
+	switch args[0] {
+	case "on":
+		message := strings.Join(args[1:], " ")
+		if message == "" {
+			message = "Back soon."
+		}
+		r.setMaintenance(message)
+		audit.Info("admin.maintenance", "on", true, "message", message)
+		wish.Printf(s, "maintenance on, %d sessions are still connected\n", r.len()-1)
+	case "off":
+		r.setMaintenance("")
+		audit.Info("admin.maintenance", "on", false)
+		wish.Println(s, "maintenance off")
+	default:
+		wish.Fatal(s, adminUsage)
+	}
+}
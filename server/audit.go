@@ -0,0 +1,93 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+)
+
+// Rotated audit logs kept besides the current one, as path.1 (newest) to path.5
+const auditBackups = 5
+
+// auditWriter appends to path, rotating it past maxBytes, or writes to stdout if path is empty.
+// Every session's logger shares it, so writes are serialized here.
+type auditWriter struct {
+	mtx      sync.Mutex
+	path     string
+	maxBytes int64
+	file     *os.File
+	size     int64
+}
+
+func (w *auditWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	w.file, w.size = file, info.Size()
+	return nil
+}
+
+func (w *auditWriter) rotate() error {
+	w.file.Close()
+	w.file = nil
+	for i := auditBackups - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", w.path, i), fmt.Sprintf("%s.%d", w.path, i+1))
+	}
+	if err := os.Rename(w.path, w.path+".1"); err != nil {
+		return err
+	}
+	return w.open()
+}
+
+func (w *auditWriter) Write(p []byte) (int, error) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if w.path == "" {
+		return os.Stdout.Write(p)
+	}
+	if w.file == nil {
+		if err := w.open(); err != nil {
+			return 0, err
+		}
+	}
+	if w.maxBytes > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxBytes {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// newAuditLogger writes JSON lines to path, or stdout if path is empty
+func newAuditLogger(path string, maxBytes int64) *log.Logger {
+	var w io.Writer = &auditWriter{path: path, maxBytes: maxBytes}
+	return log.NewWithOptions(w, log.Options{
+		ReportTimestamp: true,
+		TimeFormat:      time.RFC3339,
+		Formatter:       log.JSONFormatter,
+		Prefix:          "audit",
+	})
+}
+
+// Every entry says which connection it came from
+func sessionAudit(audit *log.Logger, ctx ssh.Context) *log.Logger {
+	return audit.With("session", ctx.SessionID(), "remote", ctx.RemoteAddr().String())
+}
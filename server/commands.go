@@ -0,0 +1,288 @@
+package server
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/pages/account"
+	"github.com/zhengkyl/review-ssh/ui/pages/exporter"
+	"github.com/zhengkyl/review-ssh/ui/pages/importer"
+	"github.com/zhengkyl/review-ssh/ui/pages/stats"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// Exports are small, but don't let a stray pipe fill up memory
+const maxInputBytes = 16 << 20
+
+type contextKey string
+
+const authKey contextKey = "auth"
+
+const commandsUsage = `usage: ssh <email>@<host> <command>
+
+commands:
+  import [--format auto|letterboxd|letterboxd-watchlist|imdb|trakt] [--dry-run] < export
+  export [--format json|csv|markdown|html|letterboxd] [--year 2023] > file
+  stats [--width 80] > file
+`
+
+// Each session has its own client, but TMDB requests share tmdbBucket.
+// Metrics are taken under the bucket, so waiting on it isn't counted as latency.
+func newHttpClient() *retryablehttp.Client {
+	httpClient := retryablehttp.NewClient()
+	httpClient.Logger = nil
+	httpClient.HTTPClient.Transport = &tmdbTransport{
+		base:   &metricsTransport{httpClient.HTTPClient.Transport},
+		bucket: tmdbBucket,
+	}
+	return httpClient
+}
+
+// Set on the connection's permissions by publicKeyHandler
+const fingerprintExt = "review-ssh.fingerprint"
+
+// Keys are only used to recognize returning users, see keySessions.
+// Usernames that look like emails still need a password, so they skip this.
+//
+// This also runs for keys the client only offers, without proving it holds them.
+// The fingerprint it sets is dropped unless the client then signs with the key, see provenFingerprint.
+func publicKeyHandler(ctx ssh.Context, key ssh.PublicKey) bool {
+	if strings.Contains(ctx.User(), "@") {
+		return false
+	}
+	perms := ctx.Permissions()
+	if perms.Extensions == nil {
+		perms.Extensions = map[string]string{}
+	}
+	perms.Extensions[fingerprintExt] = gossh.FingerprintSHA256(key)
+	return true
+}
+
+// provenFingerprint is the fingerprint of the key the client signed in with, or "" if it didn't.
+// Once connected, the permissions are those of the auth method that succeeded,
+// so unlike s.PublicKey() in older ssh versions, an offered key can't end up here.
+func provenFingerprint(s ssh.Session) string {
+	return s.Permissions().Extensions[fingerprintExt]
+}
+
+// Usernames that look like emails are asked for their review-api password,
+// so commands can run as that user. Everyone else gets in without a prompt, as before.
+func keyboardInteractiveHandler(signIns *signInLimiter, audit *log.Logger) ssh.KeyboardInteractiveHandler {
+	return func(ctx ssh.Context, challenger gossh.KeyboardInteractiveChallenge) bool {
+		if !strings.Contains(ctx.User(), "@") {
+			return true
+		}
+
+		ip := remoteIP(ctx.RemoteAddr())
+		if ok, wait := signIns.allow(ip, ctx.User()); !ok {
+			log.Warn("ssh sign in limited", "user", ctx.User(), "ip", ip)
+			sessionAudit(audit, ctx).Info("sign_in", "via", "ssh", "email", ctx.User(), "user_id", 0, "error", "rate limited")
+			challenger("", fmt.Sprintf("Too many attempts to sign in, try again in %s.", wait.Round(time.Second)), nil, nil)
+			return false
+		}
+
+		answers, err := challenger("", "Sign in to review-ssh", []string{"Password: "}, []bool{false})
+		if err != nil || len(answers) != 1 {
+			return false
+		}
+
+		auth, err := account.SignIn(newHttpClient(), ctx.User(), answers[0])
+		sessionAudit(audit, ctx).Info("sign_in", "via", "ssh", "email", ctx.User(), "user_id", auth.User.Id, "error", err)
+		if err != nil {
+			log.Info("ssh sign in failed", "user", ctx.User(), "err", err)
+			return false
+		}
+
+		ctx.SetValue(authKey, auth)
+		return true
+	}
+}
+
+// commandMiddleware runs non-interactive commands like "ssh host import < file".
+// Sessions without a command fall through to the TUI.
+func commandMiddleware(tmdbKey string, sh *shared) wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			args := s.Command()
+			if len(args) == 0 {
+				next(s)
+				return
+			}
+
+			// Admins are known by key, not by signing in
+			if args[0] == "admin" {
+				if !sh.registry.isAdmin(s) {
+					wish.Fatalln(s, "admin commands need a key listed in the admin keys")
+					return
+				}
+				runAdmin(s, sh, args[1:])
+				return
+			}
+
+			auth, ok := s.Context().Value(authKey).(common.AuthState)
+			if !ok {
+				wish.Fatal(s, "sign in by connecting as your email, like ssh you@example.com@<host>\n\n"+commandsUsage)
+				return
+			}
+
+			g := common.Global{
+				AuthState:  &auth,
+				Config:     common.Config{TMDB_API_KEY: tmdbKey},
+				HttpClient: newHttpClient(),
+				Outbox:     sh.outbox,
+				Audit:      sessionAudit(sh.audit, s.Context()),
+			}
+
+			switch args[0] {
+			case "import":
+				runImport(s, g, args[1:])
+			case "export":
+				runExport(s, g, args[1:])
+			case "stats":
+				runStats(s, g, args[1:])
+			default:
+				wish.Fatalf(s, "unknown command %q\n\n%s", args[0], commandsUsage)
+			}
+		}
+	}
+}
+
+func runImport(s ssh.Session, g common.Global, args []string) {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(s.Stderr())
+	formatFlag := flags.String("format", "auto", "export format")
+	dryRun := flags.Bool("dry-run", false, "show changes without saving them")
+	if err := flags.Parse(args); err != nil {
+		s.Exit(2)
+		return
+	}
+
+	format, err := importer.ParseFormat(*formatFlag)
+	if err != nil {
+		wish.Fatalln(s, err)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(s, maxInputBytes))
+	if err != nil {
+		wish.Fatalln(s, "could not read stdin:", err)
+		return
+	}
+
+	entries, err := importer.Parse(data, format)
+	if err != nil {
+		wish.Fatalln(s, err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(s.Context())
+	defer cancel()
+
+	existing, err := common.FetchAllReviews(ctx, g, g.AuthState.User.Id)
+	if err != nil {
+		wish.Fatalln(s, "could not load your reviews:", err)
+		return
+	}
+
+	resolutions := importer.ResolveAll(ctx, g, entries, func(done int) {
+		wish.Errorf(s, "\rfinding films %d/%d", done, len(entries))
+	})
+	wish.Errorln(s)
+
+	// There's no one to ask, so ambiguous matches are reported and skipped
+	for _, res := range resolutions {
+		if res.Ambiguous() {
+			titles := make([]string, 0, len(res.Candidates))
+			for _, film := range res.Candidates {
+				titles = append(titles, film.Title+" "+strings.SplitN(film.Release_date, "-", 2)[0])
+			}
+			wish.Errorf(s, "skipping %q, could be: %s (use the TUI importer to choose)\n", res.Entry.String(), strings.Join(titles, ", "))
+		}
+	}
+
+	changes := importer.Plan(resolutions, existing)
+	for _, c := range changes {
+		if c.Action != importer.Unchanged {
+			wish.Println(s, c.String())
+		}
+	}
+	wish.Println(s, importer.Summarize(changes).String())
+
+	if *dryRun {
+		return
+	}
+
+	pending := importer.Pending(changes)
+	common.Audit(g, "import.start", "changes", len(pending), "via", "ssh")
+	failed := 0
+	for i, c := range pending {
+		if _, err := importer.Apply(ctx, g, c); err != nil {
+			failed++
+			wish.Errorf(s, "\rcould not save %s: %v\n", c.Title(), err)
+		}
+		wish.Errorf(s, "\rsaving %d/%d", i+1, len(pending))
+	}
+	wish.Errorln(s)
+	wish.Printf(s, "%d of %d reviews saved.\n", len(pending)-failed, len(pending))
+	common.Audit(g, "import.done", "saved", len(pending)-failed, "failed", failed, "via", "ssh")
+
+	if failed > 0 {
+		s.Exit(1)
+	}
+}
+
+func runExport(s ssh.Session, g common.Global, args []string) {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(s.Stderr())
+	formatFlag := flags.String("format", "json", "json, csv, markdown, html or letterboxd")
+	year := flags.Int("year", time.Now().Year(), "year for markdown and html")
+	if err := flags.Parse(args); err != nil {
+		s.Exit(2)
+		return
+	}
+
+	format, err := exporter.ParseFormat(*formatFlag)
+	if err != nil {
+		wish.Fatalln(s, err)
+		return
+	}
+
+	items, err := exporter.Load(s.Context(), g, g.AuthState.User.Id, nil)
+	if err != nil {
+		wish.Fatalln(s, "could not load your reviews:", err)
+		return
+	}
+
+	if err := exporter.Write(s, items, format, *year); err != nil {
+		wish.Fatalln(s, err)
+	}
+}
+
+func runStats(s ssh.Session, g common.Global, args []string) {
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	flags.SetOutput(s.Stderr())
+	width := flags.Int("width", 80, "columns to fit the charts in")
+	if err := flags.Parse(args); err != nil {
+		s.Exit(2)
+		return
+	}
+
+	items, directors, err := stats.Load(s.Context(), g, g.AuthState.User.Id, nil)
+	if err != nil {
+		wish.Fatalln(s, "could not load your reviews:", err)
+		return
+	}
+
+	if err := stats.Write(s, stats.Compute(items, directors, nil, time.Now()), *width); err != nil {
+		wish.Fatalln(s, err)
+	}
+}
@@ -0,0 +1,87 @@
+package server
+
+import "github.com/zhengkyl/review-ssh/ui/common"
+
+// guestSplit keeps guests out of a shared store, since every guest has the same id.
+// Each session's guest gets a store of its own that's gone when they leave.
+type guestSplit[T any] struct {
+	shared T
+	guest  T
+}
+
+func (s guestSplit[T]) pick(userId int) T {
+	if userId == common.GuestAuthState.User.Id {
+		return s.guest
+	}
+	return s.shared
+}
+
+type sessionHistory struct {
+	guestSplit[common.SearchHistory]
+}
+
+func newSessionHistory(shared common.SearchHistory) sessionHistory {
+	return sessionHistory{guestSplit[common.SearchHistory]{shared, common.NewMemoryHistory()}}
+}
+
+func (h sessionHistory) Recent(userId int) []common.SearchEntry {
+	return h.pick(userId).Recent(userId)
+}
+
+func (h sessionHistory) AddRecent(userId int, entry common.SearchEntry) {
+	h.pick(userId).AddRecent(userId, entry)
+}
+
+func (h sessionHistory) RemoveRecent(userId int, query string) {
+	h.pick(userId).RemoveRecent(userId, query)
+}
+
+func (h sessionHistory) Saved(userId int) []common.SearchEntry {
+	return h.pick(userId).Saved(userId)
+}
+
+func (h sessionHistory) Save(userId int, entry common.SearchEntry) {
+	h.pick(userId).Save(userId, entry)
+}
+
+func (h sessionHistory) Unsave(userId int, name string) {
+	h.pick(userId).Unsave(userId, name)
+}
+
+type sessionSettings struct {
+	guestSplit[common.SettingsStore]
+}
+
+func newSessionSettings(shared common.SettingsStore) sessionSettings {
+	return sessionSettings{guestSplit[common.SettingsStore]{shared, common.NewMemorySettings()}}
+}
+
+func (s sessionSettings) Get(userId int) common.Settings {
+	return s.pick(userId).Get(userId)
+}
+
+func (s sessionSettings) Set(userId int, settings common.Settings) {
+	s.pick(userId).Set(userId, settings)
+}
+
+type sessionDiary struct{ guestSplit[common.DiaryStore] }
+
+func newSessionDiary(shared common.DiaryStore) sessionDiary {
+	return sessionDiary{guestSplit[common.DiaryStore]{shared, common.NewMemoryDiary()}}
+}
+
+func (d sessionDiary) Entries(userId int) []common.DiaryEntry {
+	return d.pick(userId).Entries(userId)
+}
+
+func (d sessionDiary) Add(userId int, entry common.DiaryEntry) common.DiaryEntry {
+	return d.pick(userId).Add(userId, entry)
+}
+
+func (d sessionDiary) Update(userId int, entry common.DiaryEntry) error {
+	return d.pick(userId).Update(userId, entry)
+}
+
+func (d sessionDiary) Delete(userId int, entryId int) error {
+	return d.pick(userId).Delete(userId, entryId)
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// readJSONFile leaves v untouched if path doesn't exist yet
+func readJSONFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// writeJSONFile writes then renames, so a crash never leaves a half written file
+func writeJSONFile(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// A common.Memory* store
+type memoryStore interface {
+	json.Marshaler
+	json.Unmarshaler
+	OnChange(f func())
+}
+
+// Changes this close together are saved in one write
+const writeDelay = time.Second
+
+// jsonFile saves a store to path a little after it changes, off the session goroutines
+type jsonFile struct {
+	path  string
+	name  string
+	store json.Marshaler
+
+	mtx     sync.Mutex // Guards dirty, timer and stopped
+	dirty   bool
+	timer   *time.Timer
+	stopped bool
+
+	writeMtx sync.Mutex // Serializes writes to path
+}
+
+func (f *jsonFile) changed() {
+	f.mtx.Lock()
+	f.dirty = true
+	if f.stopped {
+		// Nothing is queued after stop, so save right away
+		f.mtx.Unlock()
+		f.write()
+		return
+	}
+	if f.timer == nil {
+		f.timer = time.AfterFunc(writeDelay, f.write)
+	}
+	f.mtx.Unlock()
+}
+
+func (f *jsonFile) write() {
+	f.writeMtx.Lock()
+	defer f.writeMtx.Unlock()
+
+	f.mtx.Lock()
+	dirty := f.dirty
+	f.dirty = false
+	f.timer = nil
+	f.mtx.Unlock()
+	if !dirty {
+		return
+	}
+
+	if err := writeJSONFile(f.path, f.store); err != nil {
+		log.Error("could not write "+f.name, "path", f.path, "err", err)
+	}
+}
+
+// stop saves whatever is waiting, or waits for a write already under way.
+// Changes after that are saved as they happen.
+func (f *jsonFile) stop() {
+	f.mtx.Lock()
+	f.stopped = true
+	if f.timer != nil {
+		f.timer.Stop()
+	}
+	f.mtx.Unlock()
+	f.write()
+}
+
+// The files persist is saving to, so shutdown can save what's left
+type jsonFiles []*jsonFile
+
+func (files jsonFiles) stop() {
+	for _, f := range files {
+		f.stop()
+	}
+}
+
+// persist loads store from path, then writes it back in the background after changes,
+// so a busy store doesn't rewrite its file on every keypress
+func persist[T memoryStore](files *jsonFiles, store T, path, name string) T {
+	if err := readJSONFile(path, store); err != nil {
+		log.Error("could not read "+name, "path", path, "err", err)
+	}
+
+	f := &jsonFile{path: path, name: name, store: store}
+	store.OnChange(f.changed)
+	*files = append(*files, f)
+	return store
+}
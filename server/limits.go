@@ -0,0 +1,280 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/muesli/termenv"
+	"github.com/zhengkyl/review-ssh/metrics"
+)
+
+// bucket is a token bucket, refilled at rate tokens per second up to burst
+type bucket struct {
+	mtx    sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	paused time.Time // No tokens until then, eg after a 429
+}
+
+func newBucket(rate float64, burst int) *bucket {
+	return &bucket{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+func (b *bucket) refill(now time.Time) {
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+}
+
+// take uses up a token, or says how long until there's one
+func (b *bucket) take(now time.Time) (bool, time.Duration) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.refill(now)
+	if now.Before(b.paused) {
+		return false, b.paused.Sub(now)
+	}
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// wait blocks until a token is taken or ctx is done
+func (b *bucket) wait(ctx context.Context) error {
+	for {
+		ok, wait := b.take(time.Now())
+		if ok {
+			return nil
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (b *bucket) pause(until time.Time) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if until.After(b.paused) {
+		b.paused = until
+	}
+}
+
+// A bucket that's full again is the same as a new one
+func (b *bucket) idle(now time.Time) bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.refill(now)
+	return b.tokens >= b.burst && !now.Before(b.paused)
+}
+
+// keyedBuckets has a bucket per key, eg per ip
+type keyedBuckets struct {
+	mtx     sync.Mutex
+	rate    float64
+	burst   int
+	buckets map[string]*bucket
+}
+
+// Past this many keys, idle buckets are dropped
+const maxIdleBuckets = 1024
+
+func newKeyedBuckets(rate float64, burst int) *keyedBuckets {
+	return &keyedBuckets{rate: rate, burst: burst, buckets: map[string]*bucket{}}
+}
+
+func (k *keyedBuckets) take(key string, now time.Time) (bool, time.Duration) {
+	k.mtx.Lock()
+	if len(k.buckets) > maxIdleBuckets {
+		for other, b := range k.buckets {
+			if b.idle(now) {
+				delete(k.buckets, other)
+			}
+		}
+	}
+	b, ok := k.buckets[key]
+	if !ok {
+		b = newBucket(k.rate, k.burst)
+		k.buckets[key] = b
+	}
+	k.mtx.Unlock()
+
+	return b.take(now)
+}
+
+// signInLimiter limits attempts to sign in from each ip and as each email,
+// so passwords can't be guessed quickly from one place or spread across many
+type signInLimiter struct {
+	byIP    *keyedBuckets
+	byEmail *keyedBuckets
+}
+
+func newSignInLimiter() *signInLimiter {
+	return &signInLimiter{
+		byIP:    newKeyedBuckets(1.0/10, 10),
+		byEmail: newKeyedBuckets(1.0/30, 5),
+	}
+}
+
+func (l *signInLimiter) allow(ip, email string) (bool, time.Duration) {
+	now := time.Now()
+	if ok, wait := l.byIP.take(ip, now); !ok {
+		return false, wait
+	}
+	return l.byEmail.take(email, now)
+}
+
+// ipSignIns is the common.SignInLimiter for one ssh session
+type ipSignIns struct {
+	limiter *signInLimiter
+	ip      string
+}
+
+func (s ipSignIns) Allow(email string) (bool, time.Duration) {
+	return s.limiter.allow(s.ip, email)
+}
+
+func remoteIP(addr net.Addr) string {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
+// sessionLimits caps concurrent sessions, in total and from each ip
+type sessionLimits struct {
+	mtx      sync.Mutex
+	maxTotal int
+	maxPerIP int
+	total    int
+	perIP    map[string]int
+}
+
+func newSessionLimits(maxTotal, maxPerIP int) *sessionLimits {
+	return &sessionLimits{maxTotal: maxTotal, maxPerIP: maxPerIP, perIP: map[string]int{}}
+}
+
+// acquire returns the limit that was hit (total or ip) and why, or "" after counting the session. Limits <= 0 are off.
+func (l *sessionLimits) acquire(ip string) (string, string) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.maxTotal > 0 && l.total >= l.maxTotal {
+		return "total", fmt.Sprintf("All %d seats are taken right now.", l.maxTotal)
+	}
+	if l.maxPerIP > 0 && l.perIP[ip] >= l.maxPerIP {
+		return "ip", fmt.Sprintf("You already have %d sessions open from %s. Close one and try again.", l.perIP[ip], ip)
+	}
+	l.total++
+	l.perIP[ip]++
+	return "", ""
+}
+
+func (l *sessionLimits) release(ip string) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.total--
+	if l.perIP[ip]--; l.perIP[ip] <= 0 {
+		delete(l.perIP, ip)
+	}
+}
+
+// limitMiddleware turns away sessions over the limits with busyScreen, unless exempt, eg admins
+func limitMiddleware(limits *sessionLimits, exempt func(ssh.Session) bool) wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			if exempt(s) {
+				next(s)
+				return
+			}
+
+			ip := remoteIP(s.RemoteAddr())
+			if limit, reason := limits.acquire(ip); limit != "" {
+				log.Warn("session refused", "ip", ip, "reason", reason)
+				metrics.SessionsRefused.Inc(limit)
+				busyScreen(s, "review-ssh is busy", reason)
+				return
+			}
+			defer limits.release(ip)
+
+			next(s)
+		}
+	}
+}
+
+// Refused sessions don't count against the limits, so the busy screen only holds them briefly,
+// and only so many at once. Past that they just get the reason.
+const (
+	busyTimeout    = 5 * time.Second
+	maxBusyScreens = 32
+)
+
+var busyScreens atomic.Int32
+
+var busyBoxStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color("#F25D94")).
+	Padding(1, 3).
+	Width(48).
+	Align(lipgloss.Center)
+
+// busyScreen explains why, instead of dropping the connection. Commands just get the reason.
+func busyScreen(s ssh.Session, title, reason string) {
+	pty, _, active := s.Pty()
+	if busyScreens.Add(1) > maxBusyScreens || !active {
+		busyScreens.Add(-1)
+		wish.Fatalln(s, title+":", reason, "Try again in a minute.")
+		return
+	}
+	defer busyScreens.Add(-1)
+
+	renderer := lipgloss.NewRenderer(s, termenv.WithProfile(termenv.TrueColor))
+	heading := renderer.NewStyle().Bold(true).Render(title)
+	box := busyBoxStyle.Copy().Renderer(renderer).Render(heading + "\n\n" + reason + "\n\nTry again in a minute.\n\nPress any key to leave.")
+
+	// Clear the screen, then center the box. Nothing turns \n into \r\n like bubbletea does.
+	screen := renderer.Place(pty.Window.Width, pty.Window.Height, lipgloss.Center, lipgloss.Center, box)
+	wish.WriteString(s, "\x1b[2J\x1b[H"+strings.ReplaceAll(screen, "\n", "\r\n"))
+
+	key := make(chan struct{})
+	go func() {
+		buf := make([]byte, 1)
+		s.Read(buf)
+		close(key)
+	}()
+
+	select {
+	case <-key:
+	case <-time.After(busyTimeout):
+	case <-s.Context().Done():
+	}
+
+	wish.WriteString(s, "\x1b[2J\x1b[H")
+	s.Exit(1)
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/zhengkyl/review-ssh/metrics"
+	"github.com/zhengkyl/review-ssh/ui/common"
+)
+
+var reviewHost = func() string {
+	u, _ := url.Parse(common.ReviewBase)
+	return u.Host
+}()
+
+// Names each upstream for metrics
+func upstream(host string) string {
+	switch host {
+	case tmdbHost:
+		return "tmdb"
+	case "image.tmdb.org":
+		return "images"
+	case reviewHost:
+		return "review-api"
+	}
+	return "other"
+}
+
+// metricsTransport counts and times every request, including each retry
+type metricsTransport struct {
+	base http.RoundTripper
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	name := upstream(req.URL.Host)
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	metrics.RequestSeconds.Observe(time.Since(start).Seconds(), name)
+
+	if err != nil {
+		metrics.Requests.Inc(name, "error")
+	} else {
+		metrics.Requests.Inc(name, strconv.Itoa(resp.StatusCode/100)+"xx")
+	}
+	return resp, err
+}
+
+// sessionMetrics counts sessions let in by limitMiddleware
+func sessionMetrics() wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			kind := "tui"
+			if len(s.Command()) > 0 {
+				kind = "command"
+			}
+
+			metrics.ActiveSessions.Inc(kind)
+			start := time.Now()
+			defer func() {
+				metrics.ActiveSessions.Dec(kind)
+				metrics.SessionSeconds.Observe(time.Since(start).Seconds(), kind)
+			}()
+
+			next(s)
+		}
+	}
+}
+
+// serveMetrics serves /metrics and /healthz on addr. /healthz fails once stopping is set.
+func serveMetrics(addr string, stopping *atomic.Bool) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		if stopping.Load() {
+			http.Error(w, "stopping", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok\n"))
+	})
+
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		log.Info("Serving metrics", "addr", addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("could not serve metrics", "error", err)
+		}
+	}()
+	return srv
+}
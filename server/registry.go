@@ -0,0 +1,220 @@
+package server
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+type registryKey struct{}
+
+// session is one ssh session, as the admin console sees it
+type session struct {
+	registry *registry
+	id       int
+	ssh      ssh.Session
+	ip       string
+	kind     string // tui, or the command being run
+	started  time.Time
+	program  *tea.Program // nil for commands
+
+	// Reported by the TUI, see Report
+	userId int
+	user   string
+	page   string
+	films  int
+
+	kicked string // Why an admin closed it
+}
+
+// Report is the common.Presence for a TUI session
+func (e *session) Report(userId int, user, page string, films int) {
+	e.registry.mtx.Lock()
+	defer e.registry.mtx.Unlock()
+	e.userId, e.user, e.page, e.films = userId, user, page, films
+}
+
+// registry is every open session, so they can be listed, messaged and closed
+type registry struct {
+	mtx         sync.Mutex
+	nextId      int
+	sessions    map[int]*session
+	maintenance string          // Shown to anyone connecting while it isn't ""
+	admins      map[string]bool // By fingerprint
+}
+
+func newRegistry(admins []ssh.PublicKey) *registry {
+	r := &registry{nextId: 1, sessions: map[int]*session{}, admins: map[string]bool{}}
+	for _, key := range admins {
+		r.admins[gossh.FingerprintSHA256(key)] = true
+	}
+	return r
+}
+
+// isAdmin is true if s signed in with an admin key, not just offered one, see provenFingerprint
+func (r *registry) isAdmin(s ssh.Session) bool {
+	fingerprint := provenFingerprint(s)
+	return fingerprint != "" && r.admins[fingerprint]
+}
+
+// middleware registers each session until it ends. During maintenance only admins get in.
+func (r *registry) middleware() wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			r.mtx.Lock()
+			maintenance := r.maintenance
+			r.mtx.Unlock()
+			if maintenance != "" && !r.isAdmin(s) {
+				busyScreen(s, "review-ssh is down for maintenance", maintenance)
+				return
+			}
+
+			e := r.add(s)
+			defer r.remove(e)
+			s.Context().SetValue(registryKey{}, e)
+
+			next(s)
+
+			r.mtx.Lock()
+			kicked := e.kicked
+			r.mtx.Unlock()
+			if kicked != "" {
+				wish.Fatalln(s, "Disconnected by an admin:", kicked)
+			}
+		}
+	}
+}
+
+func (r *registry) add(s ssh.Session) *session {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	kind := "tui"
+	if len(s.Command()) > 0 {
+		kind = s.Command()[0]
+	}
+	e := &session{
+		registry: r,
+		id:       r.nextId,
+		ssh:      s,
+		ip:       remoteIP(s.RemoteAddr()),
+		kind:     kind,
+		started:  time.Now(),
+	}
+	r.nextId++
+	r.sessions[e.id] = e
+	return e
+}
+
+func (r *registry) remove(e *session) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	delete(r.sessions, e.id)
+}
+
+// presence is where the TUI for s reports who's in it
+func (r *registry) presence(s ssh.Session) common.Presence {
+	e, ok := s.Context().Value(registryKey{}).(*session)
+	if !ok {
+		return nil
+	}
+	return e
+}
+
+// setProgram attaches the TUI started for s, so it can be messaged and closed
+func (r *registry) setProgram(s ssh.Session, p *tea.Program) {
+	e, ok := s.Context().Value(registryKey{}).(*session)
+	if !ok {
+		return
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	e.program = p
+}
+
+// list copies every session, oldest first
+func (r *registry) list() []session {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	list := make([]session, 0, len(r.sessions))
+	for _, e := range r.sessions {
+		list = append(list, *e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].id < list[j].id })
+	return list
+}
+
+func (r *registry) len() int {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return len(r.sessions)
+}
+
+// send delivers msg to every TUI without waiting on any of them
+func (r *registry) send(msg tea.Msg) int {
+	sent := 0
+	for _, e := range r.list() {
+		if e.program != nil {
+			// Send blocks until the program reads it
+			go e.program.Send(msg)
+			sent++
+		}
+	}
+	return sent
+}
+
+// kick closes session id, telling its user why. It's false if there's no such session.
+func (r *registry) kick(id int, reason string) bool {
+	r.mtx.Lock()
+	e, ok := r.sessions[id]
+	if ok {
+		e.kicked = reason
+	}
+	r.mtx.Unlock()
+	if !ok {
+		return false
+	}
+
+	r.mtx.Lock()
+	program := e.program
+	r.mtx.Unlock()
+	if program != nil {
+		// Quit waits for the program to read it
+		go program.Quit()
+	} else {
+		e.ssh.Close()
+	}
+	return true
+}
+
+// setMaintenance turns maintenance mode on with a message for new connections, or off with ""
+func (r *registry) setMaintenance(message string) {
+	r.mtx.Lock()
+	r.maintenance = message
+	r.mtx.Unlock()
+}
+
+// shutdown warns every program, waits out notice unless everyone leaves sooner, then quits the rest
+func (r *registry) shutdown(notice time.Duration) {
+	at := time.Now().Add(notice)
+	r.send(common.ShuttingDown{At: at})
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for r.len() > 0 && time.Now().Before(at) {
+		<-ticker.C
+	}
+
+	for _, e := range r.list() {
+		if e.program != nil {
+			e.program.Quit()
+		}
+	}
+}
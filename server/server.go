@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -15,7 +17,6 @@ import (
 	"github.com/charmbracelet/wish"
 	bm "github.com/charmbracelet/wish/bubbletea"
 	lm "github.com/charmbracelet/wish/logging"
-	"github.com/hashicorp/go-retryablehttp"
 	"github.com/muesli/termenv"
 	"github.com/zhengkyl/review-ssh/ui"
 	"github.com/zhengkyl/review-ssh/ui/common"
@@ -27,13 +28,74 @@ const (
 	port = 3456
 )
 
-func RunServer(tmdbKey string) {
+type Config struct {
+	TMDBKey      string
+	HistoryPath  string
+	SettingsPath string
+	GroupsPath   string
+	DiaryPath    string
+	SessionsPath string
+	// Base64 AES-256 key for SessionsPath. If empty, one is kept at SessionKeyPath.
+	SessionKey     string
+	SessionKeyPath string
+	// Concurrent ssh sessions, 0 for no limit
+	MaxSessions      int
+	MaxSessionsPerIP int
+	IdleTimeout      time.Duration
+	// How long sessions are warned before the server stops
+	ShutdownNotice time.Duration
+	// Where to serve /metrics and /healthz, like :9091. Empty for nowhere.
+	MetricsAddr string
+	// File for the audit log, rotated past AuditLogMaxBytes. Empty for stdout.
+	AuditLogPath     string
+	AuditLogMaxBytes int64
+	// authorized_keys file of who can run "ssh <host> admin"
+	AdminKeysPath string
+}
+
+// Everything kept across sessions
+type shared struct {
+	history  *common.MemoryHistory
+	settings *common.MemorySettings
+	groups   *common.MemoryGroups // Shared by every session, guests can't use groups
+	diary    *common.MemoryDiary
+	sessions *sessionStore
+	signIns  *signInLimiter
+	outbox   *common.Outbox
+	registry *registry
+	audit    *log.Logger
+}
+
+func RunServer(config Config) {
+	sessionKey, err := loadSessionKey(config.SessionKey, config.SessionKeyPath)
+	if err != nil {
+		log.Error("could not load session key", "err", err)
+	}
+
+	var files jsonFiles
+	sh := &shared{
+		history:  persist(&files, common.NewMemoryHistory(), config.HistoryPath, "search history"),
+		settings: persist(&files, common.NewMemorySettings(), config.SettingsPath, "settings"),
+		groups:   persist(&files, common.NewMemoryGroups(), config.GroupsPath, "groups"),
+		diary:    persist(&files, common.NewMemoryDiary(), config.DiaryPath, "diary"),
+		sessions: newSessionStore(config.SessionsPath, sessionKey),
+		signIns:  newSignInLimiter(),
+		outbox:   &common.Outbox{},
+		registry: newRegistry(loadAdminKeys(config.AdminKeysPath)),
+		audit:    newAuditLogger(config.AuditLogPath, config.AuditLogMaxBytes),
+	}
 
 	s, err := wish.NewServer(
 		wish.WithAddress(fmt.Sprintf("%s:%d", host, port)),
 		wish.WithHostKeyPath(".ssh/server_ed25519"),
+		wish.WithPublicKeyAuth(publicKeyHandler),
+		wish.WithKeyboardInteractiveAuth(keyboardInteractiveHandler(sh.signIns, sh.audit)),
 		wish.WithMiddleware(
-			bm.MiddlewareWithColorProfile(makeTeaHandler(tmdbKey), termenv.TrueColor),
+			bm.MiddlewareWithProgramHandler(makeProgramHandler(config, sh), termenv.TrueColor),
+			commandMiddleware(config.TMDBKey, sh),
+			sh.registry.middleware(),
+			sessionMetrics(),
+			limitMiddleware(newSessionLimits(config.MaxSessions, config.MaxSessionsPerIP), sh.registry.isAdmin),
 			lm.Middleware(),
 		),
 	)
@@ -42,6 +104,12 @@ func RunServer(tmdbKey string) {
 		log.Error("server didn't start", "err", err)
 	}
 
+	var stopping atomic.Bool
+	var metricsServer *http.Server
+	if config.MetricsAddr != "" {
+		metricsServer = serveMetrics(config.MetricsAddr, &stopping)
+	}
+
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
@@ -54,26 +122,43 @@ func RunServer(tmdbKey string) {
 	}()
 
 	<-done
-	log.Info("Stopping SSH server")
+	log.Info("Stopping SSH server", "sessions", sh.registry.len())
+	stopping.Store(true)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), config.ShutdownNotice+30*time.Second)
 	defer cancel()
 
-	if err := s.Shutdown(ctx); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
+	// Stop taking connections, but give everyone connected time to finish
+	stopped := make(chan error, 1)
+	go func() { stopped <- s.Shutdown(ctx) }()
+	sh.registry.shutdown(config.ShutdownNotice)
+
+	if err := <-stopped; err != nil && !errors.Is(err, ssh.ErrServerClosed) {
 		log.Error("could not stop server", "error", err)
 	}
+
+	// Sessions can end before their last review saves
+	if err := sh.outbox.Flush(ctx); err != nil {
+		log.Error("writes were still pending", "pending", sh.outbox.Pending(), "error", err)
+	}
+
+	// Save what the stores haven't written yet
+	files.stop()
+
+	if metricsServer != nil {
+		metricsServer.Shutdown(ctx)
+	}
 }
 
-func makeTeaHandler(tmdbKey string) bm.Handler {
-	return func(s ssh.Session) (tea.Model, []tea.ProgramOption) {
+func makeProgramHandler(config Config, sh *shared) bm.ProgramHandler {
+	return func(s ssh.Session) *tea.Program {
 		_, _, active := s.Pty()
 		if !active {
 			wish.Fatalln(s, "no active terminal, skipping")
-			return nil, nil
+			return nil
 		}
 
-		httpClient := retryablehttp.NewClient()
-		httpClient.Logger = nil
+		httpClient := newHttpClient()
 
 		c := common.Props{
 			Global: common.Global{
@@ -81,16 +166,36 @@ func makeTeaHandler(tmdbKey string) bm.Handler {
 					Authed: false,
 				},
 				Config: common.Config{
-					TMDB_API_KEY: tmdbKey,
+					TMDB_API_KEY: config.TMDBKey,
+					IdleTimeout:  config.IdleTimeout,
+					Location:     sessionLocation(s),
 				},
 
 				ReviewMap:  map[int]common.Review{},
 				FilmCache:  common.Cache[common.Film]{},
+				GenreMap:   map[int]string{},
 				KeyMap:     keymap.DefaultKeyMap(),
 				HttpClient: httpClient,
+
+				ProviderCache: common.Cache[common.WatchProviders]{},
+				UserCache:     common.Cache[common.User]{},
+
+				SearchHistory: newSessionHistory(sh.history),
+				Settings:      newSessionSettings(sh.settings),
+				Groups:        sh.groups,
+				Diary:         newSessionDiary(sh.diary),
+				Sessions:      newKeySessions(sh.sessions, provenFingerprint(s)),
+				SignIns:       ipSignIns{sh.signIns, remoteIP(s.RemoteAddr())},
+				Guest:         common.NewGuestReviews(),
+				Outbox:        sh.outbox,
+				Audit:         sessionAudit(sh.audit, s.Context()),
+				Presence:      sh.registry.presence(s),
+				Output:        termenv.NewOutput(s, termenv.WithProfile(termenv.TrueColor)),
 			},
 		}
 
-		return ui.New(c), []tea.ProgramOption{tea.WithAltScreen()}
+		p := tea.NewProgram(ui.New(c), tea.WithAltScreen(), tea.WithInput(s), tea.WithOutput(s))
+		sh.registry.setProgram(s, p)
+		return p
 	}
 }
@@ -0,0 +1,209 @@
+package server
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/zhengkyl/review-ssh/ui/common"
+)
+
+// sessionStore remembers review-api sessions by ssh key fingerprint.
+// Each session is sealed with AES-GCM, so the file is useless without the key.
+type sessionStore struct {
+	mtx    sync.Mutex
+	path   string
+	aead   cipher.AEAD
+	sealed map[string]string // fingerprint -> base64 nonce + ciphertext
+}
+
+// loadSessionKey decodes key (base64, 32 bytes) or, if it's empty, reads or creates one at path.
+func loadSessionKey(key, path string) ([]byte, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			raw := make([]byte, 32)
+			if _, err := rand.Read(raw); err != nil {
+				return nil, err
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+				return nil, err
+			}
+			key = base64.StdEncoding.EncodeToString(raw)
+			if err := os.WriteFile(path, []byte(key), 0o600); err != nil {
+				return nil, err
+			}
+			log.Info("created session key", "path", path)
+		case err != nil:
+			return nil, err
+		default:
+			// Key files usually end in a newline, like from openssl rand -base64 32 > file
+			key = strings.TrimSpace(string(data))
+		}
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("session key isn't base64: %w", err)
+	}
+	if len(raw) != 32 {
+		return nil, fmt.Errorf("session key is %d bytes, want 32", len(raw))
+	}
+	return raw, nil
+}
+
+// Without a usable key, nothing is remembered
+func newSessionStore(path string, key []byte) *sessionStore {
+	s := &sessionStore{
+		path:   path,
+		sealed: map[string]string{},
+	}
+
+	block, err := aes.NewCipher(key)
+	if err == nil {
+		s.aead, err = cipher.NewGCM(block)
+	}
+	if err != nil {
+		log.Error("sessions won't be remembered", "err", err)
+		return s
+	}
+
+	if err := readJSONFile(path, &s.sealed); err != nil {
+		log.Error("could not read sessions", "path", path, "err", err)
+	}
+	return s
+}
+
+func (s *sessionStore) persist() {
+	if err := writeJSONFile(s.path, s.sealed); err != nil {
+		log.Error("could not write sessions", "path", s.path, "err", err)
+	}
+}
+
+// The fingerprint is authenticated too, so a session can't be moved to another key
+func (s *sessionStore) seal(fingerprint string, auth common.AuthState) (string, error) {
+	plain, err := json.Marshal(auth)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(s.aead.Seal(nonce, nonce, plain, []byte(fingerprint))), nil
+}
+
+func (s *sessionStore) open(fingerprint, sealed string) (common.AuthState, error) {
+	var auth common.AuthState
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return auth, err
+	}
+	if len(data) < s.aead.NonceSize() {
+		return auth, errors.New("sealed session too short")
+	}
+	nonce, ciphertext := data[:s.aead.NonceSize()], data[s.aead.NonceSize():]
+	plain, err := s.aead.Open(nil, nonce, ciphertext, []byte(fingerprint))
+	if err != nil {
+		return auth, err
+	}
+	err = json.Unmarshal(plain, &auth)
+	return auth, err
+}
+
+func (s *sessionStore) Get(fingerprint string) (common.AuthState, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	sealed, ok := s.sealed[fingerprint]
+	if !ok || s.aead == nil {
+		return common.AuthState{}, false
+	}
+
+	auth, err := s.open(fingerprint, sealed)
+	if err != nil || auth.Expired(time.Now()) {
+		// Expired, or sealed with a key that's since changed
+		delete(s.sealed, fingerprint)
+		s.persist()
+		return common.AuthState{}, false
+	}
+	return auth, true
+}
+
+func (s *sessionStore) Set(fingerprint string, auth common.AuthState) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.aead == nil {
+		return
+	}
+	sealed, err := s.seal(fingerprint, auth)
+	if err != nil {
+		log.Error("could not seal session", "err", err)
+		return
+	}
+	s.sealed[fingerprint] = sealed
+	s.persist()
+}
+
+func (s *sessionStore) Delete(fingerprint string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, ok := s.sealed[fingerprint]; ok {
+		delete(s.sealed, fingerprint)
+		s.persist()
+	}
+}
+
+// keySessions is the common.SessionStore for one ssh connection.
+// Connections without a public key have no fingerprint and can't be remembered.
+type keySessions struct {
+	store       *sessionStore
+	fingerprint string
+}
+
+// fingerprint is from provenFingerprint, anyone could claim a key they don't hold
+func newKeySessions(store *sessionStore, fingerprint string) *keySessions {
+	k := &keySessions{store: store}
+	if store.aead != nil {
+		k.fingerprint = fingerprint
+	}
+	return k
+}
+
+func (k *keySessions) CanRemember() bool {
+	return k.fingerprint != ""
+}
+
+func (k *keySessions) Remembered() (common.AuthState, bool) {
+	if !k.CanRemember() {
+		return common.AuthState{}, false
+	}
+	return k.store.Get(k.fingerprint)
+}
+
+// Guests have nothing worth remembering
+func (k *keySessions) Remember(auth common.AuthState) {
+	if k.CanRemember() && auth.User.Id != common.GuestAuthState.User.Id {
+		k.store.Set(k.fingerprint, auth)
+	}
+}
+
+func (k *keySessions) Forget() {
+	if k.CanRemember() {
+		k.store.Delete(k.fingerprint)
+	}
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"strings"
+	"time"
+	_ "time/tzdata" // The alpine image has no zoneinfo
+
+	"github.com/charmbracelet/ssh"
+)
+
+// sessionLocation is the time zone in the client's TZ, if it sent one (SendEnv TZ).
+// It's nil otherwise, or if TZ isn't a zone name like "America/New_York".
+func sessionLocation(s ssh.Session) *time.Location {
+	for _, env := range s.Environ() {
+		name, value, _ := strings.Cut(env, "=")
+		if name != "TZ" {
+			continue
+		}
+		if value == "" {
+			return nil
+		}
+		loc, err := time.LoadLocation(strings.TrimPrefix(value, ":"))
+		if err != nil {
+			return nil
+		}
+		return loc
+	}
+	return nil
+}
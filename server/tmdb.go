@@ -0,0 +1,54 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// TMDB allows around 50 requests a second per ip, and every session shares ours
+const (
+	tmdbHost  = "api.themoviedb.org"
+	tmdbRate  = 40
+	tmdbBurst = 40
+)
+
+// Without a Retry-After, a 429 stops TMDB requests for this long
+const tmdbBackoff = 2 * time.Second
+
+var tmdbBucket = newBucket(tmdbRate, tmdbBurst)
+
+// tmdbTransport makes every session's TMDB requests wait on one bucket.
+// A 429 pauses the bucket for everyone, then retryablehttp retries as usual.
+type tmdbTransport struct {
+	base   http.RoundTripper
+	bucket *bucket
+}
+
+func (t *tmdbTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != tmdbHost {
+		return t.base.RoundTrip(req)
+	}
+
+	if err := t.bucket.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		t.bucket.pause(time.Now().Add(retryAfter(resp.Header.Get("Retry-After"))))
+	}
+	return resp, err
+}
+
+// Retry-After is either seconds or a date
+func retryAfter(header string) time.Duration {
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+	return tmdbBackoff
+}
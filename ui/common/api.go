@@ -1,11 +1,13 @@
 package common
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -19,16 +21,164 @@ const ReviewBase = "https://review-api.fly.dev"
 type User struct {
 	Id         int       `json:"id"`
 	Name       string    `json:"name"`
+	Email      string    `json:"email,omitempty"` // Only known for the signed in user
 	Created_at time.Time `json:"created_at"`
 	Updated_at time.Time `json:"updated_at"`
 }
 
 type Film struct {
+	Id                int
+	Title             string
+	Overview          string
+	Poster_path       string
+	Release_date      string
+	Genre_ids         []int
+	Original_language string
+	Popularity        float64
+	Vote_average      float64
+	Adult             bool
+
+	// Only in /movie/{id}, search results leave these empty. See HasDetails()
+	Runtime               int
+	Genres                []Genre
+	Tagline               string
+	Original_title        string
+	Vote_count            int
+	Production_countries  []Country
+	Spoken_languages      []Language
+	Budget                int64
+	Revenue               int64
+	Belongs_to_collection *CollectionRef
+	Imdb_id               string
+}
+
+// Whether film came from /movie/{id} rather than a search or list
+func (f Film) HasDetails() bool {
+	return f.Genres != nil || f.Runtime != 0 || f.Imdb_id != ""
+}
+
+type Country struct {
+	Iso_3166_1 string
+	Name       string
+}
+
+type Language struct {
+	Iso_639_1    string
+	English_name string
+}
+
+type CollectionRef struct {
+	Id          int
+	Name        string
+	Poster_path string
+}
+
+// Response of TMDB /collection/{id}
+type Collection struct {
+	Id    int
+	Name  string
+	Parts []Film
+}
+
+type Provider struct {
+	Provider_id      int
+	Provider_name    string
+	Logo_path        string
+	Display_priority int
+}
+
+// How one film can be watched in one region
+type RegionProviders struct {
+	Link     string
+	Flatrate []Provider // Subscription streaming
+	Free     []Provider
+	Ads      []Provider
+	Rent     []Provider
+	Buy      []Provider
+}
+
+// Included means watchable at no extra cost, given the right subscription
+func (r RegionProviders) Included() []Provider {
+	return append(append(append([]Provider{}, r.Flatrate...), r.Free...), r.Ads...)
+}
+
+// Every region at once, keyed by ISO 3166-1 code
+type WatchProviders struct {
+	Id      int
+	Results map[string]RegionProviders
+}
+
+type ProviderList struct {
+	Results []Provider
+}
+
+type Region struct {
+	Iso_3166_1   string
+	English_name string
+}
+
+type RegionList struct {
+	Results []Region
+}
+
+type Genre struct {
+	Id   int
+	Name string
+}
+
+type GenreList struct {
+	Genres []Genre
+}
+
+// Response of TMDB /find/{external_id}
+type FindResults struct {
+	Movie_results []Film
+}
+
+// Response of TMDB /movie/{id}/credits
+type Credits struct {
+	Cast []CastCredit
+	Crew []CrewCredit
+}
+
+type CastCredit struct {
 	Id           int
-	Title        string
-	Overview     string
-	Poster_path  string
-	Release_date string
+	Name         string
+	Character    string
+	Order        int
+	Profile_path string
+}
+
+type CrewCredit struct {
+	Id           int
+	Name         string
+	Job          string
+	Department   string
+	Profile_path string
+}
+
+type Person struct {
+	Id                   int
+	Name                 string
+	Biography            string
+	Birthday             string
+	Deathday             string
+	Place_of_birth       string
+	Profile_path         string
+	Known_for_department string
+}
+
+// One role in a person's filmography, the film's fields are inlined by TMDB
+type FilmCredit struct {
+	Film
+	Character string // Cast only
+	Job       string // Crew only
+}
+
+// Response of TMDB /person/{id}/movie_credits
+type PersonCredits struct {
+	Cast []FilmCredit
+	Crew []FilmCredit
 }
 
 type Review struct {
@@ -69,7 +219,7 @@ func (a ByStatusAndUpdate) Less(i, j int) bool {
 	}
 
 	if a[i].Updated_at.Equal(a[j].Updated_at) {
-		return a[i].Created_at.After(a[j].Updated_at)
+		return a[i].Created_at.After(a[j].Created_at)
 	}
 	return a[i].Updated_at.After(a[j].Updated_at)
 }
@@ -82,7 +232,7 @@ type Paged[T Review | Film] struct {
 }
 
 type responseData interface {
-	Film | Review | Paged[Film] | Paged[Review] | struct{}
+	Film | Review | Paged[Film] | Paged[Review] | GenreList | FindResults | Credits | Person | PersonCredits | Collection | WatchProviders | ProviderList | RegionList | User | struct{}
 }
 
 type fetchCallback[T responseData] func(data T, err error) tea.Msg
@@ -93,56 +243,118 @@ func Get[T responseData](g Global, url string, callback fetchCallback[T]) tea.Cm
 }
 
 func Fetch[T responseData](g Global, method string, url string, body map[string]interface{}, callback fetchCallback[T]) tea.Cmd {
+	return FetchWithContext[T](context.Background(), g, method, url, body, callback)
+}
+
+// Canceling ctx aborts the request, and callback receives the context error
+// A 401 from review-api also sends the user to sign in again, after callback has run.
+// TMDB's 401s are about the API key, not the user.
+func FetchWithContext[T responseData](ctx context.Context, g Global, method string, url string, body map[string]interface{}, callback fetchCallback[T]) tea.Cmd {
+	return func() tea.Msg {
+		data, err := Do[T](ctx, g, method, url, body)
+		return func() tea.Msg {
+			msg := callback(data, err)
+			if !errors.Is(err, StatusError(http.StatusUnauthorized)) || !strings.HasPrefix(url, ReviewBase) ||
+				g.AuthState.User.Id == GuestAuthState.User.Id {
+				return msg
+			}
+
+			signIn := tea.Cmd(func() tea.Msg { return Unauthorized{} })
+			switch msg := msg.(type) {
+			case nil:
+				return signIn
+			case tea.Cmd:
+				return tea.Batch(msg, signIn)
+			default:
+				return tea.Batch(func() tea.Msg { return msg }, signIn)
+			}
+		}
+	}
+}
+
+// A non 2xx response. It prints as just the code, like "404".
+type StatusError int
+
+func (e StatusError) Error() string {
+	return fmt.Sprint(int(e))
+}
+
+// Do is the blocking version of Fetch, for use outside of tea.Cmds. Changes are audited, see WithAudit.
+func Do[T responseData](ctx context.Context, g Global, method string, url string, body map[string]interface{}) (T, error) {
+	data, err := do[T](ctx, g, method, url, body)
+	if method != "GET" {
+		auditRequest(ctx, g, method, url, body, data, err)
+	}
+	return data, err
+}
+
+func do[T responseData](ctx context.Context, g Global, method string, url string, body map[string]interface{}) (T, error) {
+	var data T
+
+	if g.Guest != nil && g.AuthState.User.Id == GuestAuthState.User.Id {
+		if res, ok, err := g.Guest.serve(method, url, body); ok {
+			if err != nil {
+				return data, err
+			}
+			data, _ = res.(T)
+			return data, nil
+		}
+	}
+
+	if method != "GET" {
+		defer g.Outbox.start()()
+	}
 
 	var rawbody []byte
 	var err error
 	if body != nil {
 		rawbody, err = json.Marshal(body)
 		if err != nil {
-			return nil
+			return data, err
 		}
 	}
 
-	req, err := retryablehttp.NewRequest(method, url, rawbody)
+	req, err := retryablehttp.NewRequestWithContext(ctx, method, url, rawbody)
+	if err != nil {
+		return data, err
+	}
+
 	req.AddCookie(&http.Cookie{Name: "id", Value: g.AuthState.Cookie})
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
+	resp, err := g.HttpClient.Do(req)
 	if err != nil {
-		return nil
+		return data, err
 	}
+	defer resp.Body.Close()
 
-	return func() tea.Msg {
-		var data T
-
-		resp, err := g.HttpClient.Do(req)
-
-		if err != nil {
-			return func() tea.Msg { return callback(data, err) }
-		}
-
-		if resp.StatusCode < 200 || resp.StatusCode > 299 {
-			return func() tea.Msg { return callback(data, errors.New(fmt.Sprint(resp.StatusCode))) }
-		}
-
-		if resp.StatusCode != 204 {
-			err = json.NewDecoder(resp.Body).Decode(&data)
-		}
-
-		if err != nil {
-			return func() tea.Msg { return callback(data, err) }
-		}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return data, StatusError(resp.StatusCode)
+	}
 
-		return func() tea.Msg { return callback(data, nil) }
+	if resp.StatusCode != 204 {
+		err = json.NewDecoder(resp.Body).Decode(&data)
 	}
+
+	return data, err
 }
 
 const filmEndpoint = "https://api.themoviedb.org/3/movie/"
 
+// FilmURL is a TMDB film endpoint, like "/credits", or "" for the film itself
+func FilmURL(g Global, filmId int, suffix string) string {
+	return filmEndpoint + strconv.Itoa(filmId) + suffix + "?api_key=" + g.Config.TMDB_API_KEY
+}
+
+// Fetches into FilmCache unless it's already there or loading
 func GetFilmCmd(g Global, filmId int) tea.Cmd {
+	if !g.FilmCache.needsFetch("film", filmId) {
+		return nil
+	}
 	g.FilmCache.SetLoading(filmId)
-	url := (filmEndpoint + strconv.Itoa(filmId) + "?api_key=" + g.Config.TMDB_API_KEY)
+	url := FilmURL(g, filmId, "")
 	return Get[Film](g, url, func(data Film, err error) tea.Msg {
 		if err != nil {
 			g.FilmCache.Delete(filmId)
@@ -153,11 +365,240 @@ func GetFilmCmd(g Global, filmId int) tea.Cmd {
 	})
 }
 
+func GetCreditsCmd(g Global, filmId int, callback fetchCallback[Credits]) tea.Cmd {
+	url := FilmURL(g, filmId, "/credits")
+	return Get[Credits](g, url, callback)
+}
+
+// TMDB's recommendations are based on what other users watched
+func RecommendationsURL(g Global, filmId int) string {
+	return FilmURL(g, filmId, "/recommendations")
+}
+
+func GetRecommendationsCmd(g Global, filmId int, callback fetchCallback[Paged[Film]]) tea.Cmd {
+	return Get[Paged[Film]](g, RecommendationsURL(g, filmId), callback)
+}
+
+// Similar films share genres and keywords
+func GetSimilarCmd(g Global, filmId int, callback fetchCallback[Paged[Film]]) tea.Cmd {
+	url := FilmURL(g, filmId, "/similar")
+	return Get[Paged[Film]](g, url, callback)
+}
+
+// Results are cached for every region, failures are cached as unavailable everywhere
+func GetWatchProvidersCmd(g Global, filmId int) tea.Cmd {
+	if !g.ProviderCache.needsFetch("providers", filmId) {
+		return nil
+	}
+	g.ProviderCache.SetLoading(filmId)
+	url := FilmURL(g, filmId, "/watch/providers")
+	return Get[WatchProviders](g, url, func(data WatchProviders, err error) tea.Msg {
+		if err != nil {
+			data = WatchProviders{Id: filmId}
+		}
+		g.ProviderCache.Set(filmId, data)
+		return nil
+	})
+}
+
+const watchProvidersEndpoint = "https://api.themoviedb.org/3/watch/providers/"
+
+// Services available in region, most popular first
+func GetProviderListCmd(g Global, region string, callback fetchCallback[ProviderList]) tea.Cmd {
+	url := watchProvidersEndpoint + "movie?watch_region=" + region + "&api_key=" + g.Config.TMDB_API_KEY
+	return Get[ProviderList](g, url, callback)
+}
+
+func GetRegionListCmd(g Global, callback fetchCallback[RegionList]) tea.Cmd {
+	url := watchProvidersEndpoint + "regions?api_key=" + g.Config.TMDB_API_KEY
+	return Get[RegionList](g, url, callback)
+}
+
+func GetCollectionCmd(g Global, collectionId int, callback fetchCallback[Collection]) tea.Cmd {
+	url := "https://api.themoviedb.org/3/collection/" + strconv.Itoa(collectionId) + "?api_key=" + g.Config.TMDB_API_KEY
+	return Get[Collection](g, url, callback)
+}
+
+const personEndpoint = "https://api.themoviedb.org/3/person/"
+
+func GetPersonCmd(g Global, personId int, callback fetchCallback[Person]) tea.Cmd {
+	url := personEndpoint + strconv.Itoa(personId) + "?api_key=" + g.Config.TMDB_API_KEY
+	return Get[Person](g, url, callback)
+}
+
+func GetPersonCreditsCmd(g Global, personId int, callback fetchCallback[PersonCredits]) tea.Cmd {
+	url := personEndpoint + strconv.Itoa(personId) + "/movie_credits?api_key=" + g.Config.TMDB_API_KEY
+	return Get[PersonCredits](g, url, callback)
+}
+
 const filmReviewEndpoint = ReviewBase + "/reviews?category=Film"
 
 func GetMyFilmReviewCmd(g Global, filmId int, callback fetchCallback[Paged[Review]]) tea.Cmd {
+	return GetUserFilmReviewCmd(g, g.AuthState.User.Id, filmId, callback)
+}
+
+func GetUserFilmReviewCmd(g Global, userId int, filmId int, callback fetchCallback[Paged[Review]]) tea.Cmd {
 	url := filmReviewEndpoint +
 		"&tmdb_id=" + strconv.Itoa(filmId) +
-		"&user_id=" + strconv.Itoa(g.AuthState.User.Id)
+		"&user_id=" + strconv.Itoa(userId)
 	return Get[Paged[Review]](g, url, callback)
 }
+
+const userEndpoint = ReviewBase + "/users/"
+
+// Users that can't be fetched are cached with a placeholder name
+func GetUserCmd(g Global, userId int) tea.Cmd {
+	if !g.UserCache.needsFetch("user", userId) {
+		return nil
+	}
+	g.UserCache.SetLoading(userId)
+	return Get[User](g, userEndpoint+strconv.Itoa(userId), func(data User, err error) tea.Msg {
+		if err != nil || data.Name == "" {
+			data = User{Id: userId, Name: "User #" + strconv.Itoa(userId)}
+		}
+		g.UserCache.Set(userId, data)
+		return nil
+	})
+}
+
+// Changes to the signed in user keep AuthState in sync. Email and password changes should be re-authed first.
+func PatchUserCmd(g Global, updates map[string]interface{}, callback func(err error) tea.Msg) tea.Cmd {
+	url := userEndpoint + strconv.Itoa(g.AuthState.User.Id)
+	return Fetch[User](g, "PATCH", url, updates, func(data User, err error) tea.Msg {
+		if err == nil {
+			if data.Email == "" {
+				data.Email = g.AuthState.User.Email
+				if email, ok := updates["email"].(string); ok {
+					data.Email = email
+				}
+			}
+			g.AuthState.User = data
+			g.UserCache.Set(data.Id, data)
+		}
+		return callback(err)
+	})
+}
+
+// This also deletes the user's reviews on review-api
+func DeleteUserCmd(g Global, callback func(err error) tea.Msg) tea.Cmd {
+	url := userEndpoint + strconv.Itoa(g.AuthState.User.Id)
+	return Fetch[struct{}](g, "DELETE", url, nil, func(data struct{}, err error) tea.Msg {
+		return callback(err)
+	})
+}
+
+const authEndpoint = ReviewBase + "/auth"
+
+// SignOutCmd ends the session on review-api, then locally even if that failed
+func SignOutCmd(g Global) tea.Cmd {
+	return Fetch[struct{}](g, "DELETE", authEndpoint, nil, func(data struct{}, err error) tea.Msg {
+		return tea.Cmd(func() tea.Msg { return SignOut{} })
+	})
+}
+
+const genreEndpoint = "https://api.themoviedb.org/3/genre/movie/list"
+
+func GetGenresCmd(g Global, callback func() tea.Msg) tea.Cmd {
+	url := genreEndpoint + "?api_key=" + g.Config.TMDB_API_KEY
+	return Get[GenreList](g, url, func(data GenreList, err error) tea.Msg {
+		if err == nil {
+			for _, genre := range data.Genres {
+				g.GenreMap[genre.Id] = genre.Name
+			}
+		}
+		return callback()
+	})
+}
+
+const reviewEndpoint = ReviewBase + "/reviews"
+
+// FetchAllReviews gets every film review for userId, across all pages. This blocks.
+func FetchAllReviews(ctx context.Context, g Global, userId int) (map[int]Review, error) {
+	return FetchReviews(ctx, g, userId, 0)
+}
+
+// FetchReviews is FetchAllReviews, but stops after maxPages pages of 50, unless it's 0
+func FetchReviews(ctx context.Context, g Global, userId int, maxPages int) (map[int]Review, error) {
+	reviews := map[int]Review{}
+
+	for page := 1; ; page++ {
+		url := reviewEndpoint + "?category=Film&per_page=50&page=" + strconv.Itoa(page) + "&user_id=" + strconv.Itoa(userId)
+		data, err := Do[Paged[Review]](ctx, g, "GET", url, nil)
+		if err != nil {
+			return reviews, err
+		}
+		for _, review := range data.Results {
+			reviews[review.Tmdb_id] = review
+		}
+		if page >= data.Total_Pages || len(data.Results) == 0 || page == maxPages {
+			return reviews, nil
+		}
+	}
+}
+
+// Read now, since ReviewMap is only safe to use from Update
+func auditBefore(g Global, tmdbId int) context.Context {
+	if before, ok := g.ReviewMap[tmdbId]; ok {
+		return WithAudit(context.Background(), "before", before)
+	}
+	return context.Background()
+}
+
+func reviewURL(tmdbId int) string {
+	return reviewEndpoint + "/Film/" + strconv.Itoa(tmdbId)
+}
+
+func newReviewBody(tmdbId int, status string) map[string]interface{} {
+	return map[string]interface{}{
+		"tmdb_id":  tmdbId,
+		"category": "Film",
+		"status":   status,
+	}
+}
+
+// PostReview is the blocking version of PostReviewCmd. It leaves ReviewMap to the caller.
+func PostReview(ctx context.Context, g Global, tmdbId int, status string) (Review, error) {
+	return Do[Review](ctx, g, "POST", reviewEndpoint, newReviewBody(tmdbId, status))
+}
+
+// PatchReview is the blocking version of PatchReviewCmd. It leaves ReviewMap to the caller.
+func PatchReview(ctx context.Context, g Global, tmdbId int, updates map[string]interface{}) (Review, error) {
+	return Do[Review](ctx, g, "PATCH", reviewURL(tmdbId), updates)
+}
+
+// Review mutations keep ReviewMap in sync. callback may be nil.
+func PostReviewCmd(g Global, tmdbId int, status string, callback func(err error) tea.Msg) tea.Cmd {
+	return FetchWithContext[Review](auditBefore(g, tmdbId), g, "POST", reviewEndpoint, newReviewBody(tmdbId, status), func(data Review, err error) tea.Msg {
+		if err == nil {
+			g.ReviewMap[tmdbId] = data
+		}
+		if callback == nil {
+			return nil
+		}
+		return callback(err)
+	})
+}
+
+func PatchReviewCmd(g Global, tmdbId int, updates map[string]interface{}, callback func(err error) tea.Msg) tea.Cmd {
+	return FetchWithContext[Review](auditBefore(g, tmdbId), g, "PATCH", reviewURL(tmdbId), updates, func(data Review, err error) tea.Msg {
+		if err == nil {
+			g.ReviewMap[tmdbId] = data
+		}
+		if callback == nil {
+			return nil
+		}
+		return callback(err)
+	})
+}
+
+func DeleteReviewCmd(g Global, tmdbId int, callback func(err error) tea.Msg) tea.Cmd {
+	return FetchWithContext[struct{}](auditBefore(g, tmdbId), g, "DELETE", reviewURL(tmdbId), nil, func(data struct{}, err error) tea.Msg {
+		if err == nil {
+			delete(g.ReviewMap, tmdbId)
+		}
+		if callback == nil {
+			return nil
+		}
+		return callback(err)
+	})
+}
@@ -0,0 +1,110 @@
+package common
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const redacted = "[redacted]"
+
+// Keys whose values never reach the audit log
+func sensitive(key string) bool {
+	key = strings.ToLower(key)
+	return strings.Contains(key, "password") || key == "cookie" || key == "token" || key == "secret"
+}
+
+func redactMap(m map[string]interface{}) map[string]interface{} {
+	clean := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		if sensitive(key) {
+			clean[key] = redacted
+		} else {
+			clean[key] = value
+		}
+	}
+	return clean
+}
+
+// Redact replaces sensitive values in keyvals, including inside request bodies
+func Redact(keyvals []interface{}) []interface{} {
+	clean := make([]interface{}, len(keyvals))
+	copy(clean, keyvals)
+	for i := 0; i+1 < len(clean); i += 2 {
+		if key, ok := clean[i].(string); ok && sensitive(key) {
+			clean[i+1] = redacted
+			continue
+		}
+		if m, ok := clean[i+1].(map[string]interface{}); ok {
+			clean[i+1] = redactMap(m)
+		}
+	}
+	return clean
+}
+
+// Audit records something the user changed, as the signed in user unless keyvals has a user_id.
+// It does nothing without Global.Audit.
+func Audit(g Global, action string, keyvals ...interface{}) {
+	if g.Audit == nil {
+		return
+	}
+	hasUser := false
+	for i := 0; i < len(keyvals); i += 2 {
+		if keyvals[i] == "user_id" {
+			hasUser = true
+		}
+	}
+	if !hasUser && g.AuthState != nil {
+		keyvals = append([]interface{}{"user_id", g.AuthState.User.Id}, keyvals...)
+	}
+	g.Audit.Info(action, Redact(keyvals)...)
+}
+
+type auditKey struct{}
+
+// WithAudit adds keyvals to the audit entries of requests made with ctx, like what a review was before
+func WithAudit(ctx context.Context, keyvals ...interface{}) context.Context {
+	prev, _ := ctx.Value(auditKey{}).([]interface{})
+	return context.WithValue(ctx, auditKey{}, append(append([]interface{}{}, prev...), keyvals...))
+}
+
+// auditRequest logs a mutating request made by Do
+func auditRequest(ctx context.Context, g Global, method, rawURL string, body map[string]interface{}, data interface{}, err error) {
+	u, parseErr := url.Parse(rawURL)
+	if parseErr != nil || !strings.HasPrefix(rawURL, ReviewBase) {
+		return
+	}
+
+	keyvals := []interface{}{"method", method, "path", u.Path}
+	var action string
+	switch {
+	case strings.HasPrefix(u.Path, "/reviews"):
+		action = map[string]string{"POST": "review.create", "PATCH": "review.update", "DELETE": "review.delete"}[method]
+		tmdbId, _ := body["tmdb_id"].(int)
+		if id, convErr := strconv.Atoi(strings.TrimPrefix(u.Path, "/reviews/Film/")); convErr == nil {
+			tmdbId = id
+		}
+		keyvals = append(keyvals, "tmdb_id", tmdbId)
+	case strings.HasPrefix(u.Path, "/users"):
+		action = map[string]string{"PATCH": "user.update", "DELETE": "user.delete"}[method]
+	case u.Path == "/auth" && method == "DELETE":
+		action = "sign_out"
+	}
+	if action == "" {
+		action = "request"
+	}
+
+	if extra, ok := ctx.Value(auditKey{}).([]interface{}); ok {
+		keyvals = append(keyvals, extra...)
+	}
+	if body != nil {
+		keyvals = append(keyvals, "changes", body)
+	}
+	if err != nil {
+		keyvals = append(keyvals, "error", err)
+	} else if review, ok := data.(Review); ok {
+		keyvals = append(keyvals, "after", review)
+	}
+	Audit(g, action, keyvals...)
+}
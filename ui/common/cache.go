@@ -1,7 +1,9 @@
 package common
 
+import "github.com/zhengkyl/review-ssh/metrics"
+
 type Cacheable interface {
-	Film
+	Film | WatchProviders | User
 }
 
 type CacheInfo[T Cacheable] struct {
@@ -13,15 +15,35 @@ type Cache[T Cacheable] map[int]CacheInfo[T]
 
 func (c Cache[T]) Get(id int) (bool, bool, T) {
 	res, exists := c[id]
-
 	if !exists {
 		var t T
 		return false, false, t
 	}
-
 	return !res.Loading, res.Loading, res.Data
 }
 
+// Clear forgets everything, eg when an admin flushes the caches
+func (c Cache[T]) Clear() {
+	for id := range c {
+		delete(c, id)
+	}
+}
+
+// needsFetch counts a lookup under name and is true if id is neither cached nor loading.
+// Only the Get*Cmd helpers call it, so the metric counts fetch decisions, not renders.
+func (c Cache[T]) needsFetch(name string, id int) bool {
+	ok, loading, _ := c.Get(id)
+	switch {
+	case ok:
+		metrics.CacheLookups.Inc(name, "hit")
+	case loading:
+		metrics.CacheLookups.Inc(name, "loading")
+	default:
+		metrics.CacheLookups.Inc(name, "miss")
+	}
+	return !ok && !loading
+}
+
 func (c Cache[T]) Set(id int, data T) {
 	c[id] = CacheInfo[T]{false, data}
 }
@@ -0,0 +1,147 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+)
+
+// Watch dates have no time or zone, they're the day the user says
+const DiaryDateLayout = "2006-01-02"
+
+var ErrNoEntry = errors.New("no such diary entry")
+
+// One watch of a film. Reviews only keep the latest state, so each watch gets its own entry.
+type DiaryEntry struct {
+	Id         int       `json:"id"`
+	Tmdb_id    int       `json:"tmdb_id"`
+	Watched    string    `json:"watched"` // DiaryDateLayout
+	Rewatch    bool      `json:"rewatch"`
+	Note       string    `json:"note"`
+	Fun_during bool      `json:"fun_during"`
+	Fun_after  bool      `json:"fun_after"`
+	Created_at time.Time `json:"created_at"`
+}
+
+// Unparseable dates are the zero time
+func (e DiaryEntry) Date() time.Time {
+	t, _ := time.Parse(DiaryDateLayout, e.Watched)
+	return t
+}
+
+type DiaryStore interface {
+	// Most recently watched first
+	Entries(userId int) []DiaryEntry
+	// Add assigns the entry an id
+	Add(userId int, entry DiaryEntry) DiaryEntry
+	Update(userId int, entry DiaryEntry) error
+	Delete(userId int, entryId int) error
+}
+
+type userDiary struct {
+	NextId  int          `json:"next_id"`
+	Entries []DiaryEntry `json:"entries"`
+}
+
+func (d *userDiary) index(entryId int) int {
+	for i, e := range d.Entries {
+		if e.Id == entryId {
+			return i
+		}
+	}
+	return -1
+}
+
+// MemoryDiary is a DiaryStore kept in memory, see memory
+type MemoryDiary struct {
+	memory
+	users map[int]*userDiary
+}
+
+func NewMemoryDiary() *MemoryDiary {
+	return &MemoryDiary{users: map[int]*userDiary{}}
+}
+
+func (d *MemoryDiary) user(userId int) *userDiary {
+	diary, ok := d.users[userId]
+	if !ok {
+		diary = &userDiary{NextId: 1}
+		d.users[userId] = diary
+	}
+	return diary
+}
+
+// Ties go to the entry logged last
+func sortEntries(entries []DiaryEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Watched != entries[j].Watched {
+			return entries[i].Watched > entries[j].Watched
+		}
+		return entries[i].Id > entries[j].Id
+	})
+}
+
+func (d *MemoryDiary) Entries(userId int) []DiaryEntry {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	entries := append([]DiaryEntry{}, d.user(userId).Entries...)
+	sortEntries(entries)
+	return entries
+}
+
+func (d *MemoryDiary) Add(userId int, entry DiaryEntry) DiaryEntry {
+	d.mtx.Lock()
+	defer d.changed()
+	defer d.mtx.Unlock()
+
+	diary := d.user(userId)
+	entry.Id = diary.NextId
+	if entry.Created_at.IsZero() {
+		entry.Created_at = time.Now()
+	}
+	diary.NextId++
+	diary.Entries = append(diary.Entries, entry)
+	return entry
+}
+
+func (d *MemoryDiary) Update(userId int, entry DiaryEntry) error {
+	d.mtx.Lock()
+	defer d.changed()
+	defer d.mtx.Unlock()
+
+	diary := d.user(userId)
+	i := diary.index(entry.Id)
+	if i < 0 {
+		return ErrNoEntry
+	}
+	diary.Entries[i] = entry
+	return nil
+}
+
+func (d *MemoryDiary) Delete(userId int, entryId int) error {
+	d.mtx.Lock()
+	defer d.changed()
+	defer d.mtx.Unlock()
+
+	diary := d.user(userId)
+	i := diary.index(entryId)
+	if i < 0 {
+		return ErrNoEntry
+	}
+	diary.Entries = append(diary.Entries[:i], diary.Entries[i+1:]...)
+	return nil
+}
+
+func (d *MemoryDiary) MarshalJSON() ([]byte, error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	return json.Marshal(d.users)
+}
+
+func (d *MemoryDiary) UnmarshalJSON(data []byte) error {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	return json.Unmarshal(data, &d.users)
+}
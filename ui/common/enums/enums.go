@@ -50,6 +50,21 @@ func (s Status) String() string {
 	return "Invalid Status"
 }
 
+func (c Category) String() string {
+	switch c {
+	case Film:
+		return "Film"
+	case Show:
+		return "Show"
+	}
+	return "Invalid Category"
+}
+
+// Like review-api, so reviews read back the same, eg from the audit log
+func (c Category) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.String())
+}
+
 func (c *Category) UnmarshalJSON(data []byte) (err error) {
 	var category string
 	if err := json.Unmarshal(data, &category); err != nil {
@@ -66,22 +81,30 @@ func (c *Category) UnmarshalJSON(data []byte) (err error) {
 	return nil
 }
 
-func (s *Status) UnmarshalJSON(data []byte) (err error) {
-	var status string
-	if err := json.Unmarshal(data, &status); err != nil {
-		return err
-	}
+// ParseStatus is the inverse of Status.String
+func ParseStatus(status string) (Status, error) {
 	switch status {
 	case "PlanToWatch":
-		*s = PlanToWatch
+		return PlanToWatch, nil
 	case "Watching":
-		*s = Watching
+		return Watching, nil
 	case "Completed":
-		*s = Completed
+		return Completed, nil
 	case "Dropped":
-		*s = Dropped
-	default:
-		return fmt.Errorf("%q is not a valid Status", status)
+		return Dropped, nil
 	}
-	return nil
+	return 0, fmt.Errorf("%q is not a valid Status", status)
+}
+
+func (s Status) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+func (s *Status) UnmarshalJSON(data []byte) (err error) {
+	var status string
+	if err := json.Unmarshal(data, &status); err != nil {
+		return err
+	}
+	*s, err = ParseStatus(status)
+	return err
 }
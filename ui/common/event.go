@@ -1,11 +1,41 @@
 package common
 
 import (
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 type ShowFilm int
 
+type ShowPerson int
+
+// Another review-api user's lists
+type ShowUser int
+
+// A shared watchlist, see GroupStore
+type ShowGroup int
+
+// Clears AuthState and ReviewMap and goes back to the account picker.
+// KeepGuest asks a guest to sign up, then saves their reviews to the new account.
+type SignOut struct {
+	KeepGuest bool
+}
+
+// review-api rejected the cookie, so sign in again and come back
+type Unauthorized struct{}
+
+// The server is stopping, and closes the session at At
+type ShuttingDown struct {
+	At time.Time
+}
+
+// A message from the admins, shown in the banner until the next key
+type Broadcast string
+
+// An admin emptied the caches, so refetch what's shown
+type FlushCaches struct{}
+
 type KeyEvent struct {
 	KeyMsg  tea.KeyMsg
 	Handled bool
@@ -0,0 +1,28 @@
+package common
+
+import (
+	"context"
+	"sync"
+)
+
+// ForEach calls fn for 0 to n-1 from up to workers goroutines, and returns once every call has.
+// Once ctx is done, the rest are skipped. fn must lock whatever results it shares.
+func ForEach(ctx context.Context, n, workers int, fn func(i int)) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n && ctx.Err() == nil; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -1,7 +1,11 @@
 package common
 
 import (
+	"time"
+
+	"github.com/charmbracelet/log"
 	"github.com/hashicorp/go-retryablehttp"
+	"github.com/muesli/termenv"
 	"github.com/zhengkyl/review-ssh/ui/keymap"
 )
 
@@ -13,21 +17,53 @@ type Global struct {
 
 	ReviewMap map[int]Review
 	FilmCache Cache[Film]
+	GenreMap  map[int]string
+
+	// Where each film can be watched, see Settings.Available()
+	ProviderCache Cache[WatchProviders]
+	UserCache     Cache[User]
+
+	SearchHistory SearchHistory
+	Settings      SettingsStore
+	Groups        GroupStore
+	Diary         DiaryStore
+	Sessions      SessionStore
+	SignIns       SignInLimiter
+	Guest         *GuestReviews // Reviews while signed in as a guest
+	Outbox        *Outbox       // review-api writes in flight, shared by every session
+	Audit         *log.Logger   // Where Audit writes, nil to not audit
+	Presence      Presence      // Tells admins who's here, nil to not tell
+
+	// The session's terminal, for escape sequences bubbletea doesn't handle like OSC 52
+	Output *termenv.Output
 }
 
 type Config struct {
 	TMDB_API_KEY string
+	// Sessions without a key pressed for this long are closed, after a warning. 0 never closes them.
+	IdleTimeout time.Duration
+	// The session's time zone, for dates like "watched today". nil is the server's.
+	Location *time.Location
+}
+
+// Now is the time in the session's time zone
+func (g Global) Now() time.Time {
+	if g.Config.Location == nil {
+		return time.Now()
+	}
+	return time.Now().In(g.Config.Location)
 }
 
 type AuthState struct {
-	Authed bool
-	Cookie string
-	User   User
+	Authed  bool
+	Cookie  string
+	Expires time.Time // When review-api said Cookie expires, if it did
+	User    User
 }
 
+// Guests have no cookie, their reviews are kept in Global.Guest instead
 var GuestAuthState = AuthState{
 	Authed: true,
-	Cookie: "guestcookie",
 	User: User{
 		Id:   -1,
 		Name: "Guest",
@@ -0,0 +1,268 @@
+package common
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+var (
+	ErrNoGroup   = errors.New("no such group")
+	ErrNotMember = errors.New("not a member of this group")
+	ErrNoInvite  = errors.New("no group has that invite code")
+)
+
+type GroupFilm struct {
+	Tmdb_id  int   `json:"tmdb_id"`
+	Added_by int   `json:"added_by"`
+	Votes    []int `json:"votes"` // User ids
+}
+
+// A watchlist shared by its members, who all add to and vote on it
+type Group struct {
+	Id      int         `json:"id"`
+	Name    string      `json:"name"`
+	Invite  string      `json:"invite"` // Members share it with whoever they want to join
+	Members []int       `json:"members"`
+	Films   []GroupFilm `json:"films"`
+}
+
+func (g Group) HasMember(userId int) bool {
+	return slices.Contains(g.Members, userId)
+}
+
+func (g Group) HasFilm(filmId int) bool {
+	return g.film(filmId) >= 0
+}
+
+func (g Group) film(filmId int) int {
+	return slices.IndexFunc(g.Films, func(f GroupFilm) bool { return f.Tmdb_id == filmId })
+}
+
+func (g Group) clone() Group {
+	g.Members = append([]int{}, g.Members...)
+	films := make([]GroupFilm, len(g.Films))
+	for i, f := range g.Films {
+		f.Votes = append([]int{}, f.Votes...)
+		films[i] = f
+	}
+	g.Films = films
+	return g
+}
+
+// review-api has no groups, so they're shared by every session on this server
+type GroupStore interface {
+	Groups(userId int) []Group
+	Get(groupId int) (Group, bool)
+	Create(userId int, name string) Group
+	// Joins the group with this invite code
+	Join(invite string, userId int) (Group, error)
+	Leave(groupId, userId int) error
+	// Adds the film, or removes it if it's already there
+	ToggleFilm(groupId, userId, filmId int) error
+	ToggleVote(groupId, userId, filmId int) error
+}
+
+// MemoryGroups is a GroupStore kept in memory, see memory
+type MemoryGroups struct {
+	memory
+	nextId int
+	groups map[int]*Group
+}
+
+func NewMemoryGroups() *MemoryGroups {
+	return &MemoryGroups{nextId: 1, groups: map[int]*Group{}}
+}
+
+// Oldest first
+func (s *MemoryGroups) Groups(userId int) []Group {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	groups := []Group{}
+	for _, g := range s.groups {
+		if g.HasMember(userId) {
+			groups = append(groups, g.clone())
+		}
+	}
+	slices.SortFunc(groups, func(a, b Group) int { return a.Id - b.Id })
+	return groups
+}
+
+func (s *MemoryGroups) Get(groupId int) (Group, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	g, ok := s.groups[groupId]
+	if !ok {
+		return Group{}, false
+	}
+	return g.clone(), true
+}
+
+func (s *MemoryGroups) Create(userId int, name string) Group {
+	s.mtx.Lock()
+	defer s.changed()
+	defer s.mtx.Unlock()
+
+	g := &Group{
+		Id:      s.nextId,
+		Name:    strings.TrimSpace(name),
+		Invite:  newInvite(),
+		Members: []int{userId},
+		Films:   []GroupFilm{},
+	}
+	s.nextId++
+	s.groups[g.Id] = g
+	return g.clone()
+}
+
+func (s *MemoryGroups) Join(invite string, userId int) (Group, error) {
+	s.mtx.Lock()
+	group, added, err := s.join(invite, userId)
+	s.mtx.Unlock()
+
+	// Only save when someone new joins, not on every mistyped code
+	if added {
+		s.changed()
+	}
+	return group, err
+}
+
+// Callers must hold mtx
+func (s *MemoryGroups) join(invite string, userId int) (Group, bool, error) {
+	invite = strings.ToLower(strings.TrimSpace(invite))
+	for _, g := range s.groups {
+		if invite == "" || g.Invite != invite {
+			continue
+		}
+		if g.HasMember(userId) {
+			return g.clone(), false, nil
+		}
+		g.Members = append(g.Members, userId)
+		return g.clone(), true, nil
+	}
+	return Group{}, false, ErrNoInvite
+}
+
+// The last member to leave deletes the group. Their votes go with them.
+func (s *MemoryGroups) Leave(groupId, userId int) error {
+	s.mtx.Lock()
+	defer s.changed()
+	defer s.mtx.Unlock()
+
+	g, err := s.member(groupId, userId)
+	if err != nil {
+		return err
+	}
+	g.Members = slices.DeleteFunc(g.Members, func(id int) bool { return id == userId })
+	if len(g.Members) == 0 {
+		delete(s.groups, groupId)
+		return nil
+	}
+	for i := range g.Films {
+		g.Films[i].Votes = slices.DeleteFunc(g.Films[i].Votes, func(id int) bool { return id == userId })
+	}
+	return nil
+}
+
+func (s *MemoryGroups) ToggleFilm(groupId, userId, filmId int) error {
+	s.mtx.Lock()
+	defer s.changed()
+	defer s.mtx.Unlock()
+
+	g, err := s.member(groupId, userId)
+	if err != nil {
+		return err
+	}
+	if i := g.film(filmId); i >= 0 {
+		g.Films = slices.Delete(g.Films, i, i+1)
+		return nil
+	}
+	// Adding a film counts as voting for it
+	g.Films = append(g.Films, GroupFilm{Tmdb_id: filmId, Added_by: userId, Votes: []int{userId}})
+	return nil
+}
+
+func (s *MemoryGroups) ToggleVote(groupId, userId, filmId int) error {
+	s.mtx.Lock()
+	defer s.changed()
+	defer s.mtx.Unlock()
+
+	g, err := s.member(groupId, userId)
+	if err != nil {
+		return err
+	}
+	i := g.film(filmId)
+	if i < 0 {
+		return nil
+	}
+	f := &g.Films[i]
+	if j := slices.Index(f.Votes, userId); j >= 0 {
+		f.Votes = slices.Delete(f.Votes, j, j+1)
+	} else {
+		f.Votes = append(f.Votes, userId)
+	}
+	return nil
+}
+
+// Random, unlike group ids, so nobody can join by guessing
+func newInvite() string {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return strings.ToLower(base32.StdEncoding.EncodeToString(b))
+}
+
+// Callers must hold mtx
+func (s *MemoryGroups) member(groupId, userId int) (*Group, error) {
+	g, ok := s.groups[groupId]
+	if !ok {
+		return nil, ErrNoGroup
+	}
+	if !g.HasMember(userId) {
+		return nil, ErrNotMember
+	}
+	return g, nil
+}
+
+type memoryGroupsJSON struct {
+	NextId int            `json:"next_id"`
+	Groups map[int]*Group `json:"groups"`
+}
+
+func (s *MemoryGroups) MarshalJSON() ([]byte, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return json.Marshal(memoryGroupsJSON{s.nextId, s.groups})
+}
+
+func (s *MemoryGroups) UnmarshalJSON(data []byte) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var v memoryGroupsJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	s.nextId = v.NextId
+	s.groups = v.Groups
+	if s.groups == nil {
+		s.groups = map[int]*Group{}
+	}
+	// Ids are never reused, even if the file was edited by hand
+	for id := range s.groups {
+		if id >= s.nextId {
+			s.nextId = id + 1
+		}
+	}
+	if s.nextId < 1 {
+		s.nextId = 1
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zhengkyl/review-ssh/ui/common/enums"
+)
+
+// GuestReviews stands in for review-api while signed in as a guest, so guests can try everything.
+// It lasts as long as the ssh session. Do sends the guest's review requests here.
+type GuestReviews struct {
+	mtx     sync.Mutex
+	reviews map[int]Review
+	// Films Migrate has created for the signed in user but not finished
+	posted map[int]bool
+}
+
+func NewGuestReviews() *GuestReviews {
+	return &GuestReviews{reviews: map[int]Review{}, posted: map[int]bool{}}
+}
+
+func (r *GuestReviews) Len() int {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return len(r.reviews)
+}
+
+func (r *GuestReviews) Clear() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.reviews = map[int]Review{}
+	r.posted = map[int]bool{}
+}
+
+// Most recently updated first, like review-api
+func (r *GuestReviews) list() []Review {
+	reviews := make([]Review, 0, len(r.reviews))
+	for _, review := range r.reviews {
+		reviews = append(reviews, review)
+	}
+	sort.Slice(reviews, func(i, j int) bool {
+		if !reviews[i].Updated_at.Equal(reviews[j].Updated_at) {
+			return reviews[i].Updated_at.After(reviews[j].Updated_at)
+		}
+		return reviews[i].Tmdb_id < reviews[j].Tmdb_id
+	})
+	return reviews
+}
+
+func intParam(query url.Values, name string, fallback int) int {
+	n, err := strconv.Atoi(query.Get(name))
+	if err != nil || n < 1 {
+		return fallback
+	}
+	return n
+}
+
+func (r *GuestReviews) get(query url.Values) Paged[Review] {
+	reviews := r.list()
+	if tmdbId := query.Get("tmdb_id"); tmdbId != "" {
+		id, _ := strconv.Atoi(tmdbId)
+		filtered := []Review{}
+		for _, review := range reviews {
+			if review.Tmdb_id == id {
+				filtered = append(filtered, review)
+			}
+		}
+		reviews = filtered
+	}
+
+	page, perPage := intParam(query, "page", 1), intParam(query, "per_page", 10)
+	start := (page - 1) * perPage
+	end := start + perPage
+	if start > len(reviews) {
+		start = len(reviews)
+	}
+	if end > len(reviews) {
+		end = len(reviews)
+	}
+
+	return Paged[Review]{
+		Results:       reviews[start:end],
+		Page:          page,
+		Total_Pages:   (len(reviews) + perPage - 1) / perPage,
+		Total_Results: len(reviews),
+	}
+}
+
+func (r *GuestReviews) post(body map[string]interface{}) (Review, error) {
+	tmdbId, _ := body["tmdb_id"].(int)
+	status, err := enums.ParseStatus(stringField(body, "status"))
+	if err != nil || tmdbId == 0 {
+		return Review{}, StatusError(http.StatusBadRequest)
+	}
+	if _, ok := r.reviews[tmdbId]; ok {
+		return Review{}, StatusError(http.StatusConflict)
+	}
+
+	now := time.Now()
+	review := Review{
+		User_id:    GuestAuthState.User.Id,
+		Tmdb_id:    tmdbId,
+		Category:   enums.Film,
+		Status:     status,
+		Created_at: now,
+		Updated_at: now,
+	}
+	r.reviews[tmdbId] = review
+	return review, nil
+}
+
+func (r *GuestReviews) patch(tmdbId int, body map[string]interface{}) (Review, error) {
+	review, ok := r.reviews[tmdbId]
+	if !ok {
+		return Review{}, StatusError(http.StatusNotFound)
+	}
+
+	if s, ok := body["status"].(string); ok {
+		status, err := enums.ParseStatus(s)
+		if err != nil {
+			return Review{}, StatusError(http.StatusBadRequest)
+		}
+		review.Status = status
+	}
+	if text, ok := body["text"].(string); ok {
+		review.Text = text
+	}
+	for name, field := range map[string]*bool{
+		"fun_before": &review.Fun_before,
+		"fun_during": &review.Fun_during,
+		"fun_after":  &review.Fun_after,
+	} {
+		if v, ok := body[name].(bool); ok {
+			*field = v
+		}
+	}
+
+	review.Updated_at = time.Now()
+	r.reviews[tmdbId] = review
+	return review, nil
+}
+
+func stringField(body map[string]interface{}, name string) string {
+	s, _ := body[name].(string)
+	return s
+}
+
+// serve answers a request like review-api would. ok is false if it isn't about the guest's reviews.
+func (r *GuestReviews) serve(method string, rawURL string, body map[string]interface{}) (data interface{}, ok bool, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || !strings.HasPrefix(rawURL, ReviewBase+"/reviews") {
+		return nil, false, nil
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	path := strings.TrimPrefix(u.Path, "/reviews")
+	switch {
+	case method == "GET" && path == "":
+		// Other users' reviews are real
+		if u.Query().Get("user_id") != strconv.Itoa(GuestAuthState.User.Id) {
+			return nil, false, nil
+		}
+		return r.get(u.Query()), true, nil
+	case method == "POST" && path == "":
+		review, err := r.post(body)
+		return review, true, err
+	case strings.HasPrefix(path, "/Film/"):
+		tmdbId, err := strconv.Atoi(strings.TrimPrefix(path, "/Film/"))
+		if err != nil {
+			return nil, true, StatusError(http.StatusNotFound)
+		}
+		switch method {
+		case "PATCH":
+			review, err := r.patch(tmdbId, body)
+			return review, true, err
+		case "DELETE":
+			if _, ok := r.reviews[tmdbId]; !ok {
+				return nil, true, StatusError(http.StatusNotFound)
+			}
+			delete(r.reviews, tmdbId)
+			return struct{}{}, true, nil
+		}
+	}
+	return nil, true, StatusError(http.StatusMethodNotAllowed)
+}
+
+// Migrate saves the guest's reviews to the signed in user in g, forgetting each one once it's saved.
+// Films the user already reviewed keep their review. It returns how many were kept.
+// If it fails partway, calling it again carries on where it stopped.
+func (r *GuestReviews) Migrate(ctx context.Context, g Global) (kept int, err error) {
+	r.mtx.Lock()
+	reviews := r.list()
+	r.mtx.Unlock()
+
+	ctx = WithAudit(ctx, "via", "guest")
+	defer func() { Audit(g, "guest.migrate", "reviews", len(reviews), "kept", kept, "error", err) }()
+
+	for _, review := range reviews {
+		_, err := PostReview(ctx, g, review.Tmdb_id, review.Status.String())
+		if errors.Is(err, StatusError(http.StatusConflict)) {
+			// Created by an earlier try that failed before the PATCH, so it still needs one
+			r.mtx.Lock()
+			posted := r.posted[review.Tmdb_id]
+			r.mtx.Unlock()
+			if !posted {
+				r.forget(review.Tmdb_id)
+				continue
+			}
+		} else if err != nil {
+			return kept, err
+		}
+
+		r.mtx.Lock()
+		r.posted[review.Tmdb_id] = true
+		r.mtx.Unlock()
+
+		if review.Text != "" || review.Fun_before || review.Fun_during || review.Fun_after {
+			_, err = PatchReview(ctx, g, review.Tmdb_id, map[string]interface{}{
+				"text":       review.Text,
+				"fun_before": review.Fun_before,
+				"fun_during": review.Fun_during,
+				"fun_after":  review.Fun_after,
+			})
+			if err != nil {
+				return kept, err
+			}
+		}
+		r.forget(review.Tmdb_id)
+		kept++
+	}
+
+	return kept, nil
+}
+
+func (r *GuestReviews) forget(tmdbId int) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	delete(r.reviews, tmdbId)
+	delete(r.posted, tmdbId)
+}
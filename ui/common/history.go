@@ -0,0 +1,133 @@
+package common
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const maxRecentSearches = 20
+
+type SearchEntry struct {
+	Name  string    `json:"name,omitempty"` // Only set for saved searches
+	Query string    `json:"query"`          // Free text and filter syntax, see search.ParseQuery()
+	Time  time.Time `json:"time"`
+}
+
+type SearchHistory interface {
+	Recent(userId int) []SearchEntry
+	AddRecent(userId int, entry SearchEntry)
+	RemoveRecent(userId int, query string)
+	Saved(userId int) []SearchEntry
+	Save(userId int, entry SearchEntry)
+	Unsave(userId int, name string)
+}
+
+type UserSearches struct {
+	Recent []SearchEntry `json:"recent"`
+	Saved  []SearchEntry `json:"saved"`
+}
+
+// MemoryHistory is a SearchHistory kept in memory, see memory
+type MemoryHistory struct {
+	memory
+	users map[int]*UserSearches
+}
+
+func NewMemoryHistory() *MemoryHistory {
+	return &MemoryHistory{users: map[int]*UserSearches{}}
+}
+
+func (h *MemoryHistory) user(userId int) *UserSearches {
+	u, ok := h.users[userId]
+	if !ok {
+		u = &UserSearches{}
+		h.users[userId] = u
+	}
+	return u
+}
+
+func (h *MemoryHistory) Recent(userId int) []SearchEntry {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	return append([]SearchEntry{}, h.user(userId).Recent...)
+}
+
+// Most recent first, repeated queries move to the front
+func (h *MemoryHistory) AddRecent(userId int, entry SearchEntry) {
+	h.mtx.Lock()
+	defer h.changed()
+	defer h.mtx.Unlock()
+	u := h.user(userId)
+
+	recent := []SearchEntry{entry}
+	for _, e := range u.Recent {
+		if e.Query != entry.Query && len(recent) < maxRecentSearches {
+			recent = append(recent, e)
+		}
+	}
+	u.Recent = recent
+}
+
+func (h *MemoryHistory) RemoveRecent(userId int, query string) {
+	h.mtx.Lock()
+	defer h.changed()
+	defer h.mtx.Unlock()
+	u := h.user(userId)
+
+	recent := []SearchEntry{}
+	for _, e := range u.Recent {
+		if e.Query != query {
+			recent = append(recent, e)
+		}
+	}
+	u.Recent = recent
+}
+
+func (h *MemoryHistory) Saved(userId int) []SearchEntry {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	return append([]SearchEntry{}, h.user(userId).Saved...)
+}
+
+// Saving with an existing name replaces that search
+func (h *MemoryHistory) Save(userId int, entry SearchEntry) {
+	h.mtx.Lock()
+	defer h.changed()
+	defer h.mtx.Unlock()
+	u := h.user(userId)
+
+	for i, e := range u.Saved {
+		if e.Name == entry.Name {
+			u.Saved[i] = entry
+			return
+		}
+	}
+	u.Saved = append(u.Saved, entry)
+}
+
+func (h *MemoryHistory) Unsave(userId int, name string) {
+	h.mtx.Lock()
+	defer h.changed()
+	defer h.mtx.Unlock()
+	u := h.user(userId)
+
+	saved := []SearchEntry{}
+	for _, e := range u.Saved {
+		if e.Name != name {
+			saved = append(saved, e)
+		}
+	}
+	u.Saved = saved
+}
+
+func (h *MemoryHistory) MarshalJSON() ([]byte, error) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	return json.Marshal(h.users)
+}
+
+func (h *MemoryHistory) UnmarshalJSON(data []byte) error {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	return json.Unmarshal(data, &h.users)
+}
@@ -0,0 +1,14 @@
+package common
+
+import "time"
+
+// Slows down password guessing, eg per connection and per email
+type SignInLimiter interface {
+	// Allow uses up an attempt to sign in as email, or says how long until there's one
+	Allow(email string) (bool, time.Duration)
+}
+
+// NoLimit allows every attempt
+type NoLimit struct{}
+
+func (NoLimit) Allow(email string) (bool, time.Duration) { return true, 0 }
@@ -0,0 +1,22 @@
+package common
+
+import "sync"
+
+// memory is embedded by the Memory* stores. They only live as long as the process,
+// unless something saves them from OnChange, and are safe to share between sessions.
+type memory struct {
+	mtx      sync.Mutex
+	onChange func()
+}
+
+// OnChange calls f after every change, eg to save the store. Set it before sharing the store.
+func (m *memory) OnChange(f func()) {
+	m.onChange = f
+}
+
+// Deferred before mtx.Unlock(), so it runs after and f can read the store
+func (m *memory) changed() {
+	if m.onChange != nil {
+		m.onChange()
+	}
+}
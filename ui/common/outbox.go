@@ -0,0 +1,67 @@
+package common
+
+import (
+	"context"
+	"sync"
+)
+
+// Outbox counts writes to review-api that haven't finished, so the server can let them land before exiting.
+// A nil Outbox counts nothing.
+type Outbox struct {
+	mtx     sync.Mutex
+	pending int
+	flushed chan struct{} // Closed once pending is back to 0, if someone is waiting
+}
+
+// start counts a write until the returned func is called
+func (o *Outbox) start() func() {
+	if o == nil {
+		return func() {}
+	}
+	o.mtx.Lock()
+	o.pending++
+	o.mtx.Unlock()
+
+	return func() {
+		o.mtx.Lock()
+		defer o.mtx.Unlock()
+		o.pending--
+		if o.pending == 0 && o.flushed != nil {
+			close(o.flushed)
+			o.flushed = nil
+		}
+	}
+}
+
+func (o *Outbox) Pending() int {
+	if o == nil {
+		return 0
+	}
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	return o.pending
+}
+
+// Flush waits until no writes are in flight, or ctx is done
+func (o *Outbox) Flush(ctx context.Context) error {
+	if o == nil {
+		return nil
+	}
+	o.mtx.Lock()
+	if o.pending == 0 {
+		o.mtx.Unlock()
+		return nil
+	}
+	if o.flushed == nil {
+		o.flushed = make(chan struct{})
+	}
+	flushed := o.flushed
+	o.mtx.Unlock()
+
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
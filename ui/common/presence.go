@@ -0,0 +1,7 @@
+package common
+
+// Presence is how a session tells the server's admin console who's in it and where
+type Presence interface {
+	// Report is called when the page or user changes. userId is 0 before signing in.
+	Report(userId int, user, page string, films int)
+}
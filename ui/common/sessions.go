@@ -0,0 +1,27 @@
+package common
+
+import "time"
+
+// Remembers who signed in on a connection, eg by ssh key, so reconnecting skips signing in
+type SessionStore interface {
+	// CanRemember is false if there's no way to recognize this connection next time
+	CanRemember() bool
+	// Remembered is false if nothing was saved or it has expired
+	Remembered() (AuthState, bool)
+	Remember(auth AuthState)
+	Forget()
+}
+
+// Expired is true once review-api's expiry for the cookie has passed.
+// Cookies without one are assumed to last until review-api says otherwise.
+func (a AuthState) Expired(now time.Time) bool {
+	return !a.Expires.IsZero() && now.After(a.Expires)
+}
+
+// NoSessions never remembers anyone
+type NoSessions struct{}
+
+func (NoSessions) CanRemember() bool             { return false }
+func (NoSessions) Remembered() (AuthState, bool) { return AuthState{}, false }
+func (NoSessions) Remember(auth AuthState)       {}
+func (NoSessions) Forget()                       {}
@@ -0,0 +1,99 @@
+package common
+
+import (
+	"encoding/json"
+	"maps"
+
+	"golang.org/x/exp/slices"
+)
+
+const DefaultRegion = "US"
+
+type Settings struct {
+	Region   string `json:"region"`   // ISO 3166-1, like "US"
+	Services []int  `json:"services"` // TMDB provider ids the user subscribes to
+	// review-api has no follows, so they're kept with settings
+	Following []int `json:"following"`
+	// By lists page tab name
+	ListSorts map[string]ListSort `json:"list_sorts,omitempty"`
+}
+
+type ListSort struct {
+	By      string `json:"by"` // Like "title", see the lists page
+	Reverse bool   `json:"reverse"`
+}
+
+func (s Settings) Subscribed(providerId int) bool {
+	return slices.Contains(s.Services, providerId)
+}
+
+func (s Settings) Follows(userId int) bool {
+	return slices.Contains(s.Following, userId)
+}
+
+func (s *Settings) ToggleFollow(userId int) {
+	if i := slices.Index(s.Following, userId); i >= 0 {
+		s.Following = slices.Delete(s.Following, i, i+1)
+		return
+	}
+	s.Following = append(s.Following, userId)
+}
+
+// Whether film can be watched on one of the user's services at no extra cost
+func (s Settings) Available(providers WatchProviders) bool {
+	for _, p := range providers.Results[s.Region].Included() {
+		if s.Subscribed(p.Provider_id) {
+			return true
+		}
+	}
+	return false
+}
+
+type SettingsStore interface {
+	Get(userId int) Settings
+	Set(userId int, settings Settings)
+}
+
+// MemorySettings is a SettingsStore kept in memory, see memory
+type MemorySettings struct {
+	memory
+	users map[int]Settings
+}
+
+func NewMemorySettings() *MemorySettings {
+	return &MemorySettings{users: map[int]Settings{}}
+}
+
+// Users who never saved settings get DefaultRegion and no services
+func (s *MemorySettings) Get(userId int) Settings {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	settings := s.users[userId]
+	if settings.Region == "" {
+		settings.Region = DefaultRegion
+	}
+	settings.Services = append([]int{}, settings.Services...)
+	settings.Following = append([]int{}, settings.Following...)
+	settings.ListSorts = maps.Clone(settings.ListSorts)
+	return settings
+}
+
+func (s *MemorySettings) Set(userId int, settings Settings) {
+	s.mtx.Lock()
+	defer s.changed()
+	defer s.mtx.Unlock()
+	s.users[userId] = settings
+}
+
+func (s *MemorySettings) MarshalJSON() ([]byte, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return json.Marshal(s.users)
+}
+
+func (s *MemorySettings) UnmarshalJSON(data []byte) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return json.Unmarshal(data, &s.users)
+}
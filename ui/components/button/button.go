@@ -13,19 +13,22 @@ type Model struct {
 	text     string
 	callback tea.Cmd
 	focused  bool
+	disabled bool
 }
 
 type Style struct {
-	Normal lipgloss.Style
-	Active lipgloss.Style
+	Normal   lipgloss.Style
+	Active   lipgloss.Style
+	Disabled lipgloss.Style
 }
 
 func New(p common.Props, text string, callback tea.Cmd) *Model {
 	return &Model{
 		props: p,
 		Style: Style{
-			Normal: lipgloss.NewStyle().Foreground(lipgloss.Color("#FFF7DB")).Background(lipgloss.Color("#888B7E")).Padding(0, 1),
-			Active: lipgloss.NewStyle().Foreground(lipgloss.Color("#FFF7DB")).Background(lipgloss.Color("#F25D94")).Padding(0, 1),
+			Normal:   lipgloss.NewStyle().Foreground(lipgloss.Color("#FFF7DB")).Background(lipgloss.Color("#888B7E")).Padding(0, 1),
+			Active:   lipgloss.NewStyle().Foreground(lipgloss.Color("#FFF7DB")).Background(lipgloss.Color("#F25D94")).Padding(0, 1),
+			Disabled: lipgloss.NewStyle().Foreground(lipgloss.Color("#888B7E")).Padding(0, 1),
 		},
 		text:     text,
 		callback: callback,
@@ -45,12 +48,21 @@ func (m *Model) Blur() {
 	m.focused = false
 }
 
+// Disabled buttons can still be focused, but do nothing
+func (m *Model) SetDisabled(disabled bool) {
+	m.disabled = disabled
+}
+
+func (m *Model) Disabled() bool {
+	return m.disabled
+}
+
 func (m *Model) SetSize(h, w int) {
 
 }
 
 func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
-	if m.focused {
+	if m.focused && !m.disabled {
 
 		switch msg := msg.(type) {
 		case *common.KeyEvent:
@@ -67,6 +79,13 @@ func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
 }
 
 func (m *Model) View() string {
+	if m.disabled {
+		if m.focused {
+			return m.Style.Disabled.Copy().Underline(true).Render(m.text)
+		}
+		return m.Style.Disabled.Render(m.text)
+	}
+
 	if m.focused {
 		return m.Style.Active.Render(m.text)
 	}
@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/zhengkyl/review-ssh/ui/common"
 	"github.com/zhengkyl/review-ssh/ui/components/button"
+	"github.com/zhengkyl/review-ssh/ui/components/textfield"
 	"github.com/zhengkyl/review-ssh/ui/util"
 )
 
@@ -19,6 +20,7 @@ type Model struct {
 	props   common.Props
 	text    string
 	buttons []button.Model
+	input   *textfield.Model // Optional, for typed confirmations
 	active  int
 	focused bool
 }
@@ -32,6 +34,10 @@ func New(p common.Props, text string) *Model {
 	return m
 }
 
+func (m *Model) SetText(text string) {
+	m.text = text
+}
+
 func (m *Model) Buttons(buttons ...button.Model) {
 	m.buttons = buttons
 
@@ -39,6 +45,11 @@ func (m *Model) Buttons(buttons ...button.Model) {
 	m.buttons[0].Focus()
 }
 
+// Input adds a text field above the buttons. It gets keys before the buttons do.
+func (m *Model) Input(input *textfield.Model) {
+	m.input = input
+}
+
 func (m *Model) Focused() bool {
 	return m.focused
 }
@@ -50,10 +61,18 @@ func (m *Model) Focus() {
 		m.buttons[m.active].Focus()
 	}
 
+	if m.input != nil {
+		m.input.Focus()
+	}
+
 	m.focused = true
 }
 
 func (m *Model) Blur() {
+	if m.input != nil {
+		m.input.Blur()
+	}
+
 	m.focused = false
 }
 
@@ -63,10 +82,21 @@ func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
 	case *common.KeyEvent:
 		prevActive := m.active
 
-		switch {
-		case key.Matches(msg.KeyMsg, m.props.Global.KeyMap.Back):
+		if key.Matches(msg.KeyMsg, m.props.Global.KeyMap.Back) {
 			msg.Handled = true
 			m.Blur()
+			return m, nil
+		}
+
+		if m.input != nil {
+			_, cmd := m.input.Update(msg)
+			if msg.Handled {
+				return m, cmd
+			}
+			cmds = append(cmds, cmd)
+		}
+
+		switch {
 		case key.Matches(msg.KeyMsg, m.props.Global.KeyMap.NextX):
 			msg.Handled = true
 			m.active = util.Mod(m.active+1, len(m.buttons))
@@ -79,6 +109,11 @@ func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
 			m.buttons[prevActive].Blur()
 			m.buttons[m.active].Focus()
 		}
+	default:
+		if m.input != nil {
+			_, cmd := m.input.Update(msg)
+			cmds = append(cmds, cmd)
+		}
 	}
 
 	for _, child := range m.buttons {
@@ -94,6 +129,10 @@ func (m *Model) View() string {
 
 	sb.WriteString(m.text)
 	sb.WriteString("\n\n")
+	if m.input != nil {
+		sb.WriteString(m.input.View())
+		sb.WriteString("\n")
+	}
 	for _, button := range m.buttons {
 		sb.WriteString(button.View())
 		sb.WriteString(" ")
@@ -70,6 +70,10 @@ func (m *Model) SetSize(width, height int) {
 	m.props.Height = height
 }
 
+func (m *Model) Options() []Option {
+	return m.options
+}
+
 func (m *Model) SetItems(options []Option) {
 	m.options = options
 	if m.Selected > len(options)-1 {
@@ -94,6 +98,9 @@ func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
 			m.active = util.Max(m.active-1, 0)
 		case key.Matches(msg.KeyMsg, m.props.Global.KeyMap.Select):
 			msg.Handled = true
+			if len(m.options) == 0 {
+				return m, nil
+			}
 			if !m.open {
 				m.open = true
 				if m.Selected == -1 {
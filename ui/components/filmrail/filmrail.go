@@ -0,0 +1,245 @@
+package filmrail
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/common/enums"
+	"github.com/zhengkyl/review-ssh/ui/components/poster"
+	"github.com/zhengkyl/review-ssh/ui/util"
+)
+
+// NOTE: Fullwidth spaces are 2 wide
+const (
+	posterWidth  = 4 * 2
+	posterHeight = 6
+	cardWidth    = 14
+	cardGap      = 2
+)
+
+// Header + poster + title + subtitle
+const Height = 1 + posterHeight + 2
+
+var (
+	headerStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	focusedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25D94"))
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	activeStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#F25D94"))
+	subtitleStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	badgeStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25D94"))
+)
+
+// A horizontally scrolling row of small film cards
+type Model struct {
+	props   common.Props
+	title   string
+	films   []common.Film
+	posters []*poster.Model
+	started []bool
+	loaded  bool
+	active  int
+	offset  int
+	focused bool
+}
+
+func New(p common.Props, title string) *Model {
+	return &Model{
+		props: p,
+		title: title,
+	}
+}
+
+func (m *Model) SetTitle(title string) {
+	m.title = title
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.props.Width = width
+	m.props.Height = height
+}
+
+// Forget the films, showing loading until SetFilms
+func (m *Model) Reset() {
+	m.films = nil
+	m.posters = nil
+	m.started = nil
+	m.loaded = false
+	m.active = 0
+	m.offset = 0
+}
+
+func (m *Model) SetFilms(films []common.Film) tea.Cmd {
+	m.Reset()
+	m.loaded = true
+	m.films = films
+	for _, film := range films {
+		m.posters = append(m.posters, poster.New(
+			common.Props{Width: posterWidth, Height: posterHeight, Global: m.props.Global},
+			"https://image.tmdb.org/t/p/w200"+film.Poster_path,
+		))
+		m.started = append(m.started, false)
+	}
+	return m.startPosters()
+}
+
+func (m *Model) Loaded() bool {
+	return m.loaded
+}
+
+func (m *Model) Empty() bool {
+	return len(m.films) == 0
+}
+
+func (m *Model) Focused() bool {
+	return m.focused
+}
+
+func (m *Model) Focus() {
+	m.focused = true
+}
+
+func (m *Model) Blur() {
+	m.focused = false
+}
+
+func (m *Model) visible() int {
+	return util.Max((m.props.Width+cardGap)/(cardWidth+cardGap), 1)
+}
+
+// Posters are only fetched once scrolled into view
+func (m *Model) startPosters() tea.Cmd {
+	var cmds []tea.Cmd
+	end := util.Min(m.offset+m.visible(), len(m.films))
+	for i := m.offset; i < end; i++ {
+		if !m.started[i] {
+			m.started[i] = true
+			cmds = append(cmds, m.posters[i].Init())
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) scroll() tea.Cmd {
+	if m.active < m.offset {
+		m.offset = m.active
+	} else if m.active >= m.offset+m.visible() {
+		m.offset = m.active - m.visible() + 1
+	}
+	return m.startPosters()
+}
+
+func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case *common.KeyEvent:
+		if !m.focused || m.Empty() {
+			return m, nil
+		}
+		km := m.props.Global.KeyMap
+
+		// Unhandled at either end, so focus can move on
+		switch {
+		case key.Matches(msg.KeyMsg, km.Left) && m.active > 0:
+			msg.Handled = true
+			m.active--
+			return m, m.scroll()
+		case key.Matches(msg.KeyMsg, km.Right) && m.active < len(m.films)-1:
+			msg.Handled = true
+			m.active++
+			return m, m.scroll()
+		case key.Matches(msg.KeyMsg, km.Select):
+			msg.Handled = true
+			filmId := m.films[m.active].Id
+			return m, func() tea.Msg { return common.ShowFilm(filmId) }
+		case key.Matches(msg.KeyMsg, km.Add):
+			msg.Handled = true
+			filmId := m.films[m.active].Id
+			if _, ok := m.props.Global.ReviewMap[filmId]; ok {
+				return m, nil
+			}
+			return m, common.PostReviewCmd(m.props.Global, filmId, enums.PlanToWatch.String(), nil)
+		}
+		return m, nil
+	}
+
+	var cmds []tea.Cmd
+	for i, p := range m.posters {
+		if m.started[i] {
+			_, cmd := p.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// Films already reviewed are badged rather than hidden
+func (m *Model) subtitle(film common.Film) string {
+	year := "    "
+	if len(film.Release_date) >= 4 {
+		year = film.Release_date[:4]
+	}
+
+	review, ok := m.props.Global.ReviewMap[film.Id]
+	if !ok {
+		if film.Vote_average > 0 {
+			return subtitleStyle.Render(util.TruncAndPadUnicode(fmt.Sprintf("%s ★%.1f", year, film.Vote_average), cardWidth))
+		}
+		return subtitleStyle.Render(util.TruncAndPadUnicode(year, cardWidth))
+	}
+
+	badge := "✓ seen"
+	switch {
+	case review.Status == enums.PlanToWatch:
+		badge = "+ planned"
+	case review.Status != enums.Completed:
+		badge = review.Status.DisplayString()
+	case review.Fun_after:
+		badge = "★ starred"
+	}
+	return badgeStyle.Render(util.TruncAndPadUnicode(badge, cardWidth))
+}
+
+func (m *Model) card(i int) string {
+	film := m.films[i]
+
+	title := util.TruncAndPadUnicode(film.Title, cardWidth)
+	if m.focused && i == m.active {
+		title = activeStyle.Render(title)
+	} else {
+		title = titleStyle.Render(title)
+	}
+
+	cover := lipgloss.NewStyle().Width(cardWidth).Render(m.posters[i].View())
+	return lipgloss.JoinVertical(lipgloss.Left, cover, title, m.subtitle(film))
+}
+
+func (m *Model) View() string {
+	header := m.title
+	if m.focused && !m.Empty() {
+		header = focusedStyle.Render(header) + headerStyle.Render(fmt.Sprintf(" %d/%d  ←→ · enter details · a plan to watch", m.active+1, len(m.films)))
+	} else {
+		header = headerStyle.Render(header)
+	}
+	header = lipgloss.NewStyle().MaxWidth(m.props.Width).Render(header)
+
+	switch {
+	case !m.loaded:
+		return header + "\n" + subtitleStyle.Render("Loading...")
+	case m.Empty():
+		return header + "\n" + subtitleStyle.Render("Nothing to suggest")
+	}
+
+	cards := []string{}
+	end := util.Min(m.offset+m.visible(), len(m.films))
+	for i := m.offset; i < end; i++ {
+		if i > m.offset {
+			cards = append(cards, "  ")
+		}
+		cards = append(cards, m.card(i))
+	}
+
+	row := lipgloss.JoinHorizontal(lipgloss.Top, cards...)
+	return header + "\n" + row
+}
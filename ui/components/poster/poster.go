@@ -10,6 +10,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/muesli/termenv"
+	"github.com/zhengkyl/review-ssh/metrics"
 	"github.com/zhengkyl/review-ssh/ui/common"
 	"github.com/zhengkyl/review-ssh/ui/components/skeleton"
 	"golang.org/x/image/draw"
@@ -76,6 +77,11 @@ func (m *Model) SetSize(width, height int) {
 }
 
 func (m *Model) Init() tea.Cmd {
+	if m.loaded {
+		metrics.CacheLookups.Inc("poster", "hit")
+		return nil
+	}
+	metrics.CacheLookups.Inc("poster", "miss")
 	return tea.Batch(getSrcCmd(m.props.Global.HttpClient, m.src), m.skeleton.Tick)
 }
 
@@ -109,7 +115,7 @@ func (m *Model) View() string {
 	view := ""
 	if m.scaled == nil ||
 		m.scaled.Bounds().Max.X != m.props.Width ||
-		m.scaled.Bounds().Max.Y != m.props.Height {
+		m.scaled.Bounds().Max.Y != m.props.Height*2 {
 
 		m.scaled = image.NewRGBA(image.Rect(0, 0, m.props.Width, m.props.Height*2))
 		draw.CatmullRom.Scale(m.scaled, m.scaled.Rect, m.image, m.image.Bounds(), draw.Over, nil)
@@ -0,0 +1,22 @@
+package tabs
+
+import "github.com/charmbracelet/lipgloss"
+
+var (
+	border      = lipgloss.NormalBorder()
+	style       = lipgloss.NewStyle().Padding(0, 1).Border(border, true)
+	activeStyle = lipgloss.NewStyle().Padding(0, 1).BorderForeground(lipgloss.Color("#F25D94")).Border(border, true).Foreground(lipgloss.Color("#F25D94"))
+)
+
+// View is a row of boxed tab names, with the active one highlighted
+func View(names []string, active int) string {
+	rendered := make([]string, len(names))
+	for i, name := range names {
+		if i == active {
+			rendered[i] = activeStyle.Render(name)
+		} else {
+			rendered[i] = style.Render(name)
+		}
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, rendered...)
+}
@@ -11,7 +11,9 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/mattn/go-runewidth"
 	"github.com/muesli/reflow/ansi"
+	"github.com/muesli/reflow/truncate"
 	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/util"
 )
 
 var (
@@ -21,7 +23,8 @@ var (
 	focusedStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25D94"))
 	// blurredStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
 	// cursorStyle  = focusedStyle.Copy()
-	noStyle = lipgloss.NewStyle()
+	noStyle    = lipgloss.NewStyle()
+	errorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("197")).PaddingLeft(1)
 )
 
 type Model struct {
@@ -29,12 +32,16 @@ type Model struct {
 	inner       textinput.Model
 	focused     bool
 	placeholder string
+	validators  []Validator
+	serverErr   string // Set by SetError, cleared by editing
+	dirty       bool   // Errors only show once the value has been edited
+	maxLength   int    // See MaxLength, 0 if unset
 }
 
 func New(p common.Props) *Model {
 	inner := textinput.New()
 
-	m := &Model{p, inner, false, ""}
+	m := &Model{props: p, inner: inner}
 
 	m.SetSize(p.Width, p.Height)
 
@@ -87,6 +94,10 @@ func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
 			prevValue := m.inner.Value()
 			prevPos := m.inner.Position()
 			m.inner, cmd = m.inner.Update(msg.KeyMsg)
+			if m.inner.Value() != prevValue {
+				m.dirty = true
+				m.serverErr = ""
+			}
 			if m.inner.Value() != prevValue ||
 				m.inner.Position() != prevPos {
 				msg.Handled = true
@@ -101,11 +112,50 @@ func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
 }
 
 func (m *Model) View() string {
+	var view string
 	if m.focused {
-		return focusedInputStyle.Render(m.inner.View())
+		view = focusedInputStyle.Render(m.inner.View())
 	} else {
-		return inputStyle.Render(m.inner.View())
+		view = inputStyle.Render(m.inner.View())
+	}
+
+	if m.validators == nil {
+		return view
+	}
+
+	// Validated fields are always a line taller so forms don't jump around
+	var err string
+	if m.serverErr != "" || (m.dirty && !m.focused) || m.overLimit() {
+		err = m.Error()
 	}
+	return view + "\n" + errorStyle.Render(truncate.StringWithTail(err, uint(util.Max(m.props.Width-1, 1)), "…"))
+}
+
+// Validate adds validators, which are checked in order. Fields with validators render their error below.
+func (m *Model) Validate(validators ...Validator) {
+	if m.validators == nil {
+		m.validators = []Validator{}
+	}
+	m.validators = append(m.validators, validators...)
+}
+
+// Error is the server error if there is one, or the first failing validator's message
+func (m *Model) Error() string {
+	if m.serverErr != "" {
+		return m.serverErr
+	}
+	for _, v := range m.validators {
+		if err := v(m.Value()); err != "" {
+			return err
+		}
+	}
+	return ""
+}
+
+// SetError shows an error from the server on this field until it's edited
+func (m *Model) SetError(err string) {
+	m.serverErr = err
+	m.dirty = true
 }
 
 // textinput model
@@ -147,6 +197,19 @@ func (m *Model) EchoCharacter(e rune) {
 func (m *Model) CharLimit(c int) {
 	m.inner.CharLimit = c
 }
+
+// MaxLength is CharLimit with an error. One more character is let in, so going over
+// the limit, say with a pasted password, shows why instead of being cut off quietly.
+func (m *Model) MaxLength(n int) {
+	m.maxLength = n
+	m.inner.CharLimit = n + 1
+	m.Validate(MaxLength(n))
+}
+
+// Shown while typing, since there's no point typing more
+func (m *Model) overLimit() bool {
+	return m.maxLength > 0 && utf8.RuneCountInString(m.Value()) > m.maxLength
+}
 func (m *Model) Cursor(c cursor.Model) {
 	m.inner.Cursor = c
 }
@@ -0,0 +1,70 @@
+package textfield
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// A Validator returns what's wrong with value, or "" if nothing is
+type Validator func(value string) string
+
+func Required(label string) Validator {
+	return func(value string) string {
+		if strings.TrimSpace(value) == "" {
+			return label + " is required."
+		}
+		return ""
+	}
+}
+
+// Email only catches typos, review-api has the final say
+func Email() Validator {
+	return func(value string) string {
+		at := strings.LastIndex(value, "@")
+		if at < 1 || !strings.Contains(value[at+1:], ".") || strings.HasSuffix(value, ".") || strings.ContainsAny(value, " \t") {
+			return "That doesn't look like an email."
+		}
+		return ""
+	}
+}
+
+func MinLength(n int) Validator {
+	return func(value string) string {
+		if utf8.RuneCountInString(value) < n {
+			return fmt.Sprintf("At least %d characters.", n)
+		}
+		return ""
+	}
+}
+
+// MaxLength fails over n characters. Use Model.MaxLength, since the field's
+// CharLimit stops typing at the limit and this would never fail.
+func MaxLength(n int) Validator {
+	return func(value string) string {
+		if utf8.RuneCountInString(value) > n {
+			return fmt.Sprintf("At most %d characters.", n)
+		}
+		return ""
+	}
+}
+
+// Match fails unless value is the same as other's, eg for retyped passwords
+func Match(other *Model, msg string) Validator {
+	return func(value string) string {
+		if value != other.Value() {
+			return msg
+		}
+		return ""
+	}
+}
+
+// AllValid is true if every field passes its validators and has no server error
+func AllValid(fields ...*Model) bool {
+	for _, f := range fields {
+		if f.Error() != "" {
+			return false
+		}
+	}
+	return true
+}
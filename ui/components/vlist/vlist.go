@@ -105,6 +105,23 @@ func (m *Model) SetItems(items []common.Focusable) {
 	}
 }
 
+// AppendItems keeps the active item, unlike SetItems
+func (m *Model) AppendItems(items ...common.Focusable) {
+	for _, item := range items {
+		if sizable, ok := item.(common.Sizable); ok {
+			sizable.SetSize(m.props.Width, m.ItemHeight)
+		}
+	}
+	m.items = append(m.items, items...)
+	if len(m.items) == len(items) && len(items) > 0 {
+		m.items[m.active].Focus()
+	}
+}
+
+func (m *Model) Active() int {
+	return m.active
+}
+
 func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
 	if len(m.items) == 0 {
 		return m, nil
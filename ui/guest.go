@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/util"
+)
+
+var bannerStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFF7DB")).Background(lipgloss.Color("#F25D94")).Padding(0, 1)
+
+func reviewsText(n int) string {
+	if n == 1 {
+		return "1 review"
+	}
+	return fmt.Sprintf("%d reviews", n)
+}
+
+func (m *Model) guest() bool {
+	g := m.props.Global
+	return g.AuthState.Authed && g.AuthState.User.Id == common.GuestAuthState.User.Id
+}
+
+// Reviews only kept in this session
+func (m *Model) guestReviews() int {
+	if m.props.Global.Guest == nil {
+		return 0
+	}
+	return m.props.Global.Guest.Len()
+}
+
+// A line above the app bar when the server is stopping, for guests, or after keeping their reviews
+func (m *Model) banner() string {
+	switch {
+	case !m.shutdownAt.IsZero():
+		if n := m.guestReviews(); n > 0 {
+			return m.shutdownText() + fmt.Sprintf(" · your %s as a guest will be lost", reviewsText(n))
+		}
+		return m.shutdownText()
+	case m.notice != "":
+		return m.notice
+	case m.guest():
+		return fmt.Sprintf("Guest mode · your %s only last until you leave · press A on your lists to sign up and keep them", reviewsText(m.guestReviews()))
+	}
+	return ""
+}
+
+func (m *Model) bannerView() string {
+	width := util.Max(m.props.Width-bannerStyle.GetHorizontalPadding(), 1)
+	return bannerStyle.Width(m.props.Width).Render(util.TruncAndPadUnicode(m.banner(), width))
+}
+
+func (m *Model) confirmQuit() {
+	text := "Quit program?"
+	if n := m.guestReviews(); n > 0 {
+		text = fmt.Sprintf("Quit program?\nYour %s as a guest will be lost.", reviewsText(n))
+	}
+	m.dialog.SetText(text)
+	m.dialog.Focus()
+}
+
+// Saves the guest's reviews to the account just signed in to, then reloads the lists
+func (m *Model) migrateGuest() tea.Cmd {
+	g := m.props.Global
+	return func() tea.Msg {
+		kept, err := g.Guest.Migrate(context.Background(), g)
+		return func() tea.Msg {
+			m.notice = fmt.Sprintf("Kept %s from your guest session.", reviewsText(kept))
+			if err != nil {
+				m.notice = fmt.Sprintf("Kept %s from your guest session, the rest failed (%v).", reviewsText(kept), err)
+			}
+			m.SetSize(m.props.Width, m.props.Height)
+			return m.listsPage.Init()
+		}
+	}
+}
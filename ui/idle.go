@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zhengkyl/review-ssh/ui/common"
+)
+
+var idleStyle = lipgloss.NewStyle().Padding(1, 3).Border(lipgloss.RoundedBorder(), true).BorderForeground(lipgloss.Color("#F25D94"))
+
+// How long before the idle timeout the warning shows, at most half the timeout
+const idleWarning = time.Minute
+
+type idleTick struct{}
+
+type shutdownTick struct{}
+
+func (m *Model) idleWarningAt() time.Duration {
+	timeout := m.props.Global.Config.IdleTimeout
+	if timeout/2 < idleWarning {
+		return timeout / 2
+	}
+	return timeout - idleWarning
+}
+
+// watchIdle checks again when the warning is due, or every second to count down once it shows.
+// Keys don't reschedule it, the check just finds there's longer to go.
+func (m *Model) watchIdle() tea.Cmd {
+	if m.props.Global.Config.IdleTimeout <= 0 {
+		return nil
+	}
+	wait := time.Second
+	if !m.idleWarned {
+		wait = m.idleWarningAt() - time.Since(m.lastKey)
+	}
+	return tea.Tick(wait, func(time.Time) tea.Msg { return idleTick{} })
+}
+
+func (m *Model) checkIdle() tea.Cmd {
+	idle := time.Since(m.lastKey)
+	switch {
+	case idle >= m.props.Global.Config.IdleTimeout:
+		return tea.Quit
+	case idle >= m.idleWarningAt():
+		m.idleWarned = true
+	}
+	return m.watchIdle()
+}
+
+func (m *Model) idleView() string {
+	left := m.props.Global.Config.IdleTimeout - time.Since(m.lastKey)
+	return idleStyle.Render(fmt.Sprintf("Still there?\n\nYou'll be disconnected in %s.\nPress any key to stay.", left.Round(time.Second)))
+}
+
+func (m *Model) shuttingDown(msg common.ShuttingDown) tea.Cmd {
+	m.shutdownAt = msg.At
+	m.SetSize(m.props.Width, m.props.Height)
+	return m.watchShutdown()
+}
+
+// Counts down the banner until the server closes the session
+func (m *Model) watchShutdown() tea.Cmd {
+	if time.Now().After(m.shutdownAt) {
+		return nil
+	}
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return shutdownTick{} })
+}
+
+func (m *Model) shutdownText() string {
+	left := time.Until(m.shutdownAt).Round(time.Second)
+	if left <= 0 {
+		return "Server restarting now · reconnect in a minute"
+	}
+	return fmt.Sprintf("Server restarting in %s · finish what you're doing, then reconnect in a minute", left)
+}
@@ -3,29 +3,107 @@ package keymap
 import "github.com/charmbracelet/bubbles/key"
 
 type KeyMap struct {
-	Quit   key.Binding
-	Help   key.Binding
-	Search key.Binding
-	NextX  key.Binding
-	PrevX  key.Binding
-	NextY  key.Binding
-	PrevY  key.Binding
-	Up     key.Binding
-	Down   key.Binding
-	Left   key.Binding
-	Right  key.Binding
-	Select key.Binding
-	Back   key.Binding
-	Move   key.Binding
+	Quit      key.Binding
+	Help      key.Binding
+	Search    key.Binding
+	Filter    key.Binding
+	Pin       key.Binding
+	Sort      key.Binding
+	Reverse   key.Binding
+	Liked     key.Binding
+	Starred   key.Binding
+	HasText   key.Binding
+	Find      key.Binding
+	Mark      key.Binding
+	MarkRange key.Binding
+	MarkAll   key.Binding
+	Batch     key.Binding
+	Import    key.Binding
+	Export    key.Binding
+	Copy      key.Binding
+	Add       key.Binding
+	ForYou    key.Binding
+	Settings  key.Binding
+	Discover  key.Binding
+	Period    key.Binding
+	People    key.Binding
+	Follow    key.Binding
+	Profile   key.Binding
+	Groups    key.Binding
+	New       key.Binding
+	Vote      key.Binding
+	Pick      key.Binding
+	Remove    key.Binding
+	Stats     key.Binding
+	Watched   key.Binding
+	Rewatch   key.Binding
+	Note      key.Binding
+	Earlier   key.Binding
+	Later     key.Binding
+	Diary     key.Binding
+	Account   key.Binding
+	Available key.Binding
+	Submit    key.Binding
+	Format    key.Binding
+	NextX     key.Binding
+	PrevX     key.Binding
+	NextY     key.Binding
+	PrevY     key.Binding
+	Up        key.Binding
+	Down      key.Binding
+	Left      key.Binding
+	Right     key.Binding
+	Select    key.Binding
+	Back      key.Binding
+	Move      key.Binding
 }
 
 func DefaultKeyMap() *KeyMap {
 	km := KeyMap{
-		Quit:   key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
-		Help:   key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
-		Search: key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "search")),
-		NextX:  key.NewBinding(key.WithKeys("tab", "right", "l"), key.WithHelp("tab", "next tab")),
-		PrevX:  key.NewBinding(key.WithKeys("shift+tab", "left", "h"), key.WithHelp("shift+tab", "prev tab")),
+		Quit:      key.NewBinding(key.WithKeys("q", "ctrl+c"), key.WithHelp("q", "quit")),
+		Help:      key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+		Search:    key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "search")),
+		Filter:    key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "filters")),
+		Pin:       key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "pin search")),
+		Sort:      key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "sort")),
+		Reverse:   key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "reverse")),
+		Liked:     key.NewBinding(key.WithKeys("1"), key.WithHelp("1", "liked")),
+		Starred:   key.NewBinding(key.WithKeys("2"), key.WithHelp("2", "starred")),
+		Available: key.NewBinding(key.WithKeys("4"), key.WithHelp("4", "on my services")),
+		HasText:   key.NewBinding(key.WithKeys("3"), key.WithHelp("3", "has text")),
+		Find:      key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "find")),
+		Mark:      key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "mark")),
+		MarkRange: key.NewBinding(key.WithKeys("X"), key.WithHelp("X", "mark range")),
+		MarkAll:   key.NewBinding(key.WithKeys("ctrl+a"), key.WithHelp("ctrl+a", "mark all")),
+		Batch:     key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "batch edit")),
+		Import:    key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "import")),
+		Export:    key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "export")),
+		Copy:      key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "copy")),
+		Add:       key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "plan to watch")),
+		ForYou:    key.NewBinding(key.WithKeys("w"), key.WithHelp("w", "for you")),
+		Settings:  key.NewBinding(key.WithKeys(","), key.WithHelp(",", "settings")),
+		Discover:  key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "discover")),
+		Period:    key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "today/this week")),
+		People:    key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "people")),
+		Follow:    key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "follow")),
+		Profile:   key.NewBinding(key.WithKeys("@"), key.WithHelp("@", "their lists")),
+		Groups:    key.NewBinding(key.WithKeys("g"), key.WithHelp("g", "groups")),
+		New:       key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "new")),
+		Vote:      key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "vote")),
+		Pick:      key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "pick tonight's film")),
+		Remove:    key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "remove")),
+		Stats:     key.NewBinding(key.WithKeys("m"), key.WithHelp("m", "my stats")),
+		Watched:   key.NewBinding(key.WithKeys("+"), key.WithHelp("+", "watched today")),
+		Rewatch:   key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "rewatch")),
+		Note:      key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "note")),
+		Earlier:   key.NewBinding(key.WithKeys("["), key.WithHelp("[", "earlier")),
+		Later:     key.NewBinding(key.WithKeys("]"), key.WithHelp("]", "later")),
+		Diary:     key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "diary")),
+		Account:   key.NewBinding(key.WithKeys("A"), key.WithHelp("A", "account")),
+		Submit:    key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("ctrl+s", "submit")),
+		Format:    key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("ctrl+t", "format")),
+		NextX:     key.NewBinding(key.WithKeys("tab", "right", "l"), key.WithHelp("tab", "next tab")),
+		PrevX:     key.NewBinding(key.WithKeys("shift+tab", "left", "h"), key.WithHelp("shift+tab", "prev tab")),
 		// TODO temp fix enter is NextY b/c input lists
 		NextY:  key.NewBinding(key.WithKeys("tab", "down", "j", "enter"), key.WithHelp("tab", "next tab")),
 		PrevY:  key.NewBinding(key.WithKeys("shift+tab", "up", "k"), key.WithHelp("shift+tab", "prev tab")),
@@ -1,11 +1,12 @@
 package account
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
-	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/zhengkyl/review-ssh/ui/common"
@@ -27,19 +28,26 @@ type Model struct {
 	buttons    *vlist.Model
 	focusIndex int
 	stage      int
+	form       *form // Sign in or sign up
 	err        string
 	help       help.Model
 }
 
 const (
-	picker = 0
-	signIn = 2
-	signUp = 3
+	picker    = 0
+	restoring = 1
+	signIn    = 2
+	signUp    = 3
 )
 
 type signInMsg struct{}
 type signUpMsg struct{}
 
+// A remembered session that couldn't be used
+type restoreRes struct {
+	err error
+}
+
 func New(p common.Props) *Model {
 	b := vlist.New(p, 3,
 		button.New(p, "Continue as guest", func() tea.Msg { return common.GuestAuthState }),
@@ -52,7 +60,7 @@ func New(p common.Props) *Model {
 
 	m := &Model{
 		props:      p,
-		inputs:     vlist.New(p, 3),
+		inputs:     newFormList(p),
 		buttons:    b,
 		focusIndex: 0,
 		help:       help.New(),
@@ -71,39 +79,103 @@ func (m *Model) SetSize(width, height int) {
 	m.buttons.SetSize(width, height)
 }
 
+// Reset goes back to the picker, eg after signing out
+func (m *Model) Reset() {
+	m.stage = picker
+	m.form = nil
+	m.err = ""
+	m.inputs.SetItems(nil)
+}
+
+// Restore signs back in with the session remembered for this connection, if there is one
+func (m *Model) Restore() tea.Cmd {
+	g := m.props.Global
+	auth, ok := g.Sessions.Remembered()
+	if !ok {
+		return nil
+	}
+
+	m.stage = restoring
+	return func() tea.Msg {
+		renewed, err := Validate(g.HttpClient, auth)
+		common.Audit(g, "sign_in", "via", "remembered", "user_id", auth.User.Id, "error", err)
+		if err != nil {
+			if errors.Is(err, ErrSessionExpired) {
+				g.Sessions.Forget()
+			}
+			return restoreRes{err}
+		}
+		g.Sessions.Remember(renewed)
+		return renewed
+	}
+}
+
+// SignUp opens the sign up form with a note, eg for guests keeping their reviews
+func (m *Model) SignUp(note string) {
+	m.stage = signUp
+	m.form = signUpForm(m.props)
+	m.inputs.SetItems(m.form.items())
+	m.err = note
+}
+
+// Expired asks the user to sign in again as email, eg after a 401
+func (m *Model) Expired(email string) {
+	m.stage = signIn
+	m.form = signInForm(m.props)
+	m.form.fields[0].SetValue(email)
+	m.inputs.SetItems(m.form.items())
+	m.err = ErrSessionExpired.Error()
+}
+
 func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
 
 	case signUpRes:
-		m.err = msg.err
+		if m.form != nil {
+			m.err = m.form.setError(msg.field, msg.err)
+		}
 	case signInRes:
 		m.err = msg.err
+	case restoreRes:
+		m.stage = picker
+		m.err = msg.err.Error()
+		if !errors.Is(msg.err, ErrSessionExpired) {
+			m.err = fmt.Sprintf("Could not sign you back in (%v)", msg.err)
+		}
 	case signInMsg:
 		m.stage = signIn
-		m.inputs.SetItems(signInInputs(m.props))
+		m.form = signInForm(m.props)
+		m.inputs.SetItems(m.form.items())
 		m.err = ""
 	case signUpMsg:
 		m.stage = signUp
-		m.inputs.SetItems(signUpInputs(m.props))
+		m.form = signUpForm(m.props)
+		m.inputs.SetItems(m.form.items())
 		m.err = ""
 	case *common.KeyEvent:
 		switch {
 		case key.Matches(msg.KeyMsg, m.props.Global.KeyMap.Back):
-			if m.stage != 0 {
+			if m.stage != picker {
 				msg.Handled = true
-				m.stage = 0
+				m.stage = picker
+				m.err = ""
 			}
 		}
 	}
 
 	var cmd tea.Cmd
 
-	if m.stage == picker {
+	switch m.stage {
+	case picker:
 		_, cmd = m.buttons.Update(msg)
-	} else {
+	case restoring:
+	default:
 		_, cmd = m.inputs.Update(msg)
+		if m.form != nil {
+			m.form.refresh()
+		}
 	}
 	cmds = append(cmds, cmd)
 
@@ -113,10 +185,16 @@ func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
 func (m *Model) View() string {
 	sb := strings.Builder{}
 
-	if m.stage == picker {
+	switch m.stage {
+	case picker:
 		sb.WriteString("Ahoy there!\n")
 		sb.WriteString(m.buttons.View())
-	} else {
+		if m.err != "" {
+			sb.WriteString("\n" + errStyle.Render(m.err))
+		}
+	case restoring:
+		sb.WriteString("Signing you back in...")
+	default:
 
 		if m.stage == signIn {
 			sb.WriteString(" Sign in")
@@ -135,102 +213,56 @@ func (m *Model) View() string {
 	return accountStyle.Render(sb.String())
 }
 
-func signUpInputs(p common.Props) []common.Focusable {
-	inputs := make([]common.Focusable, 0, 5)
-
-	ic := common.Props{
-		Width:  p.Width,
-		Height: 3, // TODO does nothing
-		Global: p.Global,
-	}
+const minPasswordLength = 8
 
-	for i := 0; i < 4; i++ {
-		input := textfield.New(ic)
-		input.CharLimit(80)
-
-		switch i {
-		case 0:
-			input.Placeholder("Name")
-			input.Focus()
-		case 1:
-			input.Placeholder("Email")
-		case 2:
-			input.Placeholder("Password")
-			input.EchoMode(textinput.EchoPassword)
-		case 3:
-			input.Placeholder("Retype password")
-			input.EchoMode(textinput.EchoPassword)
-		}
-
-		inputs = append(inputs, input)
-	}
+// Field order in the sign up form, see signUpError
+const (
+	noField = iota - 1
+	nameField
+	emailField
+	passwordField
+)
 
-	bp := common.Props{
-		Width:  p.Width,
-		Height: 1, // TODO does nothing
-		Global: p.Global,
-	}
+func signUpForm(p common.Props) *form {
+	name := newField(p, "Name", false)
+	name.Validate(textfield.Required("Name"))
 
-	button := button.New(bp, "Sign up", func() tea.Msg {
-		if inputs[2].(*textfield.Model).Value() != inputs[3].(*textfield.Model).Value() {
-			return signUpRes{false, "Passwords do not match."}
-		}
+	email := newField(p, "Email", false)
+	email.Validate(textfield.Required("Email"), textfield.Email())
 
-		return postSignUp(bp.Global.HttpClient, signUpData{
-			inputs[0].(*textfield.Model).Value(),
-			inputs[1].(*textfield.Model).Value(),
-			inputs[2].(*textfield.Model).Value(),
-		})
-	})
-	// button.Style.Normal.Margin(1).MarginBottom(0)
-	// button.Style.Active.Margin(1).MarginBottom(0)
+	password := newField(p, "Password", true)
+	password.Validate(textfield.Required("Password"), textfield.MinLength(minPasswordLength))
 
-	inputs = append(inputs, button)
+	retype := newField(p, "Retype password", true)
+	retype.Validate(textfield.Match(password, "Passwords do not match."))
 
-	return inputs
+	return newForm(p, "Sign up", func(values []string) tea.Msg {
+		return postSignUp(p.Global, signUpData{values[0], values[1], values[2]})
+	}, name, email, password, retype)
 }
 
-func signInInputs(p common.Props) []common.Focusable {
-	inputs := make([]common.Focusable, 0, 3)
+func signInForm(p common.Props) *form {
+	email := newField(p, "Email", false)
+	email.Validate(textfield.Required("Email"))
 
-	ic := common.Props{
-		Width:  p.Width,
-		Height: 3, // TODO does nothing
-		Global: p.Global,
+	password := newField(p, "Password", true)
+	password.Validate(textfield.Required("Password"))
+
+	var remember *labelled
+	if p.Global.Sessions.CanRemember() {
+		remember = newLabelled(p, "Remember me with this ssh key")
 	}
 
-	for i := 0; i < 2; i++ {
-		input := textfield.New(ic)
-		input.CharLimit(80)
-
-		switch i {
-		case 0:
-			input.Placeholder("Email")
-			input.Focus()
-		case 1:
-			input.Placeholder("Password")
-			input.EchoMode(textinput.EchoPassword)
+	f := newForm(p, "Sign in", func(values []string) tea.Msg {
+		msg := postSignIn(p.Global, signInData{values[0], values[1]})
+		if auth, ok := msg.(common.AuthState); ok && remember != nil && remember.Checked {
+			p.Global.Sessions.Remember(auth)
 		}
+		return msg
+	}, email, password)
 
-		inputs = append(inputs, input)
+	if remember != nil {
+		f.options = append(f.options, remember)
 	}
-
-	bp := common.Props{
-		Width:  p.Width,
-		Height: 1, // TODO does nothing
-		Global: p.Global,
-	}
-
-	button := button.New(bp, "Sign in", func() tea.Msg {
-		return postSignIn(p.Global.HttpClient, signInData{
-			inputs[0].(*textfield.Model).Value(),
-			inputs[1].(*textfield.Model).Value(),
-		})
-	})
-	// button.Style.Normal.Margin(1).MarginBottom(0)
-	// button.Style.Active.Margin(1).MarginBottom(0)
-
-	inputs = append(inputs, button)
-
-	return inputs
+	return f
 }
@@ -3,6 +3,12 @@ package account
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/hashicorp/go-retryablehttp"
@@ -15,37 +21,59 @@ type signUpData struct {
 	Password string `json:"password"`
 }
 
+// Keeps the password out of logs, which print Stringers
+func (d signUpData) String() string {
+	return fmt.Sprintf("{%s %s [redacted]}", d.Name, d.Email)
+}
+
+// A failed sign up, on a field of the form if review-api says which
 type signUpRes struct {
-	ok  bool
-	err string
+	field int
+	err   string
 }
 
-func postSignUp(client *retryablehttp.Client, data signUpData) tea.Msg {
+// review-api has no error codes, so the field is guessed from the status and message
+func signUpError(status int, body string) (int, string) {
+	body = strings.ToLower(body)
+	switch {
+	case status == http.StatusConflict || strings.Contains(body, "already") || strings.Contains(body, "unique") || strings.Contains(body, "duplicate"):
+		return emailField, "Email already registered."
+	case strings.Contains(body, "email"):
+		return emailField, "review-api rejected this email."
+	case strings.Contains(body, "password"):
+		return passwordField, "review-api rejected this password."
+	case strings.Contains(body, "name"):
+		return nameField, "review-api rejected this name."
+	case status >= 500:
+		return noField, fmt.Sprintf("review-api is having trouble (%d), try again later.", status)
+	}
+	return noField, fmt.Sprintf("Could not sign up (%d).", status)
+}
+
+// Signs in right after signing up, so this returns a signUpRes, signInRes or common.AuthState
+func postSignUp(g common.Global, data signUpData) tea.Msg {
 	bsLoginData, err := json.Marshal(data)
 
 	if err != nil {
-		return signUpRes{false, err.Error()}
+		return signUpRes{noField, err.Error()}
 	}
 
-	resp, err := client.Post(common.ReviewBase+"/users", "application/json", bytes.NewBuffer(bsLoginData))
+	resp, err := g.HttpClient.Post(common.ReviewBase+"/users", "application/json", bytes.NewBuffer(bsLoginData))
 
 	if err != nil {
-		return signUpRes{false, err.Error()}
+		return signUpRes{noField, err.Error()}
 	}
+	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return signUpRes{false, "Email already registered."}
-	}
-
-	var user common.User
-
-	err = json.NewDecoder(resp.Body).Decode(&user)
-
-	if err != nil {
-		return signUpRes{false, err.Error()}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		field, msg := signUpError(resp.StatusCode, string(body))
+		common.Audit(g, "sign_up", "email", data.Email, "name", data.Name, "error", common.StatusError(resp.StatusCode))
+		return signUpRes{field, msg}
 	}
 
-	return signUpRes{true, err.Error()}
+	common.Audit(g, "sign_up", "email", data.Email, "name", data.Name)
+	return postSignIn(g, signInData{data.Email, data.Password})
 }
 
 type signInData struct {
@@ -53,33 +81,55 @@ type signInData struct {
 	Password string `json:"password"`
 }
 
+func (d signInData) String() string {
+	return fmt.Sprintf("{%s [redacted]}", d.Email)
+}
+
 type signInRes struct {
 	ok  bool
 	err string
 }
 
-func postSignIn(client *retryablehttp.Client, data signInData) tea.Msg {
-	bsLoginData, err := json.Marshal(data)
-
+func postSignIn(g common.Global, data signInData) tea.Msg {
+	auth, err := limitedSignIn(g, data.Email, data.Password)
 	if err != nil {
 		return signInRes{false, err.Error()}
 	}
+	return auth
+}
 
-	resp, err := client.Post(common.ReviewBase+"/auth", "application/json", bytes.NewBuffer(bsLoginData))
+// limitedSignIn is SignIn, unless there have been too many attempts lately
+func limitedSignIn(g common.Global, email, password string) (common.AuthState, error) {
+	if g.SignIns != nil {
+		if ok, wait := g.SignIns.Allow(email); !ok {
+			common.Audit(g, "sign_in", "email", email, "user_id", 0, "error", "rate limited")
+			return common.AuthState{}, fmt.Errorf("Too many attempts to sign in, try again in %s.", wait.Round(time.Second))
+		}
+	}
+	auth, err := SignIn(g.HttpClient, email, password)
+	common.Audit(g, "sign_in", "email", email, "user_id", auth.User.Id, "error", err)
+	return auth, err
+}
+
+var ErrWrongPassword = errors.New("Wrong email or password.")
+
+// SignIn is the blocking version of the sign in form, also used to authenticate ssh commands
+func SignIn(client *retryablehttp.Client, email, password string) (common.AuthState, error) {
+	bsLoginData, err := json.Marshal(signInData{email, password})
 
 	if err != nil {
-		return signInRes{false, err.Error()}
+		return common.AuthState{}, err
 	}
 
-	if resp.StatusCode != 200 {
-		return signInRes{false, "Wrong email or password."}
+	resp, err := client.Post(common.ReviewBase+"/auth", "application/json", bytes.NewBuffer(bsLoginData))
+
+	if err != nil {
+		return common.AuthState{}, err
 	}
+	defer resp.Body.Close()
 
-	var cookie string
-	for _, c := range resp.Cookies() {
-		if c.Name == "id" {
-			cookie = c.Value
-		}
+	if resp.StatusCode != 200 {
+		return common.AuthState{}, ErrWrongPassword
 	}
 
 	var user common.User
@@ -87,12 +137,73 @@ func postSignIn(client *retryablehttp.Client, data signInData) tea.Msg {
 	err = json.NewDecoder(resp.Body).Decode(&user)
 
 	if err != nil {
-		return signInRes{false, err.Error()}
+		return common.AuthState{}, err
+	}
+	if user.Email == "" {
+		user.Email = email
 	}
 
-	return common.AuthState{
+	auth := common.AuthState{
 		Authed: true,
-		Cookie: cookie,
 		User:   user,
 	}
+	renew(&auth, resp, time.Now())
+	return auth, nil
+}
+
+// renew takes the session cookie from resp, if review-api set one
+func renew(auth *common.AuthState, resp *http.Response, now time.Time) {
+	for _, c := range resp.Cookies() {
+		if c.Name != "id" {
+			continue
+		}
+		auth.Cookie = c.Value
+		switch {
+		case c.MaxAge > 0:
+			auth.Expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+		default:
+			auth.Expires = c.Expires
+		}
+	}
+}
+
+var ErrSessionExpired = errors.New("Your session expired, sign in again.")
+
+// Validate checks a remembered session with review-api. The cookie is renewed if review-api sends a new one.
+func Validate(client *retryablehttp.Client, auth common.AuthState) (common.AuthState, error) {
+	if auth.Expired(time.Now()) {
+		return common.AuthState{}, ErrSessionExpired
+	}
+
+	req, err := retryablehttp.NewRequest("GET", common.ReviewBase+"/auth", nil)
+	if err != nil {
+		return common.AuthState{}, err
+	}
+	req.AddCookie(&http.Cookie{Name: "id", Value: auth.Cookie})
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return common.AuthState{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return common.AuthState{}, ErrSessionExpired
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return common.AuthState{}, common.StatusError(resp.StatusCode)
+	}
+
+	// The status is what matters, but the user may have changed their name elsewhere
+	var user common.User
+	if err := json.NewDecoder(resp.Body).Decode(&user); err == nil && user.Id == auth.User.Id {
+		if user.Email == "" {
+			user.Email = auth.User.Email
+		}
+		auth.User = user
+	}
+
+	auth.Authed = true
+	renew(&auth, resp, time.Now())
+	return auth, nil
 }
@@ -0,0 +1,100 @@
+package account
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/components/button"
+	"github.com/zhengkyl/review-ssh/ui/components/checkbox"
+	"github.com/zhengkyl/review-ssh/ui/components/textfield"
+	"github.com/zhengkyl/review-ssh/ui/components/vlist"
+)
+
+// Validated textfields are a line taller for their error, so forms have no gap
+const fieldHeight = 4
+
+// A stage's fields and a submit button that's disabled until they're all valid
+type form struct {
+	fields  []*textfield.Model
+	options []common.Focusable // Between the fields and submit, not validated
+	submit  *button.Model
+}
+
+func newField(p common.Props, placeholder string, password bool) *textfield.Model {
+	input := textfield.New(common.Props{
+		Width:  p.Width,
+		Height: fieldHeight, // TODO does nothing
+		Global: p.Global,
+	})
+	input.MaxLength(80)
+	input.Placeholder(placeholder)
+	if password {
+		input.EchoMode(textinput.EchoPassword)
+	}
+	return input
+}
+
+func newForm(p common.Props, label string, onSubmit func(values []string) tea.Msg, fields ...*textfield.Model) *form {
+	f := &form{fields: fields}
+
+	f.submit = button.New(p, label, func() tea.Msg {
+		values := make([]string, len(fields))
+		for i, field := range fields {
+			values[i] = field.Value()
+		}
+		return onSubmit(values)
+	})
+	f.refresh()
+
+	return f
+}
+
+func newFormList(p common.Props) *vlist.Model {
+	inputs := vlist.New(p, fieldHeight)
+	inputs.ItemGap = 0
+	return inputs
+}
+
+func (f *form) items() []common.Focusable {
+	items := make([]common.Focusable, 0, len(f.fields)+len(f.options)+1)
+	for _, field := range f.fields {
+		items = append(items, field)
+	}
+	items = append(items, f.options...)
+	return append(items, f.submit)
+}
+
+// refresh should follow anything that may change a field
+func (f *form) refresh() {
+	f.submit.SetDisabled(!textfield.AllValid(f.fields...))
+}
+
+// setError puts a server error on a field, or returns it if there's no such field
+func (f *form) setError(field int, err string) string {
+	if field < 0 || field >= len(f.fields) {
+		return err
+	}
+	f.fields[field].SetError(err)
+	f.refresh()
+	return ""
+}
+
+// A checkbox with its label beside it
+type labelled struct {
+	*checkbox.Model
+	label string
+}
+
+func newLabelled(p common.Props, label string) *labelled {
+	return &labelled{checkbox.New(p), label}
+}
+
+func (l *labelled) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	_, cmd := l.Model.Update(msg)
+	return l, cmd
+}
+
+func (l *labelled) View() string {
+	return lipgloss.JoinHorizontal(lipgloss.Bottom, l.Model.View(), " "+l.label)
+}
@@ -0,0 +1,364 @@
+package account
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/components/button"
+	"github.com/zhengkyl/review-ssh/ui/components/dialog"
+	"github.com/zhengkyl/review-ssh/ui/components/textfield"
+	"github.com/zhengkyl/review-ssh/ui/components/vlist"
+	"github.com/zhengkyl/review-ssh/ui/util"
+)
+
+var (
+	managePageStyle = lipgloss.NewStyle().Margin(1, 2)
+	manageHintStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	manageTitle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25D94")).Render("Account")
+)
+
+const (
+	menu = iota
+	changeName
+	changeEmail
+	changePassword
+)
+
+const (
+	maxFormWidth  = 50
+	deleteConfirm = "delete"
+	deleteText    = "Delete your account and all your reviews?\nThis can't be undone."
+)
+
+// Manage is the signed in side of the account page: profile changes, signing out and deleting
+type Manage struct {
+	props   common.Props
+	menu    *vlist.Model
+	inputs  *vlist.Model
+	form    *form
+	dialog  *dialog.Model
+	confirm *textfield.Model
+	stage   int
+	pending bool // Waiting on review-api
+	err     string
+	status  string
+}
+
+func NewManage(p common.Props) *Manage {
+	m := &Manage{
+		props:  p,
+		menu:   vlist.New(p, 1),
+		inputs: newFormList(p),
+	}
+
+	m.confirm = textfield.New(common.Props{Width: 30, Height: 3, Global: p.Global})
+	m.confirm.CharLimit(20)
+	m.confirm.Placeholder(fmt.Sprintf("Type %q", deleteConfirm))
+
+	m.dialog = dialog.New(p, deleteText)
+	m.dialog.Input(m.confirm)
+	m.dialog.Buttons(
+		*button.New(p, "Delete", m.deleteAccount),
+		*button.New(p, "Cancel", func() tea.Msg {
+			return func() tea.Msg {
+				m.dialog.Blur()
+				return nil
+			}
+		}))
+
+	m.SetSize(p.Width, p.Height)
+	return m
+}
+
+func (m *Manage) SetSize(width, height int) {
+	m.props.Width = width - managePageStyle.GetHorizontalFrameSize()
+	m.props.Height = height - managePageStyle.GetVerticalFrameSize()
+
+	// title + name + email
+	m.menu.SetSize(m.props.Width, util.Max(m.props.Height-5, 1))
+	// title + error
+	m.inputs.SetSize(util.Min(m.props.Width, maxFormWidth), util.Max(m.props.Height-4, 3))
+}
+
+func (m *Manage) guest() bool {
+	return m.props.Global.AuthState.User.Id == common.GuestAuthState.User.Id
+}
+
+func (m *Manage) Init() tea.Cmd {
+	m.stage = menu
+	m.pending = false
+	m.err = ""
+	m.status = ""
+	m.dialog.Blur()
+
+	p := m.props
+	if m.guest() {
+		items := []common.Focusable{}
+		if n := p.Global.Guest.Len(); n > 0 {
+			label := fmt.Sprintf("Sign up and keep these %d reviews", n)
+			if n == 1 {
+				label = "Sign up and keep this review"
+			}
+			items = append(items, button.New(p, label, func() tea.Msg { return common.SignOut{KeepGuest: true} }))
+		}
+		label := "Sign in or sign up"
+		if len(items) > 0 {
+			label = "Sign in without them"
+		}
+		items = append(items, button.New(p, label, func() tea.Msg { return common.SignOut{} }))
+		m.menu.SetItems(items)
+		return nil
+	}
+
+	m.menu.SetItems([]common.Focusable{
+		button.New(p, "Change name    ", m.open(changeName)),
+		button.New(p, "Change email   ", m.open(changeEmail)),
+		button.New(p, "Change password", m.open(changePassword)),
+		button.New(p, "Sign out       ", common.SignOutCmd(p.Global)),
+		button.New(p, "Delete account ", func() tea.Msg {
+			return func() tea.Msg {
+				m.confirm.SetValue("")
+				m.dialog.SetText(deleteText)
+				m.dialog.Focus()
+				return nil
+			}
+		}),
+	})
+	return nil
+}
+
+func (m *Manage) open(stage int) tea.Cmd {
+	return func() tea.Msg {
+		return func() tea.Msg {
+			m.stage = stage
+			m.err = ""
+			m.status = ""
+			m.form = m.newForm(stage)
+			m.inputs.SetItems(m.form.items())
+			return nil
+		}
+	}
+}
+
+// Fails if value, trimmed, is what it already is
+func unchanged(current, msg string) textfield.Validator {
+	return func(value string) string {
+		if strings.TrimSpace(value) == current {
+			return msg
+		}
+		return ""
+	}
+}
+
+func (m *Manage) newForm(stage int) *form {
+	p := m.props
+	p.Width = util.Min(p.Width, maxFormWidth)
+	user := p.Global.AuthState.User
+
+	current := newField(p, "Current password", true)
+	current.Validate(textfield.Required("Current password"))
+
+	submit := func(values []string) tea.Msg {
+		return m.submit(stage, values)
+	}
+
+	switch stage {
+	case changeName:
+		name := newField(p, "New name", false)
+		name.Validate(textfield.Required("Name"), unchanged(user.Name, "That's already your name."))
+		return newForm(p, "Save", submit, name)
+	case changeEmail:
+		email := newField(p, "New email", false)
+		email.Validate(textfield.Required("Email"), textfield.Email(), unchanged(user.Email, "That's already your email."))
+		return newForm(p, "Save", submit, email, current)
+	default:
+		password := newField(p, "New password", true)
+		password.Validate(textfield.Required("Password"), textfield.MinLength(minPasswordLength))
+		retype := newField(p, "Retype new password", true)
+		retype.Validate(textfield.Match(password, "Passwords do not match."))
+		return newForm(p, "Save", submit, current, password, retype)
+	}
+}
+
+// Runs as a command. Re-auths with the current password if needed, then patches the user.
+// The form is already valid, but review-api may still reject it.
+func (m *Manage) submit(stage int, values []string) tea.Msg {
+	g := m.props.Global
+	user := g.AuthState.User
+
+	var password, done string
+	currentField := noField
+	updates := map[string]interface{}{}
+	switch stage {
+	case changeName:
+		updates["name"] = strings.TrimSpace(values[0])
+		done = "Name changed."
+	case changeEmail:
+		password, currentField = values[1], 1
+		updates["email"] = strings.TrimSpace(values[0])
+		done = "Email changed."
+	case changePassword:
+		password, currentField = values[0], 0
+		updates["password"] = values[1]
+		done = "Password changed."
+	}
+
+	setError := func(field int, problem string) {
+		if m.stage == stage {
+			m.err = m.form.setError(field, problem)
+		}
+	}
+	fail := func(field int, problem string) tea.Msg {
+		return func() tea.Msg {
+			setError(field, problem)
+			return nil
+		}
+	}
+
+	// Sensitive changes need the current password, even with a valid cookie
+	var cookie string
+	if stage != changeName {
+		auth, err := limitedSignIn(g, user.Email, password)
+		if errors.Is(err, ErrWrongPassword) {
+			return fail(currentField, "Wrong password.")
+		}
+		if err != nil {
+			return fail(noField, err.Error())
+		}
+		cookie = auth.Cookie
+	}
+
+	return func() tea.Msg {
+		if m.stage != stage {
+			return nil
+		}
+		if cookie != "" {
+			g.AuthState.Cookie = cookie
+		}
+		m.pending = true
+		m.err = ""
+		return common.PatchUserCmd(g, updates, func(err error) tea.Msg {
+			m.pending = false
+			switch {
+			case err == nil:
+				m.stage = menu
+				m.status = done
+			case errors.Is(err, common.StatusError(http.StatusConflict)) && stage == changeEmail:
+				setError(0, "Email already registered.")
+			default:
+				m.err = fmt.Sprintf("Could not save (%v)", err)
+			}
+			return nil
+		})
+	}
+}
+
+// Runs as a command from the dialog's delete button
+func (m *Manage) deleteAccount() tea.Msg {
+	return func() tea.Msg {
+		if m.confirm.Value() != deleteConfirm {
+			m.dialog.SetText(deleteText + "\n" + errStyle.Render(fmt.Sprintf("Type %q to confirm.", deleteConfirm)))
+			return nil
+		}
+		m.dialog.Blur()
+		m.pending = true
+		return common.DeleteUserCmd(m.props.Global, func(err error) tea.Msg {
+			m.pending = false
+			if err != nil {
+				m.err = fmt.Sprintf("Could not delete account (%v)", err)
+				return nil
+			}
+			return tea.Cmd(func() tea.Msg { return common.SignOut{} })
+		})
+	}
+}
+
+func (m *Manage) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	if m.dialog.Focused() {
+		_, cmd := m.dialog.Update(msg)
+		// The dialog is modal
+		if event, ok := msg.(*common.KeyEvent); ok {
+			event.Handled = true
+		}
+		return m, cmd
+	}
+
+	if event, ok := msg.(*common.KeyEvent); ok {
+		if m.pending {
+			return m, nil
+		}
+		if key.Matches(event.KeyMsg, m.props.Global.KeyMap.Back) && m.stage != menu {
+			event.Handled = true
+			m.stage = menu
+			m.err = ""
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.stage == menu {
+		_, cmd = m.menu.Update(msg)
+	} else {
+		_, cmd = m.inputs.Update(msg)
+		m.form.refresh()
+	}
+	return m, cmd
+}
+
+func (m *Manage) View() string {
+	user := m.props.Global.AuthState.User
+	sb := strings.Builder{}
+
+	switch m.stage {
+	case menu:
+		sb.WriteString(manageTitle)
+		sb.WriteString("\n\n")
+		if m.guest() {
+			sb.WriteString("You're browsing as a guest.\n")
+			sb.WriteString(manageHintStyle.Render("Your reviews only last until you leave. Sign up to keep them, or sign in to add them to your account."))
+		} else {
+			sb.WriteString(util.TruncAndPadUnicode("Name", 7) + user.Name + "\n")
+			sb.WriteString(util.TruncAndPadUnicode("Email", 7) + user.Email)
+		}
+		sb.WriteString("\n\n")
+		sb.WriteString(m.menu.View())
+	case changeName:
+		sb.WriteString(manageTitle + " · Change name\n\n")
+		sb.WriteString(m.inputs.View())
+	case changeEmail:
+		sb.WriteString(manageTitle + " · Change email\n\n")
+		sb.WriteString(m.inputs.View())
+	case changePassword:
+		sb.WriteString(manageTitle + " · Change password\n\n")
+		sb.WriteString(m.inputs.View())
+	}
+
+	sb.WriteString("\n\n")
+	switch {
+	case m.pending:
+		sb.WriteString(manageHintStyle.Render("Saving..."))
+	case m.err != "":
+		sb.WriteString(errStyle.Render(m.err))
+	case m.status != "":
+		sb.WriteString(manageHintStyle.Render(m.status))
+	}
+
+	page := managePageStyle.Render(sb.String())
+
+	if m.dialog.Focused() {
+		// Tall enough for the dialog to sit over the middle
+		page = lipgloss.PlaceVertical(m.props.Height, lipgloss.Top, page)
+		dialogView := m.dialog.View()
+		xOffset := util.Max((m.props.Width-lipgloss.Width(dialogView))/2, 0)
+		yOffset := util.Max((m.props.Height-lipgloss.Height(dialogView))/2, 0)
+		page = util.RenderOverlay(page, dialogView, xOffset, yOffset)
+	}
+
+	return page
+}
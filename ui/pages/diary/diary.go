@@ -0,0 +1,450 @@
+package diary
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/components/tabs"
+	"github.com/zhengkyl/review-ssh/ui/util"
+)
+
+type tab int
+
+const (
+	timeline tab = iota
+	calendar
+)
+
+// This must match the order of tabs
+var tabNames = []string{
+	"Timeline",
+	"Calendar",
+}
+
+var NUM_TABS = len(tabNames)
+
+var (
+	pageStyle   = lipgloss.NewStyle().Margin(0, 1)
+	mutedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	activeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25D94"))
+	errStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("197"))
+)
+
+// Every film the user logged watching, by date
+type Model struct {
+	props     common.Props
+	activeTab tab
+	entries   []common.DiaryEntry // Newest first
+	active    int                 // Timeline entry
+	offset    int                 // Timeline line
+	day       time.Time           // Calendar selection
+	editing   bool                // Typing a note for the active entry
+	note      textinput.Model
+	err       string
+}
+
+func New(p common.Props) *Model {
+	note := textinput.New()
+	note.Prompt = "Note: "
+	note.CharLimit = 200
+
+	m := &Model{
+		props: p,
+		note:  note,
+	}
+	m.SetSize(p.Width, p.Height)
+	return m
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.props.Width = width - pageStyle.GetHorizontalFrameSize()
+	m.props.Height = height - pageStyle.GetVerticalFrameSize()
+	m.note.Width = util.Max(m.props.Width-10, 10)
+}
+
+func (m *Model) userId() int {
+	return m.props.Global.AuthState.User.Id
+}
+
+func (m *Model) today() time.Time {
+	now := m.props.Global.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (m *Model) Init() tea.Cmd {
+	m.editing = false
+	m.note.Blur()
+	m.err = ""
+	m.active = 0
+	m.offset = 0
+	m.day = m.today()
+	m.refresh()
+	return m.loadFilms()
+}
+
+// Refresh keeps the view and selection, for coming back from a film
+func (m *Model) Refresh() tea.Cmd {
+	m.refresh()
+	return m.loadFilms()
+}
+
+func (m *Model) refresh() {
+	m.entries = m.props.Global.Diary.Entries(m.userId())
+	m.active = util.Max(util.Min(m.active, len(m.entries)-1), 0)
+}
+
+// Fetches titles for entries on screen
+func (m *Model) loadFilms() tea.Cmd {
+	var shown []common.DiaryEntry
+	if m.activeTab == calendar {
+		shown = m.onDay(m.day)
+	} else {
+		for _, l := range m.visibleLines() {
+			if l.entry >= 0 {
+				shown = append(shown, m.entries[l.entry])
+			}
+		}
+	}
+
+	var cmds []tea.Cmd
+	for _, e := range shown {
+		cmds = append(cmds, common.GetFilmCmd(m.props.Global, e.Tmdb_id))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) onDay(day time.Time) []common.DiaryEntry {
+	date := day.Format(common.DiaryDateLayout)
+	entries := []common.DiaryEntry{}
+	for _, e := range m.entries {
+		if e.Watched == date {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+func (m *Model) update(entry common.DiaryEntry) {
+	if err := m.props.Global.Diary.Update(m.userId(), entry); err != nil {
+		m.err = err.Error()
+	}
+	id := entry.Id
+	m.refresh()
+	// Changing the date can move the entry
+	for i, e := range m.entries {
+		if e.Id == id {
+			m.active = i
+		}
+	}
+}
+
+func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case *common.KeyEvent:
+		km := m.props.Global.KeyMap
+
+		if m.editing {
+			msg.Handled = true
+			switch msg.KeyMsg.Type {
+			case tea.KeyEsc:
+				m.editing = false
+				m.note.Blur()
+				return m, nil
+			case tea.KeyEnter:
+				m.editing = false
+				m.note.Blur()
+				entry := m.entries[m.active]
+				entry.Note = strings.TrimSpace(m.note.Value())
+				m.update(entry)
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.note, cmd = m.note.Update(msg.KeyMsg)
+			return m, cmd
+		}
+
+		if m.activeTab == calendar {
+			if cmd, ok := m.updateCalendar(msg); ok {
+				msg.Handled = true
+				return m, tea.Batch(cmd, m.loadFilms())
+			}
+		}
+
+		switch {
+		case key.Matches(msg.KeyMsg, km.NextX):
+			msg.Handled = true
+			m.activeTab = tab((int(m.activeTab) + 1) % NUM_TABS)
+			return m, m.loadFilms()
+		case key.Matches(msg.KeyMsg, km.PrevX):
+			msg.Handled = true
+			m.activeTab = tab((int(m.activeTab) - 1 + NUM_TABS) % NUM_TABS)
+			return m, m.loadFilms()
+		}
+
+		if m.activeTab == timeline && len(m.entries) > 0 {
+			msg.Handled = true
+			cmd := m.updateTimeline(msg)
+			return m, tea.Batch(cmd, m.loadFilms())
+		}
+	}
+
+	if m.editing {
+		var cmd tea.Cmd
+		m.note, cmd = m.note.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m *Model) updateTimeline(msg *common.KeyEvent) tea.Cmd {
+	km := m.props.Global.KeyMap
+	entry := m.entries[m.active]
+	m.err = ""
+
+	switch {
+	case key.Matches(msg.KeyMsg, km.Down):
+		m.active = util.Min(m.active+1, len(m.entries)-1)
+	case key.Matches(msg.KeyMsg, km.Up):
+		m.active = util.Max(m.active-1, 0)
+	case key.Matches(msg.KeyMsg, km.Select):
+		filmId := entry.Tmdb_id
+		return func() tea.Msg { return common.ShowFilm(filmId) }
+	case key.Matches(msg.KeyMsg, km.Liked):
+		entry.Fun_during = !entry.Fun_during
+		m.update(entry)
+	case key.Matches(msg.KeyMsg, km.Starred):
+		entry.Fun_after = !entry.Fun_after
+		m.update(entry)
+	case key.Matches(msg.KeyMsg, km.Rewatch):
+		entry.Rewatch = !entry.Rewatch
+		m.update(entry)
+	case key.Matches(msg.KeyMsg, km.Earlier), key.Matches(msg.KeyMsg, km.Later):
+		delta := 1
+		if key.Matches(msg.KeyMsg, km.Earlier) {
+			delta = -1
+		}
+		date := entry.Date().AddDate(0, 0, delta)
+		if date.After(m.today()) {
+			m.err = "Watches can't be in the future"
+			return nil
+		}
+		entry.Watched = date.Format(common.DiaryDateLayout)
+		m.update(entry)
+	case key.Matches(msg.KeyMsg, km.Note):
+		m.editing = true
+		m.note.SetValue(entry.Note)
+		m.note.CursorEnd()
+		return m.note.Focus()
+	case key.Matches(msg.KeyMsg, km.Remove):
+		if err := m.props.Global.Diary.Delete(m.userId(), entry.Id); err != nil {
+			m.err = err.Error()
+		}
+		m.refresh()
+	default:
+		msg.Handled = false
+	}
+	return nil
+}
+
+// Arrows move by day and week, [ and ] by month
+func (m *Model) updateCalendar(msg *common.KeyEvent) (tea.Cmd, bool) {
+	km := m.props.Global.KeyMap
+	switch {
+	case key.Matches(msg.KeyMsg, km.Left):
+		m.day = m.day.AddDate(0, 0, -1)
+	case key.Matches(msg.KeyMsg, km.Right):
+		m.day = m.day.AddDate(0, 0, 1)
+	case key.Matches(msg.KeyMsg, km.Up):
+		m.day = m.day.AddDate(0, 0, -7)
+	case key.Matches(msg.KeyMsg, km.Down):
+		m.day = m.day.AddDate(0, 0, 7)
+	case key.Matches(msg.KeyMsg, km.Earlier):
+		m.day = m.day.AddDate(0, -1, 0)
+	case key.Matches(msg.KeyMsg, km.Later):
+		m.day = m.day.AddDate(0, 1, 0)
+	case key.Matches(msg.KeyMsg, km.Select):
+		if entries := m.onDay(m.day); len(entries) > 0 {
+			filmId := entries[0].Tmdb_id
+			return func() tea.Msg { return common.ShowFilm(filmId) }, true
+		}
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+func (m *Model) filmTitle(filmId int) string {
+	if ok, _, film := m.props.Global.FilmCache.Get(filmId); ok {
+		if len(film.Release_date) >= 4 {
+			return film.Title + " (" + film.Release_date[:4] + ")"
+		}
+		return film.Title
+	}
+	return "Loading..."
+}
+
+// A month heading, or a timeline entry
+type line struct {
+	heading string
+	entry   int
+}
+
+func (m *Model) lines() []line {
+	lines := []line{}
+	month := ""
+	for i, e := range m.entries {
+		if heading := e.Date().Format("January 2006"); heading != month {
+			month = heading
+			lines = append(lines, line{heading, -1})
+		}
+		lines = append(lines, line{"", i})
+	}
+	return lines
+}
+
+// Lines that fit below the tabs and above the hint
+func (m *Model) listHeight() int {
+	return util.Max(m.props.Height-5, 1)
+}
+
+// Keeps the active entry, and its heading when there's room, in view
+func (m *Model) visibleLines() []line {
+	lines := m.lines()
+	activeLine := 0
+	for i, l := range lines {
+		if l.entry == m.active {
+			activeLine = i
+		}
+	}
+
+	if activeLine-1 < m.offset {
+		m.offset = util.Max(activeLine-1, 0)
+	} else if activeLine >= m.offset+m.listHeight() {
+		m.offset = activeLine - m.listHeight() + 1
+	}
+	m.offset = util.Max(util.Min(m.offset, len(lines)-1), 0)
+
+	end := util.Min(m.offset+m.listHeight(), len(lines))
+	return lines[m.offset:end]
+}
+
+func marks(e common.DiaryEntry) string {
+	rating := common.RenderRating(false, e.Fun_during, e.Fun_after)
+	if e.Rewatch {
+		return "↻ " + rating
+	}
+	return "  " + rating
+}
+
+func (m *Model) timelineView() string {
+	if len(m.entries) == 0 {
+		return mutedStyle.Render("Nothing logged yet. Press + on a film's details when you watch it.")
+	}
+
+	titleWidth := util.Max(m.props.Width/2, 10)
+	noteWidth := util.Max(m.props.Width-6-1-titleWidth-1-7-1, 2)
+
+	rows := []string{}
+	for _, l := range m.visibleLines() {
+		if l.entry < 0 {
+			rows = append(rows, activeStyle.Render(l.heading))
+			continue
+		}
+		e := m.entries[l.entry]
+		title := util.TruncAndPadUnicode(m.filmTitle(e.Tmdb_id), titleWidth)
+		if l.entry == m.active {
+			title = activeStyle.Render(title)
+		}
+		rows = append(rows, mutedStyle.Render(e.Date().Format("Mon 02"))+" "+title+" "+marks(e)+" "+
+			mutedStyle.Render(util.TruncAndPadUnicode(e.Note, noteWidth)))
+	}
+	return strings.Join(rows, "\n")
+}
+
+func (m *Model) calendarView() string {
+	counts := map[string]int{}
+	for _, e := range m.entries {
+		counts[e.Watched]++
+	}
+
+	first := time.Date(m.day.Year(), m.day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	sb := strings.Builder{}
+	sb.WriteString(activeStyle.Render(first.Format("January 2006")))
+	sb.WriteString(mutedStyle.Render("  [ ] months"))
+	sb.WriteString("\n")
+	sb.WriteString(mutedStyle.Render(" Mo  Tu  We  Th  Fr  Sa  Su"))
+	sb.WriteString("\n")
+
+	// Weeks start on Monday
+	sb.WriteString(strings.Repeat("    ", (int(first.Weekday())+6)%7))
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		cell := fmt.Sprintf("%2d", day.Day())
+		if counts[day.Format(common.DiaryDateLayout)] > 0 {
+			cell = activeStyle.Render(cell)
+		}
+		if day.Equal(m.day) {
+			cell = "[" + cell + "]"
+		} else {
+			cell = " " + cell + " "
+		}
+		sb.WriteString(cell)
+		if day.Weekday() == time.Sunday {
+			sb.WriteString("\n")
+		}
+	}
+	sb.WriteString("\n\n")
+
+	entries := m.onDay(m.day)
+	sb.WriteString(m.day.Format("Monday, January 2"))
+	switch len(entries) {
+	case 0:
+		sb.WriteString(mutedStyle.Render("  nothing watched"))
+	case 1:
+		sb.WriteString(mutedStyle.Render("  1 film"))
+	default:
+		sb.WriteString(mutedStyle.Render(fmt.Sprintf("  %d films", len(entries))))
+	}
+	for _, e := range entries {
+		sb.WriteString("\n")
+		sb.WriteString(util.TruncAndPadUnicode(m.filmTitle(e.Tmdb_id), util.Max(m.props.Width-8, 10)) + marks(e))
+	}
+	return sb.String()
+}
+
+func (m *Model) hintView() string {
+	switch {
+	case m.editing:
+		return m.note.View()
+	case m.err != "":
+		return errStyle.Render(m.err)
+	case m.activeTab == calendar:
+		return mutedStyle.Render("tab timeline · ←↓↑→ days · enter details")
+	case len(m.entries) == 0:
+		return mutedStyle.Render("tab calendar")
+	}
+	return mutedStyle.Render("enter details · 1 like · 2 star · r rewatch · [ ] date · n note · x delete")
+}
+
+func (m *Model) View() string {
+	sb := strings.Builder{}
+
+	sb.WriteString(tabs.View(tabNames, int(m.activeTab)))
+	sb.WriteString("\n")
+
+	if m.activeTab == calendar {
+		sb.WriteString(m.calendarView())
+	} else {
+		sb.WriteString(m.timelineView())
+	}
+
+	sb.WriteString(strings.Repeat("\n", util.Max(m.props.Height-1-lipgloss.Height(sb.String()), 1)))
+	sb.WriteString(m.hintView())
+
+	return pageStyle.Render(sb.String())
+}
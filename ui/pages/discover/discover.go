@@ -0,0 +1,266 @@
+package discover
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/components/tabs"
+	"github.com/zhengkyl/review-ssh/ui/components/vlist"
+	"github.com/zhengkyl/review-ssh/ui/pages/search/filmitem"
+	"github.com/zhengkyl/review-ssh/ui/util"
+)
+
+type tab int
+
+const (
+	trending tab = iota
+	popular
+	topRated
+	upcoming
+	nowPlaying
+)
+
+// This must match the order of tabs
+var tabNames = []string{
+	"Trending",
+	"Popular",
+	"Top Rated",
+	"Upcoming",
+	"Now Playing",
+}
+
+var NUM_TABS = len(tabNames)
+
+const itemHeight = 6
+
+var (
+	controlStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8")).PaddingLeft(1)
+	activeToggle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25D94"))
+)
+
+// One tab's films, loaded a TMDB page at a time
+type feed struct {
+	list       *vlist.Model
+	page       int // Last page loaded
+	totalPages int
+	loading    bool
+	err        string
+	region     string // For upcoming and now playing, which depend on it
+}
+
+type Model struct {
+	props     common.Props
+	activeTab tab
+	feeds     []*feed
+	weekly    bool // Trending this week instead of today
+}
+
+func New(p common.Props) *Model {
+	m := &Model{
+		props: p,
+		feeds: make([]*feed, NUM_TABS),
+	}
+	for i := range m.feeds {
+		m.resetFeed(tab(i))
+	}
+	m.SetSize(p.Width, p.Height)
+	return m
+}
+
+// Pages still loading for the old feed are dropped
+func (m *Model) resetFeed(t tab) {
+	list := vlist.New(m.props, itemHeight)
+	list.Overflow = vlist.Paginate
+	list.SetSize(m.props.Width, m.props.Height-5)
+	m.feeds[t] = &feed{list: list}
+}
+
+func (m *Model) region() string {
+	return m.props.Global.Settings.Get(m.props.Global.AuthState.User.Id).Region
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.props.Width = width
+	m.props.Height = height
+
+	// tabs + controls + paginator
+	for _, f := range m.feeds {
+		f.list.SetSize(width, height-5)
+	}
+}
+
+func (m *Model) url(t tab, page int) string {
+	g := m.props.Global
+	path := map[tab]string{
+		popular:    "movie/popular",
+		topRated:   "movie/top_rated",
+		upcoming:   "movie/upcoming",
+		nowPlaying: "movie/now_playing",
+	}[t]
+	if t == trending {
+		path = "trending/movie/day"
+		if m.weekly {
+			path = "trending/movie/week"
+		}
+	}
+
+	url := "https://api.themoviedb.org/3/" + path + "?page=" + strconv.Itoa(page) + "&api_key=" + g.Config.TMDB_API_KEY
+	// Release dates differ by country
+	if t == upcoming || t == nowPlaying {
+		url += "&region=" + m.region()
+	}
+	return url
+}
+
+// Loads the next page of t, if there is one
+func (m *Model) loadMore(t tab) tea.Cmd {
+	f := m.feeds[t]
+	if f.loading || (f.page > 0 && f.page >= f.totalPages) {
+		return nil
+	}
+	f.loading = true
+	f.err = ""
+	f.region = m.region()
+
+	page := f.page + 1
+	return common.Get[common.Paged[common.Film]](m.props.Global, m.url(t, page), func(data common.Paged[common.Film], err error) tea.Msg {
+		if m.feeds[t] != f {
+			return nil
+		}
+		f.loading = false
+		if err != nil {
+			f.err = fmt.Sprintf("Could not load films (%v)", err)
+			return nil
+		}
+		f.page = page
+		f.totalPages = data.Total_Pages
+
+		inits := make([]tea.Cmd, 0, len(data.Results))
+		items := make([]common.Focusable, 0, len(data.Results))
+		for _, film := range data.Results {
+			if ok, _, _ := m.props.Global.FilmCache.Get(film.Id); !ok {
+				m.props.Global.FilmCache.Set(film.Id, film)
+			}
+			item := filmitem.New(common.Props{Width: m.props.Width, Height: itemHeight, Global: m.props.Global}, film)
+			items = append(items, item)
+			inits = append(inits, item.Init())
+		}
+		f.list.AppendItems(items...)
+		return tea.Batch(inits...)
+	})
+}
+
+// Tabs keep their films, only the first visit loads
+func (m *Model) Init() tea.Cmd {
+	f := m.feeds[m.activeTab]
+	if (m.activeTab == upcoming || m.activeTab == nowPlaying) && f.page > 0 && f.region != m.region() {
+		m.resetFeed(m.activeTab)
+	}
+	return m.loadMore(m.activeTab)
+}
+
+func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	f := m.feeds[m.activeTab]
+
+	switch msg := msg.(type) {
+	case *common.KeyEvent:
+		km := m.props.Global.KeyMap
+
+		switch {
+		case key.Matches(msg.KeyMsg, km.NextX):
+			msg.Handled = true
+			m.activeTab = tab((int(m.activeTab) + 1) % NUM_TABS)
+			return m, m.Init()
+		case key.Matches(msg.KeyMsg, km.PrevX):
+			msg.Handled = true
+			m.activeTab = tab((int(m.activeTab) - 1 + NUM_TABS) % NUM_TABS)
+			return m, m.Init()
+		case m.activeTab == trending && key.Matches(msg.KeyMsg, km.Period):
+			msg.Handled = true
+			m.weekly = !m.weekly
+			m.resetFeed(trending)
+			return m, m.Init()
+		}
+
+		_, cmd := f.list.Update(msg)
+
+		// Fetch the next page while the last one is being read
+		if f.list.Length() > 0 && f.list.Active() >= f.list.Length()-f.list.PerPage() {
+			cmd = tea.Batch(cmd, m.loadMore(m.activeTab))
+		}
+		return m, cmd
+	}
+
+	var cmds []tea.Cmd
+	for _, f := range m.feeds {
+		_, cmd := f.list.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m *Model) controlsView() string {
+	controls := []string{}
+	if m.activeTab == trending {
+		day, week := "today", "this week"
+		if m.weekly {
+			week = activeToggle.Render(week)
+		} else {
+			day = activeToggle.Render(day)
+		}
+		controls = append(controls, "(t) "+day+" / "+week)
+	}
+	if m.activeTab == upcoming || m.activeTab == nowPlaying {
+		controls = append(controls, "in "+m.region()+", change with , on the lists page")
+	}
+	controls = append(controls, "←→ tabs · enter details")
+	return controlStyle.Render(strings.Join(controls, "  "))
+}
+
+func (m *Model) View() string {
+	f := m.feeds[m.activeTab]
+	sb := strings.Builder{}
+
+	sb.WriteString(tabs.View(tabNames, int(m.activeTab)))
+	sb.WriteString("\n")
+	sb.WriteString(lipgloss.NewStyle().MaxWidth(m.props.Width).Render(m.controlsView()))
+	sb.WriteString("\n")
+
+	switch {
+	case f.err != "" && f.list.Length() == 0:
+		sb.WriteString(f.err)
+	case f.list.Length() == 0:
+		sb.WriteString("Loading...")
+	default:
+		sb.WriteString(f.list.View())
+	}
+
+	viewH := m.props.Height - 1
+	sb.WriteString(strings.Repeat("\n", util.Max(viewH-lipgloss.Height(sb.String()), 0)))
+
+	paginator := ""
+	if f.list.Length() > 0 {
+		start := f.list.Offset() + 1
+		last := util.Min(f.list.Offset()+f.list.PerPage(), f.list.Length())
+		paginator = fmt.Sprintf("%d-%d of %d", start, last, f.list.Length())
+		if f.page < f.totalPages {
+			paginator += "+"
+		}
+	}
+	if f.loading && f.list.Length() > 0 {
+		paginator = "loading more... " + paginator
+	} else if f.err != "" && f.list.Length() > 0 {
+		paginator = f.err + " " + paginator
+	}
+
+	// filmitems have an internal horizontal framesize of 2
+	sb.WriteString(strings.Repeat(" ", util.Max(m.props.Width-lipgloss.Width(paginator)-2, 0)))
+	sb.WriteString(paginator)
+
+	return sb.String()
+}
@@ -0,0 +1,261 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/components/button"
+	"github.com/zhengkyl/review-ssh/ui/components/vlist"
+	"github.com/zhengkyl/review-ssh/ui/util"
+)
+
+var (
+	pageStyle   = lipgloss.NewStyle().Margin(1, 2)
+	hintStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	accentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25D94"))
+	errStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("197"))
+)
+
+var formatNames = map[Format]string{
+	JSON:       "JSON (everything)",
+	CSV:        "CSV (spreadsheets)",
+	Markdown:   "Markdown year in film",
+	HTML:       "HTML year in film",
+	Letterboxd: "Letterboxd import CSV",
+}
+
+type chooseMsg Format
+
+type Model struct {
+	props   common.Props
+	menu    *vlist.Model
+	pager   viewport.Model
+	format  Format
+	year    int
+	items   []Item // nil until loaded
+	loading bool
+	status  string
+	err     string
+	seq     int
+	cancel  context.CancelFunc
+}
+
+func New(p common.Props) *Model {
+	buttons := make([]common.Focusable, 0, len(Formats))
+	for _, format := range Formats {
+		format := format
+		buttons = append(buttons, button.New(p, formatNames[format], func() tea.Msg { return chooseMsg(format) }))
+	}
+
+	m := &Model{
+		props: p,
+		menu:  vlist.New(common.Props{Width: 30, Height: len(buttons) * 2, Global: p.Global}, 1, buttons...),
+		pager: viewport.New(p.Width, p.Height),
+	}
+	m.SetSize(p.Width, p.Height)
+	return m
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.props.Width = width
+	m.props.Height = height
+
+	// margins + title + hint
+	m.pager.Width = util.Max(width-4, 10)
+	m.pager.Height = util.Max(height-6, 1)
+}
+
+func (m *Model) Init() tea.Cmd {
+	m.stop()
+	m.items = nil
+	m.loading = false
+	m.format = ""
+	m.status = ""
+	m.err = ""
+	m.year = time.Now().Year()
+	return nil
+}
+
+func (m *Model) stop() {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+	m.seq++
+}
+
+func (m *Model) load() tea.Cmd {
+	m.stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.loading = true
+	seq := m.seq
+	g := m.props.Global
+
+	known := map[int]common.Film{}
+	for id := range g.FilmCache {
+		if ok, _, film := g.FilmCache.Get(id); ok {
+			known[id] = film
+		}
+	}
+
+	return func() tea.Msg {
+		items, err := Load(ctx, g, g.AuthState.User.Id, known)
+		return func() tea.Msg {
+			if seq != m.seq {
+				return nil
+			}
+			m.loading = false
+			if err != nil {
+				m.err = fmt.Sprintf("Could not load reviews (%v)", err)
+				m.format = ""
+				return nil
+			}
+			m.items = items
+			for _, item := range items {
+				if item.Film.Id != 0 {
+					g.FilmCache.Set(item.Film.Id, item.Film)
+				}
+			}
+			m.render()
+			return nil
+		}
+	}
+}
+
+func (m *Model) render() {
+	sb := strings.Builder{}
+	if err := Write(&sb, m.items, m.format, m.year); err != nil {
+		m.err = err.Error()
+		return
+	}
+	m.err = ""
+	m.pager.SetContent(sb.String())
+	m.pager.GotoTop()
+}
+
+func (m *Model) yearly() bool {
+	return m.format == Markdown || m.format == HTML
+}
+
+func (m *Model) copy() {
+	if m.props.Global.Output == nil {
+		m.status = "Copying isn't supported here."
+		return
+	}
+
+	sb := strings.Builder{}
+	Write(&sb, m.items, m.format, m.year)
+	m.props.Global.Output.Copy(sb.String())
+	m.status = fmt.Sprintf("Copied %d bytes. Not every terminal supports OSC 52 or text this long.", sb.Len())
+}
+
+func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case chooseMsg:
+		m.format = Format(msg)
+		m.status = ""
+		if m.items == nil {
+			return m, m.load()
+		}
+		m.render()
+		return m, nil
+	case *common.KeyEvent:
+		return m, m.updateKeys(msg)
+	}
+	return m, nil
+}
+
+func (m *Model) updateKeys(msg *common.KeyEvent) tea.Cmd {
+	km := m.props.Global.KeyMap
+
+	// Menu is the first level, unhandled back leaves the page
+	if m.format == "" {
+		_, cmd := m.menu.Update(msg)
+		return cmd
+	}
+
+	switch {
+	case key.Matches(msg.KeyMsg, km.Back):
+		msg.Handled = true
+		m.stop()
+		m.loading = false
+		m.format = ""
+		m.status = ""
+		return nil
+	case m.loading:
+		return nil
+	case key.Matches(msg.KeyMsg, km.Copy):
+		msg.Handled = true
+		m.copy()
+		return nil
+	case m.yearly() && key.Matches(msg.KeyMsg, km.Left):
+		msg.Handled = true
+		m.year--
+		m.render()
+		return nil
+	case m.yearly() && key.Matches(msg.KeyMsg, km.Right):
+		msg.Handled = true
+		m.year++
+		m.render()
+		return nil
+	}
+
+	// viewport keys are pgup/pgdown/up/down/j/k etc
+	var cmd tea.Cmd
+	m.pager, cmd = m.pager.Update(msg.KeyMsg)
+	if key.Matches(msg.KeyMsg, m.pager.KeyMap.Up, m.pager.KeyMap.Down, m.pager.KeyMap.PageUp, m.pager.KeyMap.PageDown, m.pager.KeyMap.HalfPageUp, m.pager.KeyMap.HalfPageDown) {
+		msg.Handled = true
+	}
+	return cmd
+}
+
+func (m *Model) View() string {
+	view := strings.Builder{}
+
+	switch {
+	case m.format == "":
+		view.WriteString(accentStyle.Render("Export reviews"))
+		view.WriteString("\n\n")
+		view.WriteString(m.menu.View())
+		view.WriteString("\n\n")
+		if m.err != "" {
+			view.WriteString(errStyle.Render(m.err))
+		} else {
+			view.WriteString(hintStyle.Render("Or from a shell: ssh you@example.com@<host> export --format csv > reviews.csv"))
+		}
+
+	case m.loading:
+		view.WriteString(accentStyle.Render(formatNames[m.format]))
+		view.WriteString("\n\nLoading reviews and films...")
+
+	default:
+		title := formatNames[m.format]
+		if m.yearly() {
+			title += fmt.Sprintf(" ← %d →", m.year)
+		}
+		view.WriteString(accentStyle.Render(title))
+		view.WriteString(hintStyle.Render(fmt.Sprintf("  %d%%", int(m.pager.ScrollPercent()*100))))
+		view.WriteString("\n")
+		view.WriteString(m.pager.View())
+		view.WriteString("\n")
+
+		switch {
+		case m.err != "":
+			view.WriteString(errStyle.Render(m.err))
+		case m.status != "":
+			view.WriteString(hintStyle.Render(m.status))
+		default:
+			view.WriteString(hintStyle.Render("c copy to clipboard · ↑↓ scroll · esc back"))
+		}
+	}
+
+	return lipgloss.NewStyle().MaxWidth(m.props.Width).Render(pageStyle.Render(view.String()))
+}
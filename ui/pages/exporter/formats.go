@@ -0,0 +1,319 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/common/enums"
+)
+
+type Format string
+
+const (
+	JSON       Format = "json"
+	CSV        Format = "csv"
+	Markdown   Format = "markdown"
+	HTML       Format = "html"
+	Letterboxd Format = "letterboxd"
+)
+
+var Formats = []Format{JSON, CSV, Markdown, HTML, Letterboxd}
+
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+	if strings.EqualFold(s, "md") {
+		return Markdown, nil
+	}
+	return JSON, fmt.Errorf("unknown format %q", s)
+}
+
+// Suggested file extension
+func (f Format) Ext() string {
+	switch f {
+	case Markdown:
+		return "md"
+	case Letterboxd:
+		return "csv"
+	}
+	return string(f)
+}
+
+type Item struct {
+	Review common.Review
+	Film   common.Film
+}
+
+func (i Item) Year() string {
+	if len(i.Film.Release_date) >= 4 {
+		return i.Film.Release_date[:4]
+	}
+	return ""
+}
+
+// Write writes items in format. year only applies to the Markdown and HTML year in film pages.
+func Write(w io.Writer, items []Item, format Format, year int) error {
+	switch format {
+	case JSON:
+		return writeJSON(w, items)
+	case CSV:
+		return writeCSV(w, items)
+	case Markdown:
+		return writeMarkdown(w, yearInFilm(items, year))
+	case HTML:
+		return writeHTML(w, yearInFilm(items, year))
+	case Letterboxd:
+		return writeLetterboxd(w, items)
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+// Field names match review-api, so this can be read back losslessly
+type jsonReview struct {
+	Tmdb_id    int       `json:"tmdb_id"`
+	Title      string    `json:"title"`
+	Release    string    `json:"release_date"`
+	Category   string    `json:"category"`
+	Status     string    `json:"status"`
+	Text       string    `json:"text"`
+	Fun_before bool      `json:"fun_before"`
+	Fun_during bool      `json:"fun_during"`
+	Fun_after  bool      `json:"fun_after"`
+	Created_at time.Time `json:"created_at"`
+	Updated_at time.Time `json:"updated_at"`
+}
+
+func writeJSON(w io.Writer, items []Item) error {
+	reviews := make([]jsonReview, 0, len(items))
+	for _, item := range items {
+		r := item.Review
+		reviews = append(reviews, jsonReview{
+			Tmdb_id:    r.Tmdb_id,
+			Title:      item.Film.Title,
+			Release:    item.Film.Release_date,
+			Category:   "Film",
+			Status:     r.Status.String(),
+			Text:       r.Text,
+			Fun_before: r.Fun_before,
+			Fun_during: r.Fun_during,
+			Fun_after:  r.Fun_after,
+			Created_at: r.Created_at,
+			Updated_at: r.Updated_at,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(reviews)
+}
+
+func writeCSV(w io.Writer, items []Item) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"tmdb_id", "title", "year", "status", "fun_before", "liked", "starred", "text", "created_at", "updated_at"})
+
+	for _, item := range items {
+		r := item.Review
+		writer.Write([]string{
+			strconv.Itoa(r.Tmdb_id),
+			item.Film.Title,
+			item.Year(),
+			r.Status.DisplayString(),
+			strconv.FormatBool(r.Fun_before),
+			strconv.FormatBool(r.Fun_during),
+			strconv.FormatBool(r.Fun_after),
+			r.Text,
+			r.Created_at.Format(time.RFC3339),
+			r.Updated_at.Format(time.RFC3339),
+		})
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// Opposite of the importer's thresholds, so a round trip keeps likes and stars
+func letterboxdRating(r common.Review) string {
+	switch {
+	case r.Fun_after:
+		return "4.5"
+	case r.Fun_during:
+		return "4"
+	}
+	return ""
+}
+
+// Columns from letterboxd.com/about/importing-data. Letterboxd imports watchlists
+// separately, so only completed films are included.
+func writeLetterboxd(w io.Writer, items []Item) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"tmdbID", "Title", "Year", "Rating", "WatchedDate", "Review"})
+
+	for _, item := range items {
+		r := item.Review
+		if r.Status != enums.Completed {
+			continue
+		}
+		writer.Write([]string{
+			strconv.Itoa(r.Tmdb_id),
+			item.Film.Title,
+			item.Year(),
+			letterboxdRating(r),
+			r.Updated_at.Format("2006-01-02"),
+			r.Text,
+		})
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+type month struct {
+	Name  string
+	Items []Item
+}
+
+type yearSummary struct {
+	Year    int
+	Watched int
+	Liked   int
+	Starred int
+	Months  []month
+	Best    []Item // Starred first, then liked
+}
+
+// Films completed in year, going by when the review was last updated
+func yearInFilm(items []Item, year int) yearSummary {
+	s := yearSummary{Year: year}
+	byMonth := make([][]Item, 12)
+
+	for _, item := range items {
+		r := item.Review
+		if r.Status != enums.Completed || r.Updated_at.Year() != year {
+			continue
+		}
+
+		s.Watched++
+		if r.Fun_during {
+			s.Liked++
+		}
+		if r.Fun_after {
+			s.Starred++
+		}
+		if r.Fun_during || r.Fun_after {
+			s.Best = append(s.Best, item)
+		}
+
+		m := r.Updated_at.Month() - 1
+		byMonth[m] = append(byMonth[m], item)
+	}
+
+	for i, films := range byMonth {
+		if len(films) == 0 {
+			continue
+		}
+		sort.Slice(films, func(a, b int) bool {
+			return films[a].Review.Updated_at.Before(films[b].Review.Updated_at)
+		})
+		s.Months = append(s.Months, month{time.Month(i + 1).String(), films})
+	}
+
+	sort.SliceStable(s.Best, func(a, b int) bool {
+		return s.Best[a].Review.Fun_after && !s.Best[b].Review.Fun_after
+	})
+
+	return s
+}
+
+func (i Item) Label() string {
+	if year := i.Year(); year != "" {
+		return fmt.Sprintf("%s (%s)", i.Film.Title, year)
+	}
+	return i.Film.Title
+}
+
+func (i Item) Marks() string {
+	marks := ""
+	if i.Review.Fun_during {
+		marks += " ♥"
+	}
+	if i.Review.Fun_after {
+		marks += " ★"
+	}
+	return marks
+}
+
+func writeMarkdown(w io.Writer, s yearSummary) error {
+	sb := strings.Builder{}
+
+	fmt.Fprintf(&sb, "# My year in film, %d\n\n", s.Year)
+	fmt.Fprintf(&sb, "%d films watched, %d liked ♥, %d starred ★\n", s.Watched, s.Liked, s.Starred)
+
+	if len(s.Best) > 0 {
+		sb.WriteString("\n## Favorites\n\n")
+		for _, item := range s.Best {
+			fmt.Fprintf(&sb, "- %s%s\n", item.Label(), item.Marks())
+		}
+	}
+
+	for _, m := range s.Months {
+		fmt.Fprintf(&sb, "\n## %s\n\n", m.Name)
+		for _, item := range m.Items {
+			fmt.Fprintf(&sb, "- %s%s\n", item.Label(), item.Marks())
+			if text := strings.TrimSpace(item.Review.Text); text != "" {
+				fmt.Fprintf(&sb, "  > %s\n", strings.ReplaceAll(text, "\n", "\n  > "))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+var htmlTemplate = template.Must(template.New("year").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>My year in film, {{.Year}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 40rem; margin: 2rem auto; padding: 0 1rem; }
+h1, h2 { color: #F25D94; }
+blockquote { color: #666; margin: 0.25rem 0 0.5rem 1rem; white-space: pre-wrap; }
+</style>
+</head>
+<body>
+<h1>My year in film, {{.Year}}</h1>
+<p>{{.Watched}} films watched, {{.Liked}} liked ♥, {{.Starred}} starred ★</p>
+{{- if .Best}}
+<h2>Favorites</h2>
+<ul>
+{{- range .Best}}
+<li>{{.Label}}{{.Marks}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- range .Months}}
+<h2>{{.Name}}</h2>
+<ul>
+{{- range .Items}}
+<li>{{.Label}}{{.Marks}}{{with .Review.Text}}<blockquote>{{.}}</blockquote>{{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</body>
+</html>
+`))
+
+func writeHTML(w io.Writer, s yearSummary) error {
+	return htmlTemplate.Execute(w, s)
+}
@@ -0,0 +1,52 @@
+package exporter
+
+import (
+	"context"
+	"sort"
+
+	"github.com/zhengkyl/review-ssh/ui/common"
+)
+
+const loadWorkers = 4
+
+// Load gets every review for userId joined with its film. This blocks.
+// known films are reused instead of fetched. Pass a copy of FilmCache rather
+// than FilmCache itself, since this runs outside of Update.
+func Load(ctx context.Context, g common.Global, userId int, known map[int]common.Film) ([]Item, error) {
+	reviews, err := common.FetchAllReviews(ctx, g, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, 0, len(reviews))
+	for _, review := range reviews {
+		item := Item{Review: review}
+		if film, ok := known[review.Tmdb_id]; ok {
+			item.Film = film
+		}
+		items = append(items, item)
+	}
+
+	// Oldest first, reads nicely in every format
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Review.Created_at.Before(items[j].Review.Created_at)
+	})
+
+	missing := []int{}
+	for i := range items {
+		if items[i].Film.Id == 0 {
+			missing = append(missing, i)
+		}
+	}
+
+	common.ForEach(ctx, len(missing), loadWorkers, func(j int) {
+		i := missing[j]
+		id := items[i].Review.Tmdb_id
+		// A missing film shouldn't lose the review, it's exported without a title
+		if film, err := common.Do[common.Film](ctx, g, "GET", common.FilmURL(g, id, ""), nil); err == nil {
+			items[i].Film = film
+		}
+	})
+
+	return items, ctx.Err()
+}
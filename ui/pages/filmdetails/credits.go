@@ -0,0 +1,206 @@
+package filmdetails
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/util"
+)
+
+const maxCast = 8
+
+// Crew jobs worth showing, in display order
+var crewJobs = []string{"Director", "Screenplay", "Writer", "Original Music Composer"}
+
+var crewLabels = map[string]string{
+	"Director":                "Director",
+	"Screenplay":              "Writer",
+	"Writer":                  "Writer",
+	"Original Music Composer": "Music",
+}
+
+var (
+	sectionStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	roleStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	activeNameStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25D94"))
+)
+
+type credit struct {
+	personId int
+	name     string
+	role     string
+}
+
+type creditsModel struct {
+	props   common.Props
+	filmId  int
+	crew    []credit
+	cast    []credit
+	loaded  bool
+	active  int
+	offset  int
+	focused bool
+}
+
+func newCredits(p common.Props) *creditsModel {
+	return &creditsModel{props: p}
+}
+
+func (m *creditsModel) Init(filmId int) tea.Cmd {
+	m.filmId = filmId
+	m.crew = nil
+	m.cast = nil
+	m.loaded = false
+	m.active = 0
+	m.offset = 0
+
+	return common.GetCreditsCmd(m.props.Global, filmId, func(data common.Credits, err error) tea.Msg {
+		if filmId != m.filmId {
+			return nil
+		}
+		m.loaded = true
+		if err == nil {
+			m.setCredits(data)
+		}
+		return nil
+	})
+}
+
+func (m *creditsModel) setCredits(data common.Credits) {
+	// One row per person, with every job they did
+	rows := map[int]int{}
+	for _, job := range crewJobs {
+		for _, c := range data.Crew {
+			if c.Job != job {
+				continue
+			}
+			label := crewLabels[job]
+			if i, ok := rows[c.Id]; ok {
+				if !strings.Contains(m.crew[i].role, label) {
+					m.crew[i].role += ", " + label
+				}
+				continue
+			}
+			rows[c.Id] = len(m.crew)
+			m.crew = append(m.crew, credit{c.Id, c.Name, label})
+		}
+	}
+
+	// TMDB already sorts cast by billing order
+	for _, c := range data.Cast {
+		if len(m.cast) == maxCast {
+			break
+		}
+		m.cast = append(m.cast, credit{c.Id, c.Name, c.Character})
+	}
+}
+
+func (m *creditsModel) credits() []credit {
+	return append(append([]credit{}, m.crew...), m.cast...)
+}
+
+func (m *creditsModel) Focused() bool {
+	return m.focused
+}
+
+func (m *creditsModel) Focus() {
+	m.focused = true
+}
+
+func (m *creditsModel) Blur() {
+	m.focused = false
+}
+
+func (m *creditsModel) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	if !m.focused {
+		return m, nil
+	}
+
+	credits := m.credits()
+	if len(credits) == 0 {
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case *common.KeyEvent:
+		switch {
+		case key.Matches(msg.KeyMsg, m.props.Global.KeyMap.Down):
+			msg.Handled = true
+			m.active = util.Min(m.active+1, len(credits)-1)
+		case key.Matches(msg.KeyMsg, m.props.Global.KeyMap.Up):
+			msg.Handled = true
+			m.active = util.Max(m.active-1, 0)
+		case key.Matches(msg.KeyMsg, m.props.Global.KeyMap.Select):
+			msg.Handled = true
+			personId := credits[m.active].personId
+			return m, func() tea.Msg { return common.ShowPerson(personId) }
+		}
+	}
+	return m, nil
+}
+
+func (m *creditsModel) SetSize(width, height int) {
+	m.props.Width = width
+	m.props.Height = height
+}
+
+// Crew then cast, scrolled to keep the active person visible
+func (m *creditsModel) View() string {
+	width, height := m.props.Width, m.props.Height
+	if !m.loaded || height < 2 {
+		return ""
+	}
+	if len(m.crew) == 0 && len(m.cast) == 0 {
+		return sectionStyle.Render("No credits")
+	}
+
+	lines := []string{}
+	rowLine := []int{} // Line of each credit, skipping headers
+
+	nameWidth := util.Min(24, width/2)
+	row := func(c credit, i int) string {
+		name := util.TruncAndPadUnicode(c.name, nameWidth)
+		if m.focused && i == m.active {
+			name = activeNameStyle.Render(name)
+		}
+		return name + " " + roleStyle.Render(util.TruncAndPadUnicode(c.role, util.Max(width-nameWidth-1, 2)))
+	}
+
+	i := 0
+	if len(m.crew) > 0 {
+		lines = append(lines, sectionStyle.Render("Crew"))
+		for _, c := range m.crew {
+			rowLine = append(rowLine, len(lines))
+			lines = append(lines, row(c, i))
+			i++
+		}
+	}
+	if len(m.cast) > 0 {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, sectionStyle.Render("Cast"))
+		for _, c := range m.cast {
+			rowLine = append(rowLine, len(lines))
+			lines = append(lines, row(c, i))
+			i++
+		}
+	}
+
+	if m.focused {
+		activeLine := rowLine[m.active]
+		if activeLine < m.offset {
+			m.offset = activeLine
+		} else if activeLine >= m.offset+height {
+			m.offset = activeLine - height + 1
+		}
+	} else {
+		m.offset = 0
+	}
+
+	end := util.Min(m.offset+height, len(lines))
+	return strings.Join(lines[m.offset:end], "\n")
+}
@@ -0,0 +1,66 @@
+package filmdetails
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/common/enums"
+)
+
+func (m *Model) watches() []common.DiaryEntry {
+	g := m.props.Global
+	watches := []common.DiaryEntry{}
+	for _, e := range g.Diary.Entries(g.AuthState.User.Id) {
+		if e.Tmdb_id == m.filmId {
+			watches = append(watches, e)
+		}
+	}
+	return watches
+}
+
+// Logs a watch today with the current like and star, and completes the review.
+// It's a rewatch if the film was already logged or completed.
+func (m *Model) logWatch() tea.Cmd {
+	g := m.props.Global
+	review, reviewed := g.ReviewMap[m.filmId]
+
+	entry := g.Diary.Add(g.AuthState.User.Id, common.DiaryEntry{
+		Tmdb_id:    m.filmId,
+		Watched:    g.Now().Format(common.DiaryDateLayout),
+		Rewatch:    len(m.watches()) > 0 || (reviewed && review.Status == enums.Completed),
+		Fun_during: m.checkDuring.Checked,
+		Fun_after:  m.checkAfter.Checked,
+	})
+	m.logged = "Logged today"
+	if entry.Rewatch {
+		m.logged = "Logged a rewatch today"
+	}
+
+	completed := common.Review{
+		Status:     enums.Completed,
+		Fun_during: m.checkDuring.Checked,
+		Fun_after:  m.checkAfter.Checked,
+	}
+	switch {
+	case !reviewed:
+		m.updateInputs(completed)
+		return common.PostReviewCmd(g, m.filmId, enums.Completed.String(), nil)
+	case review.Status != enums.Completed:
+		m.updateInputs(completed)
+		return common.PatchReviewCmd(g, m.filmId, map[string]interface{}{"status": enums.Completed.String()}, nil)
+	}
+	return nil
+}
+
+// Like "watched 3×, last May 4", or what was just logged
+func (m *Model) watchesView() string {
+	if m.logged != "" {
+		return m.logged
+	}
+	watches := m.watches()
+	if len(watches) == 0 {
+		return "+ watched today"
+	}
+	return fmt.Sprintf("watched %d×, last %s · + again", len(watches), watches[0].Date().Format("Jan 2 2006"))
+}
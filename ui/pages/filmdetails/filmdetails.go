@@ -10,12 +10,24 @@ import (
 	"github.com/zhengkyl/review-ssh/ui/common/enums"
 	"github.com/zhengkyl/review-ssh/ui/components/checkbox"
 	"github.com/zhengkyl/review-ssh/ui/components/dropdown"
+	"github.com/zhengkyl/review-ssh/ui/components/filmrail"
 	"github.com/zhengkyl/review-ssh/ui/components/poster"
 	"github.com/zhengkyl/review-ssh/ui/util"
 )
 
+const (
+	posterWidth  = 28
+	posterHeight = 21
+	// Below this the poster is hidden
+	narrowWidth = 70
+	// Right column width at which details and credits sit side by side
+	wideWidth     = 80
+	overviewLines = 5
+)
+
 var (
 	viewStyle      = lipgloss.NewStyle().Margin(1)
+	taglineStyle   = lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("8"))
 	defaultOptions = []dropdown.Option{
 		{Text: "Plan To Watch", Value: "PlanToWatch"},
 		{Text: "Completed", Value: "Completed"},
@@ -28,17 +40,27 @@ var (
 )
 
 type Model struct {
-	props        common.Props
-	poster       *poster.Model
-	filmLoaded   bool
-	reviewLoaded bool
-	filmId       int
-	inputs       []common.Focusable
-	dropdown     *dropdown.Model
-	checkDuring  *checkbox.Model
-	checkAfter   *checkbox.Model
-	focusIndex   int
-	updates      map[string]string
+	props      common.Props
+	poster     *poster.Model
+	filmLoaded bool
+	// Search results only have the basics, so details are fetched once
+	detailsRequested bool
+	failed           bool
+	reviewLoaded     bool
+	filmId           int
+	inputs           []common.Focusable
+	dropdown         *dropdown.Model
+	checkDuring      *checkbox.Model
+	checkAfter       *checkbox.Model
+	credits          *creditsModel
+	collection       *collectionModel
+	friends          *friendsModel
+	groups           *groupPicker
+	logged           string // Confirms a watch was logged
+	recommended      *filmrail.Model
+	similar          *filmrail.Model
+	focusIndex       int
+	updates          map[string]string
 }
 
 func New(p common.Props) *Model {
@@ -50,6 +72,12 @@ func New(p common.Props) *Model {
 		dropdown:    dropdown.New(common.Props{Width: 20, Height: 3, Global: p.Global}, "Add movie", defaultOptions),
 		checkDuring: checkbox.New(p),
 		checkAfter:  checkbox.New(p),
+		credits:     newCredits(p),
+		collection:  newCollection(p),
+		friends:     newFriends(p),
+		groups:      newGroupPicker(p),
+		recommended: filmrail.New(p, "Recommended"),
+		similar:     filmrail.New(p, "Similar films"),
 		inputs:      []common.Focusable{},
 		focusIndex:  0,
 		updates:     make(map[string]string),
@@ -57,7 +85,7 @@ func New(p common.Props) *Model {
 	m.checkDuring.Label = "LIKE"
 	m.checkAfter.Label = "STAR"
 
-	m.inputs = append(m.inputs, m.dropdown, m.checkDuring, m.checkAfter)
+	m.inputs = append(m.inputs, m.dropdown, m.checkDuring, m.checkAfter, m.collection, m.credits, m.recommended, m.similar)
 
 	return m
 }
@@ -68,17 +96,20 @@ func (m *Model) SetSize(width, height int) {
 
 	m.props.Width = width - hf
 	m.props.Height = height - vf
+
+	m.recommended.SetSize(m.props.Width, filmrail.Height)
+	m.similar.SetSize(m.props.Width, filmrail.Height)
 }
 
 func (m *Model) updateInputs(review common.Review) {
 
 	m.checkDuring.Checked = review.Fun_during
 	m.checkDuring.OnChange = func(value bool) tea.Cmd {
-		return patchReviewCmd(m.props.Global, m.filmId, map[string]interface{}{"fun_during": value})
+		return common.PatchReviewCmd(m.props.Global, m.filmId, map[string]interface{}{"fun_during": value}, nil)
 	}
 	m.checkAfter.Checked = review.Fun_after
 	m.checkAfter.OnChange = func(value bool) tea.Cmd {
-		return patchReviewCmd(m.props.Global, m.filmId, map[string]interface{}{"fun_after": value})
+		return common.PatchReviewCmd(m.props.Global, m.filmId, map[string]interface{}{"fun_after": value}, nil)
 	}
 
 	m.dropdown.OnChange = func(value string) tea.Cmd {
@@ -86,9 +117,9 @@ func (m *Model) updateInputs(review common.Review) {
 			m.dropdown.SetItems(defaultOptions)
 			m.checkDuring.Checked = false
 			m.checkAfter.Checked = false
-			return deleteReviewCmd(m.props.Global, m.filmId)
+			return common.DeleteReviewCmd(m.props.Global, m.filmId, nil)
 		}
-		return patchReviewCmd(m.props.Global, m.filmId, map[string]interface{}{"status": value})
+		return common.PatchReviewCmd(m.props.Global, m.filmId, map[string]interface{}{"status": value}, nil)
 	}
 	switch review.Status {
 	case enums.PlanToWatch:
@@ -102,17 +133,28 @@ func (m *Model) updateInputs(review common.Review) {
 func (m *Model) Init(filmId int) tea.Cmd {
 	m.filmId = filmId
 	m.filmLoaded = false
+	m.detailsRequested = false
+	m.failed = false
 	review, ok := m.props.Global.ReviewMap[m.filmId]
 
 	m.focusIndex = 0
 	m.dropdown.Focus()
 	m.checkDuring.Blur()
 	m.checkAfter.Blur()
+	m.credits.Blur()
+	m.collection.Blur()
+	m.collection.Init(common.Film{Id: filmId})
+	m.groups.Close()
+	m.logged = ""
+	m.recommended.Blur()
+	m.similar.Blur()
+	creditsCmd := tea.Batch(m.credits.Init(filmId), m.friends.Init(filmId), m.initRails(filmId))
+	creditsCmd = tea.Batch(creditsCmd, common.GetWatchProvidersCmd(m.props.Global, filmId))
 
 	if ok {
 		m.updateInputs(review)
 		m.reviewLoaded = true
-		return nil
+		return creditsCmd
 	}
 	m.dropdown.Selected = -1
 	m.checkDuring.Checked = false
@@ -129,29 +171,73 @@ func (m *Model) Init(filmId int) tea.Cmd {
 			Fun_during: m.checkDuring.Checked,
 			Fun_after:  m.checkAfter.Checked,
 		})
-		return postReviewCmd(m.props.Global, filmId, value)
+		return common.PostReviewCmd(m.props.Global, filmId, value, nil)
 	}
 
-	return common.GetMyFilmReviewCmd(m.props.Global, m.filmId, func(data common.Paged[common.Review], err error) tea.Msg {
+	return tea.Batch(creditsCmd, common.GetMyFilmReviewCmd(m.props.Global, m.filmId, func(data common.Paged[common.Review], err error) tea.Msg {
 		if err == nil && len(data.Results) > 0 {
 			review := data.Results[0]
 			m.props.Global.ReviewMap[review.Tmdb_id] = review
 			m.updateInputs(review)
 		}
 		return nil
-	})
+	}))
+}
+
+func (m *Model) initRails(filmId int) tea.Cmd {
+	m.recommended.Reset()
+	m.similar.Reset()
+
+	railCallback := func(rail *filmrail.Model) func(common.Paged[common.Film], error) tea.Msg {
+		return func(data common.Paged[common.Film], err error) tea.Msg {
+			if filmId != m.filmId {
+				return nil
+			}
+			for _, film := range data.Results {
+				if ok, _, _ := m.props.Global.FilmCache.Get(film.Id); !ok {
+					m.props.Global.FilmCache.Set(film.Id, film)
+				}
+			}
+			return rail.SetFilms(data.Results)
+		}
+	}
+
+	return tea.Batch(
+		common.GetRecommendationsCmd(m.props.Global, filmId, railCallback(m.recommended)),
+		common.GetSimilarCmd(m.props.Global, filmId, railCallback(m.similar)),
+	)
 }
 
 func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 	switch msg := msg.(type) {
 	case *common.KeyEvent:
+		if m.groups.open {
+			return m, m.groups.Update(msg)
+		}
+		if key.Matches(msg.KeyMsg, m.props.Global.KeyMap.Groups) && !m.guest() {
+			msg.Handled = true
+			m.groups.Open(m.filmId)
+			return m, nil
+		}
+		if key.Matches(msg.KeyMsg, m.props.Global.KeyMap.Watched) && m.filmLoaded {
+			msg.Handled = true
+			return m, m.logWatch()
+		}
+
+		// Rails use left and right themselves until they run out of films
+		_, cmd := m.inputs[m.focusIndex].Update(msg)
+		if msg.Handled {
+			return m, cmd
+		}
+		cmds = append(cmds, cmd)
+
 		prevFocus := m.focusIndex
 		switch {
 		case key.Matches(msg.KeyMsg, m.props.Global.KeyMap.NextX):
-			m.focusIndex = util.Mod(m.focusIndex+1, 3)
+			m.moveFocus(1)
 		case key.Matches(msg.KeyMsg, m.props.Global.KeyMap.PrevX):
-			m.focusIndex = util.Mod(m.focusIndex-1, 3)
+			m.moveFocus(-1)
 		}
 		if m.focusIndex != prevFocus {
 			m.inputs[m.focusIndex].Focus()
@@ -163,13 +249,17 @@ func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
 
 		ok, loading, film := m.props.Global.FilmCache.Get(m.filmId)
 
-		if ok {
+		switch {
+		case ok && (film.HasDetails() || m.detailsRequested):
 			m.filmLoaded = true
-			m.poster = poster.New(common.Props{Width: 28, Height: 21, Global: m.props.Global}, "https://image.tmdb.org/t/p/w200"+film.Poster_path)
-			cmds = append(cmds, m.poster.Init())
-		} else if !loading {
-			cmd := common.GetFilmCmd(m.props.Global, m.filmId)
-			cmds = append(cmds, cmd)
+			m.poster = poster.New(common.Props{Width: posterWidth, Height: posterHeight, Global: m.props.Global}, "https://image.tmdb.org/t/p/w200"+film.Poster_path)
+			cmds = append(cmds, m.poster.Init(), m.collection.Init(film))
+		case loading:
+		case m.detailsRequested:
+			m.failed = true
+		default:
+			m.detailsRequested = true
+			cmds = append(cmds, common.GetFilmCmd(m.props.Global, m.filmId))
 		}
 
 	} else {
@@ -177,22 +267,85 @@ func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 	}
 
-	_, cmd := m.inputs[m.focusIndex].Update(msg)
-	cmds = append(cmds, cmd)
+	// Rail posters load whether or not the rail is focused
+	if _, ok := msg.(*common.KeyEvent); !ok {
+		for _, rail := range []*filmrail.Model{m.recommended, m.similar} {
+			_, cmd := rail.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		if m.inputs[m.focusIndex] != m.recommended && m.inputs[m.focusIndex] != m.similar {
+			_, cmd := m.inputs[m.focusIndex].Update(msg)
+			cmds = append(cmds, cmd)
+		}
+	}
 
 	return m, tea.Batch(cmds...)
 }
 
+// Every guest has the same id, so they can't use group lists
+func (m *Model) guest() bool {
+	return m.props.Global.AuthState.User.Id == common.GuestAuthState.User.Id
+}
+
+func (m *Model) hidden(input common.Focusable) bool {
+	switch input {
+	case m.collection:
+		return m.collection.Empty()
+	case m.recommended, m.similar:
+		return input.(*filmrail.Model).Empty() || m.railCount() == 0
+	}
+	return false
+}
+
+// Rails go under everything else, as many as fit
+func (m *Model) railCount() int {
+	return util.Min(util.Max((m.props.Height-posterHeight-1)/(filmrail.Height+1), 0), 2)
+}
+
+// With room for one rail, the focused one wins
+func (m *Model) rails() []*filmrail.Model {
+	switch m.railCount() {
+	case 0:
+		return nil
+	case 1:
+		if m.similar.Focused() || (m.recommended.Loaded() && m.recommended.Empty()) {
+			return []*filmrail.Model{m.similar}
+		}
+		return []*filmrail.Model{m.recommended}
+	}
+	return []*filmrail.Model{m.recommended, m.similar}
+}
+
+// Skips sections with nothing to select, like a film without a collection
+func (m *Model) moveFocus(delta int) {
+	for i := 1; i < len(m.inputs); i++ {
+		next := util.Mod(m.focusIndex+delta*i, len(m.inputs))
+		if next == m.focusIndex {
+			return
+		}
+		if m.hidden(m.inputs[next]) {
+			continue
+		}
+		m.focusIndex = next
+		return
+	}
+}
+
 func (m *Model) View() string {
+	if m.failed {
+		return viewStyle.Render("Could not load film.")
+	}
 	if !m.filmLoaded {
 		return "loading..."
 	}
 	_, _, film := m.props.Global.FilmCache.Get(m.filmId)
 
-	left := m.poster.View()
-
-	rightWidth := m.props.Width - 28 - 2 // poster + gap
-	descStyle := lipgloss.NewStyle().Width(rightWidth).Height(5)
+	// Narrow terminals drop the poster and stack everything
+	narrow := m.props.Width < narrowWidth
+	rightWidth := m.props.Width
+	if !narrow {
+		rightWidth -= posterWidth + 2 // poster + gap
+	}
 
 	rightSb := strings.Builder{}
 	rightSb.WriteString("\n")
@@ -207,14 +360,85 @@ func (m *Model) View() string {
 	dropdownView := m.dropdown.View()
 	// make place holder for expanded dropdown which needs to be overlaid
 	inputs := lipgloss.JoinHorizontal(lipgloss.Top, strings.Repeat(" ", lipgloss.Width(dropdownView)), " ", m.checkDuring.View(), " ", m.checkAfter.View())
+	hints := "  " + m.watchesView()
+	if !m.guest() {
+		hints += " · g add to group"
+	}
+	hints = util.TruncAndPadUnicode(hints, util.Max(rightWidth-lipgloss.Width(inputs), 0))
+	inputs = lipgloss.JoinHorizontal(lipgloss.Center, inputs, sectionStyle.Render(hints))
 	rightSb.WriteString(inputs)
 
 	rightSb.WriteString("\n\n")
 
+	if film.Tagline != "" {
+		rightSb.WriteString(taglineStyle.Render(util.TruncAndPadUnicode(film.Tagline, rightWidth)))
+		rightSb.WriteString("\n")
+	}
+	descStyle := lipgloss.NewStyle().Width(rightWidth).Height(overviewLines).MaxHeight(overviewLines)
 	rightSb.WriteString(descStyle.Render(film.Overview))
 	rightSb.WriteString("\n\n")
 
+	// Sections share whatever height is left
+	rails := m.rails()
+	topHeight := m.props.Height - len(rails)*(filmrail.Height+1)
+	remaining := util.Max(topHeight-lipgloss.Height(rightSb.String()), 0)
+	rightSb.WriteString(m.sectionsView(film, rightWidth, remaining))
+
 	rightView := util.RenderOverlay(rightSb.String(), dropdownView, 0, 3)
+	if m.groups.open {
+		// Under the inputs, so the film stays in view
+		rightView = util.RenderOverlay(rightView, m.groups.View(), 0, 6)
+	}
+	top := rightView
+	if !narrow {
+		top = lipgloss.JoinHorizontal(lipgloss.Top, m.poster.View(), "  ", rightView)
+	}
+	if len(rails) == 0 {
+		return viewStyle.Render(top)
+	}
+
+	view := strings.Builder{}
+	view.WriteString(lipgloss.NewStyle().Height(topHeight).MaxHeight(topHeight).Render(top))
+	for _, rail := range rails {
+		view.WriteString("\n")
+		view.WriteString(rail.View())
+	}
+	return viewStyle.Render(view.String())
+}
+
+func (m *Model) sectionsView(film common.Film, width, height int) string {
+	join := func(sections ...string) string {
+		nonEmpty := []string{}
+		for _, s := range sections {
+			if s != "" {
+				nonEmpty = append(nonEmpty, s)
+			}
+		}
+		return strings.Join(nonEmpty, "\n\n")
+	}
+	// Facts are cut before the lists, which can scroll
+	below := func(view string, height int) int {
+		if view == "" {
+			return height
+		}
+		return height - lipgloss.Height(view) - 1
+	}
+
+	if width >= wideWidth {
+		colWidth := (width - 2) / 2
+		facts := join(factsView(film, colWidth), providersView(m.props.Global, film.Id, colWidth), m.friends.View(colWidth))
+		m.collection.SetSize(colWidth, below(facts, height))
+		left := lipgloss.NewStyle().Width(colWidth).Render(join(facts, m.collection.View()))
+
+		m.credits.SetSize(width-colWidth-2, height)
+		view := lipgloss.JoinHorizontal(lipgloss.Top, left, "  ", m.credits.View())
+		return lipgloss.NewStyle().MaxHeight(height).Render(view)
+	}
 
-	return viewStyle.Render(lipgloss.JoinHorizontal(lipgloss.Top, left, "  ", rightView))
+	facts := join(factsView(film, width), providersView(m.props.Global, film.Id, width), m.friends.View(width))
+	left := below(facts, height)
+	m.collection.SetSize(width, util.Min(left, 6))
+	collection := m.collection.View()
+	m.credits.SetSize(width, below(collection, left))
+	return lipgloss.NewStyle().MaxHeight(height).Render(join(facts, collection, m.credits.View()))
 }
@@ -0,0 +1,99 @@
+package filmdetails
+
+import (
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/common/enums"
+	"github.com/zhengkyl/review-ssh/ui/util"
+)
+
+// Only this many follows are asked about a film, one request each
+const maxFriends = 20
+
+// Reviews of one film by people the user follows
+type friendsModel struct {
+	props     common.Props
+	filmId    int
+	following int
+	pending   int
+	reviews   map[int]common.Review
+}
+
+func newFriends(p common.Props) *friendsModel {
+	return &friendsModel{props: p}
+}
+
+func (m *friendsModel) Init(filmId int) tea.Cmd {
+	m.filmId = filmId
+	m.reviews = map[int]common.Review{}
+
+	following := m.props.Global.Settings.Get(m.props.Global.AuthState.User.Id).Following
+	if len(following) > maxFriends {
+		following = following[:maxFriends]
+	}
+	m.following = len(following)
+	m.pending = len(following)
+
+	var cmds []tea.Cmd
+	for _, userId := range following {
+		userId := userId
+		cmds = append(cmds, common.GetUserFilmReviewCmd(m.props.Global, userId, filmId, func(data common.Paged[common.Review], err error) tea.Msg {
+			if filmId != m.filmId {
+				return nil
+			}
+			m.pending--
+			if err != nil || len(data.Results) == 0 {
+				return nil
+			}
+			m.reviews[userId] = data.Results[0]
+			return common.GetUserCmd(m.props.Global, userId)
+		}))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *friendsModel) View(width int) string {
+	header := sectionStyle.Render("Friends who watched")
+	switch {
+	case m.following == 0:
+		return ""
+	case len(m.reviews) == 0 && m.pending > 0:
+		return header + "\n" + sectionStyle.Render("Loading...")
+	case len(m.reviews) == 0:
+		return header + "\n" + sectionStyle.Render("None of the people you follow")
+	}
+
+	// Finished first, then most recent
+	userIds := []int{}
+	for userId := range m.reviews {
+		userIds = append(userIds, userId)
+	}
+	sort.Slice(userIds, func(i, j int) bool {
+		a, b := m.reviews[userIds[i]], m.reviews[userIds[j]]
+		if (a.Status == enums.Completed) != (b.Status == enums.Completed) {
+			return a.Status == enums.Completed
+		}
+		return a.Updated_at.After(b.Updated_at)
+	})
+
+	lines := []string{header}
+	nameWidth := util.Max(width-factLabelWidth-6, 4)
+	for _, userId := range userIds {
+		review := m.reviews[userId]
+		name := "..."
+		if ok, _, user := m.props.Global.UserCache.Get(userId); ok {
+			name = user.Name
+		}
+
+		status := util.TruncAndPadUnicode(review.Status.DisplayString(), factLabelWidth)
+		rating := ""
+		if review.Status == enums.Completed {
+			rating = common.RenderRating(review.Fun_before, review.Fun_during, review.Fun_after)
+		}
+		lines = append(lines, util.TruncAndPadUnicode(name, nameWidth)+" "+sectionStyle.Render(status)+rating)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,94 @@
+package filmdetails
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/util"
+)
+
+var pickerStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#F25D94")).Padding(0, 1)
+
+const pickerWidth = 32
+
+// Adds the film to, or removes it from, the user's group lists
+type groupPicker struct {
+	props  common.Props
+	filmId int
+	groups []common.Group
+	active int
+	open   bool
+	err    string
+}
+
+func newGroupPicker(p common.Props) *groupPicker {
+	return &groupPicker{props: p}
+}
+
+func (m *groupPicker) Open(filmId int) {
+	m.filmId = filmId
+	m.groups = m.props.Global.Groups.Groups(m.props.Global.AuthState.User.Id)
+	m.active = 0
+	m.err = ""
+	m.open = true
+}
+
+func (m *groupPicker) Close() {
+	m.open = false
+}
+
+// Every key is handled while open
+func (m *groupPicker) Update(msg *common.KeyEvent) tea.Cmd {
+	km := m.props.Global.KeyMap
+	msg.Handled = true
+
+	switch {
+	case key.Matches(msg.KeyMsg, km.Back), key.Matches(msg.KeyMsg, km.Groups):
+		m.Close()
+	case len(m.groups) == 0:
+	case key.Matches(msg.KeyMsg, km.Down):
+		m.active = util.Min(m.active+1, len(m.groups)-1)
+	case key.Matches(msg.KeyMsg, km.Up):
+		m.active = util.Max(m.active-1, 0)
+	case key.Matches(msg.KeyMsg, km.Select):
+		g := m.props.Global
+		groupId := m.groups[m.active].Id
+		m.err = ""
+		if err := g.Groups.ToggleFilm(groupId, g.AuthState.User.Id, m.filmId); err != nil {
+			m.err = err.Error()
+		}
+		m.groups = g.Groups.Groups(g.AuthState.User.Id)
+		m.active = util.Max(util.Min(m.active, len(m.groups)-1), 0)
+	}
+	return nil
+}
+
+func (m *groupPicker) View() string {
+	width := pickerWidth - pickerStyle.GetHorizontalFrameSize()
+	lines := []string{sectionStyle.Render("Add to group")}
+
+	if len(m.groups) == 0 {
+		lines = append(lines, util.TruncAndPadUnicode("No groups yet", width), sectionStyle.Render(util.TruncAndPadUnicode("Make one with g on the lists page", width)))
+	}
+	for i, group := range m.groups {
+		marker := "  "
+		if group.HasFilm(m.filmId) {
+			marker = "✓ "
+		}
+		line := util.TruncAndPadUnicode(marker+group.Name, width)
+		if i == m.active {
+			line = activeNameStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	if m.err != "" {
+		lines = append(lines, util.TruncAndPadUnicode(m.err, width))
+	} else {
+		lines = append(lines, sectionStyle.Render(util.TruncAndPadUnicode("enter add/remove · esc close", width)))
+	}
+	return pickerStyle.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,291 @@
+package filmdetails
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/util"
+)
+
+const factLabelWidth = 10
+
+func formatRuntime(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
+
+// 1234567 -> "1,234,567"
+func withCommas(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// 63000000 -> "$63M", 463517383 -> "$463.5M"
+func formatMoney(n int64) string {
+	short := func(value float64, suffix string) string {
+		return "$" + strings.TrimSuffix(strconv.FormatFloat(value, 'f', 1, 64), ".0") + suffix
+	}
+	switch {
+	case n >= 1_000_000_000:
+		return short(float64(n)/1_000_000_000, "B")
+	case n >= 1_000_000:
+		return short(float64(n)/1_000_000, "M")
+	}
+	return "$" + withCommas(n)
+}
+
+func languageName(film common.Film) string {
+	for _, lang := range film.Spoken_languages {
+		if lang.Iso_639_1 == film.Original_language && lang.English_name != "" {
+			return lang.English_name
+		}
+	}
+	return film.Original_language
+}
+
+// Key value lines, skipping anything TMDB doesn't know
+func factsView(film common.Film, width int) string {
+	lines := []string{}
+	fact := func(label, value string) {
+		if value == "" {
+			return
+		}
+		line := sectionStyle.Render(util.TruncAndPadUnicode(label, factLabelWidth)) + util.TruncAndPadUnicode(value, util.Max(width-factLabelWidth, 2))
+		lines = append(lines, line)
+	}
+
+	if film.Runtime > 0 {
+		fact("Runtime", formatRuntime(film.Runtime))
+	}
+
+	genres := make([]string, 0, len(film.Genres))
+	for _, genre := range film.Genres {
+		genres = append(genres, genre.Name)
+	}
+	fact("Genres", strings.Join(genres, ", "))
+
+	if film.Original_title != "" && film.Original_title != film.Title {
+		fact("Original", film.Original_title)
+	}
+	fact("Language", languageName(film))
+
+	if film.Vote_count > 0 {
+		fact("Rating", fmt.Sprintf("%.1f/10 from %s votes", film.Vote_average, withCommas(int64(film.Vote_count))))
+	}
+
+	countries := make([]string, 0, len(film.Production_countries))
+	for _, country := range film.Production_countries {
+		countries = append(countries, country.Name)
+	}
+	fact("Country", strings.Join(countries, ", "))
+
+	if film.Budget > 0 {
+		fact("Budget", formatMoney(film.Budget))
+	}
+	if film.Revenue > 0 {
+		fact("Revenue", formatMoney(film.Revenue))
+	}
+	if film.Imdb_id != "" {
+		fact("IMDb", "imdb.com/title/"+film.Imdb_id)
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return sectionStyle.Render("Details") + "\n" + strings.Join(lines, "\n")
+}
+
+// Stream, rent and buy in the user's region, their own services highlighted
+func providersView(g common.Global, filmId int, width int) string {
+	settings := g.Settings.Get(g.AuthState.User.Id)
+	header := sectionStyle.Render("Where to watch in " + settings.Region)
+
+	ok, _, providers := g.ProviderCache.Get(filmId)
+	if !ok {
+		return header + "\n" + sectionStyle.Render("Loading...")
+	}
+
+	region, ok := providers.Results[settings.Region]
+	if !ok {
+		return header + "\n" + sectionStyle.Render("Not available")
+	}
+
+	lines := []string{header}
+	row := func(label string, list []common.Provider) {
+		if len(list) == 0 {
+			return
+		}
+		// Subscribed services go first so truncation doesn't hide them
+		mine, others := []string{}, []string{}
+		for _, p := range list {
+			if settings.Subscribed(p.Provider_id) {
+				mine = append(mine, "✓ "+p.Provider_name)
+			} else {
+				others = append(others, p.Provider_name)
+			}
+		}
+		value := util.TruncAndPadUnicode(strings.Join(append(mine, others...), ", "), util.Max(width-factLabelWidth, 2))
+		if len(mine) > 0 {
+			value = activeNameStyle.Render(value)
+		}
+		lines = append(lines, sectionStyle.Render(util.TruncAndPadUnicode(label, factLabelWidth))+value)
+	}
+	row("Stream", region.Flatrate)
+	row("Free", append(append([]common.Provider{}, region.Free...), region.Ads...))
+	row("Rent", region.Rent)
+	row("Buy", region.Buy)
+
+	return strings.Join(lines, "\n")
+}
+
+// The other films in a collection, like the rest of a trilogy
+type collectionModel struct {
+	props   common.Props
+	filmId  int
+	name    string
+	parts   []common.Film
+	active  int
+	offset  int
+	focused bool
+}
+
+func newCollection(p common.Props) *collectionModel {
+	return &collectionModel{props: p}
+}
+
+func (m *collectionModel) Init(film common.Film) tea.Cmd {
+	m.filmId = film.Id
+	m.name = ""
+	m.parts = nil
+	m.active = 0
+	m.offset = 0
+
+	if film.Belongs_to_collection == nil {
+		return nil
+	}
+
+	filmId := film.Id
+	return common.GetCollectionCmd(m.props.Global, film.Belongs_to_collection.Id, func(data common.Collection, err error) tea.Msg {
+		if filmId != m.filmId || err != nil {
+			return nil
+		}
+
+		// Release order, unreleased last
+		sort.SliceStable(data.Parts, func(i, j int) bool {
+			a, b := data.Parts[i].Release_date, data.Parts[j].Release_date
+			if a == "" || b == "" {
+				return a != "" && b == ""
+			}
+			return a < b
+		})
+
+		m.name = data.Name
+		m.parts = data.Parts
+		for i, part := range m.parts {
+			if part.Id == filmId {
+				m.active = i
+			}
+			if ok, _, _ := m.props.Global.FilmCache.Get(part.Id); !ok {
+				m.props.Global.FilmCache.Set(part.Id, part)
+			}
+		}
+		return nil
+	})
+}
+
+func (m *collectionModel) Empty() bool {
+	return len(m.parts) == 0
+}
+
+func (m *collectionModel) Focused() bool {
+	return m.focused
+}
+
+func (m *collectionModel) Focus() {
+	m.focused = true
+}
+
+func (m *collectionModel) Blur() {
+	m.focused = false
+}
+
+func (m *collectionModel) SetSize(width, height int) {
+	m.props.Width = width
+	m.props.Height = height
+}
+
+func (m *collectionModel) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	if !m.focused || m.Empty() {
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case *common.KeyEvent:
+		switch {
+		case key.Matches(msg.KeyMsg, m.props.Global.KeyMap.Down):
+			msg.Handled = true
+			m.active = util.Min(m.active+1, len(m.parts)-1)
+		case key.Matches(msg.KeyMsg, m.props.Global.KeyMap.Up):
+			msg.Handled = true
+			m.active = util.Max(m.active-1, 0)
+		case key.Matches(msg.KeyMsg, m.props.Global.KeyMap.Select):
+			msg.Handled = true
+			filmId := m.parts[m.active].Id
+			if filmId == m.filmId {
+				return m, nil
+			}
+			return m, func() tea.Msg { return common.ShowFilm(filmId) }
+		}
+	}
+	return m, nil
+}
+
+func (m *collectionModel) View() string {
+	// Header and at least one film
+	if m.Empty() || m.props.Height < 2 {
+		return ""
+	}
+
+	rows := m.props.Height - 1
+	if m.active < m.offset {
+		m.offset = m.active
+	} else if m.active >= m.offset+rows {
+		m.offset = m.active - rows + 1
+	}
+
+	lines := []string{sectionStyle.Render(util.TruncAndPadUnicode("Part of "+m.name, m.props.Width))}
+	end := util.Min(m.offset+rows, len(m.parts))
+	for i := m.offset; i < end; i++ {
+		part := m.parts[i]
+
+		year := "    "
+		if len(part.Release_date) >= 4 {
+			year = part.Release_date[:4]
+		}
+
+		title := util.TruncAndPadUnicode(part.Title, util.Max(m.props.Width-7, 2))
+		switch {
+		case m.focused && i == m.active:
+			title = activeNameStyle.Render(title)
+		case part.Id == m.filmId:
+			title = roleStyle.Render(title)
+		}
+
+		marker := "  "
+		if part.Id == m.filmId {
+			marker = "• "
+		}
+		lines = append(lines, marker+roleStyle.Render(year)+" "+title)
+	}
+	return strings.Join(lines, "\n")
+}
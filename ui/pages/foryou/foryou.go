@@ -0,0 +1,205 @@
+package foryou
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/components/filmrail"
+	"github.com/zhengkyl/review-ssh/ui/util"
+)
+
+var (
+	pageStyle   = lipgloss.NewStyle().Margin(1, 2)
+	hintStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	accentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25D94"))
+)
+
+// A "For you" rail, then one rail per starred film
+type Model struct {
+	props   common.Props
+	rails   []*filmrail.Model
+	active  int
+	offset  int
+	seeds   int
+	loading bool
+	message string
+	seq     int
+	cancel  context.CancelFunc
+}
+
+func New(p common.Props) *Model {
+	m := &Model{
+		props: p,
+	}
+	m.SetSize(p.Width, p.Height)
+	return m
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.props.Width = width - pageStyle.GetHorizontalFrameSize()
+	m.props.Height = height - pageStyle.GetVerticalFrameSize()
+
+	for _, rail := range m.rails {
+		rail.SetSize(m.props.Width, filmrail.Height)
+	}
+}
+
+func (m *Model) stop() {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+	m.seq++
+}
+
+func (m *Model) Init() tea.Cmd {
+	m.stop()
+	m.rails = nil
+	m.active = 0
+	m.offset = 0
+	m.message = ""
+
+	g := m.props.Global
+	seeds := Seeds(g.ReviewMap)
+	m.seeds = len(seeds)
+	if len(seeds) == 0 {
+		m.loading = false
+		m.message = "Star films you loved and suggestions based on them show up here."
+		return nil
+	}
+
+	reviewed := map[int]bool{}
+	for id := range g.ReviewMap {
+		reviewed[id] = true
+	}
+	known := map[int]common.Film{}
+	for _, seed := range seeds {
+		if ok, _, film := g.FilmCache.Get(seed.Tmdb_id); ok {
+			known[seed.Tmdb_id] = film
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.loading = true
+	seq := m.seq
+
+	return func() tea.Msg {
+		results, err := Load(ctx, g, seeds, reviewed, known)
+		return func() tea.Msg {
+			if seq != m.seq {
+				return nil
+			}
+			m.loading = false
+			if err != nil {
+				m.message = fmt.Sprintf("Could not load suggestions (%v)", err)
+				return nil
+			}
+			return m.setRails(results)
+		}
+	}
+}
+
+func (m *Model) newRail(title string, films []common.Film) tea.Cmd {
+	rail := filmrail.New(common.Props{Width: m.props.Width, Height: filmrail.Height, Global: m.props.Global}, title)
+	m.rails = append(m.rails, rail)
+	for _, film := range films {
+		if ok, _, _ := m.props.Global.FilmCache.Get(film.Id); !ok {
+			m.props.Global.FilmCache.Set(film.Id, film)
+		}
+	}
+	return rail.SetFilms(films)
+}
+
+func (m *Model) setRails(seeds []Seed) tea.Cmd {
+	ranked := Rank(seeds)
+	if len(ranked) == 0 {
+		m.message = "Nothing new to suggest, you've seen it all."
+		return nil
+	}
+
+	cmds := []tea.Cmd{m.newRail("For you", ranked)}
+	for _, seed := range seeds {
+		if len(seed.Recommended) == 0 || seed.Film.Id == 0 {
+			continue
+		}
+		cmds = append(cmds, m.newRail("Because you starred "+seed.Film.Title, seed.Recommended))
+	}
+	m.rails[0].Focus()
+	return tea.Batch(cmds...)
+}
+
+// Rails that fit, with the active one always among them
+func (m *Model) visible() int {
+	return util.Max((m.props.Height-2)/(filmrail.Height+1), 1)
+}
+
+func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case *common.KeyEvent:
+		if len(m.rails) == 0 {
+			return m, nil
+		}
+
+		_, cmd := m.rails[m.active].Update(msg)
+		if msg.Handled {
+			return m, cmd
+		}
+
+		km := m.props.Global.KeyMap
+		prev := m.active
+		switch {
+		case key.Matches(msg.KeyMsg, km.Down):
+			msg.Handled = true
+			m.active = util.Min(m.active+1, len(m.rails)-1)
+		case key.Matches(msg.KeyMsg, km.Up):
+			msg.Handled = true
+			m.active = util.Max(m.active-1, 0)
+		}
+		if m.active != prev {
+			m.rails[prev].Blur()
+			m.rails[m.active].Focus()
+		}
+
+		if m.active < m.offset {
+			m.offset = m.active
+		} else if m.active >= m.offset+m.visible() {
+			m.offset = m.active - m.visible() + 1
+		}
+		return m, cmd
+	}
+
+	var cmds []tea.Cmd
+	for _, rail := range m.rails {
+		_, cmd := rail.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m *Model) View() string {
+	view := strings.Builder{}
+	view.WriteString(accentStyle.Render("For you"))
+
+	switch {
+	case m.loading:
+		view.WriteString(hintStyle.Render(fmt.Sprintf("  finding films like your %d most recent stars...", m.seeds)))
+	case m.message != "":
+		view.WriteString("\n\n")
+		view.WriteString(hintStyle.Render(m.message))
+	default:
+		view.WriteString(hintStyle.Render(fmt.Sprintf("  from %d starred films · ↑↓ more", m.seeds)))
+		end := util.Min(m.offset+m.visible(), len(m.rails))
+		for _, rail := range m.rails[m.offset:end] {
+			view.WriteString("\n\n")
+			view.WriteString(rail.View())
+		}
+	}
+
+	return pageStyle.Render(view.String())
+}
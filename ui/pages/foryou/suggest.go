@@ -0,0 +1,106 @@
+package foryou
+
+import (
+	"context"
+	"sort"
+
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/common/enums"
+)
+
+const (
+	// Most recently starred films used, each one is a request
+	maxSeeds     = 10
+	maxSuggested = 20
+	loadWorkers  = 4
+)
+
+// A starred film and what TMDB recommends after it
+type Seed struct {
+	Film        common.Film
+	Recommended []common.Film
+}
+
+// Seeds are completed and starred, newest first
+func Seeds(reviews map[int]common.Review) []common.Review {
+	seeds := []common.Review{}
+	for _, review := range reviews {
+		if review.Status == enums.Completed && review.Fun_after {
+			seeds = append(seeds, review)
+		}
+	}
+	sort.Slice(seeds, func(i, j int) bool {
+		if !seeds[i].Updated_at.Equal(seeds[j].Updated_at) {
+			return seeds[i].Updated_at.After(seeds[j].Updated_at)
+		}
+		return seeds[i].Tmdb_id < seeds[j].Tmdb_id
+	})
+	if len(seeds) > maxSeeds {
+		seeds = seeds[:maxSeeds]
+	}
+	return seeds
+}
+
+// Load gets recommendations for every seed. This blocks.
+// reviewed films are left out of the results, and known films are reused
+// instead of fetched. Pass copies, since this runs outside of Update.
+func Load(ctx context.Context, g common.Global, seeds []common.Review, reviewed map[int]bool, known map[int]common.Film) ([]Seed, error) {
+	results := make([]Seed, len(seeds))
+
+	common.ForEach(ctx, len(seeds), loadWorkers, func(i int) {
+		id := seeds[i].Tmdb_id
+
+		film, ok := known[id]
+		if !ok {
+			film, _ = common.Do[common.Film](ctx, g, "GET", common.FilmURL(g, id, ""), nil)
+		}
+		results[i].Film = film
+
+		// One failed seed shouldn't hide the rest
+		page, err := common.Do[common.Paged[common.Film]](ctx, g, "GET", common.RecommendationsURL(g, id), nil)
+		if err != nil {
+			return
+		}
+		for _, rec := range page.Results {
+			if !reviewed[rec.Id] {
+				results[i].Recommended = append(results[i].Recommended, rec)
+			}
+		}
+	})
+
+	return results, ctx.Err()
+}
+
+// Rank puts films recommended after several starred films first, then
+// films near the top of a single list.
+func Rank(seeds []Seed) []common.Film {
+	scores := map[int]float64{}
+	films := map[int]common.Film{}
+	for _, seed := range seeds {
+		for rank, film := range seed.Recommended {
+			// 1 for the top pick, down to 0.5 for the last
+			scores[film.Id] += 1 - 0.5*float64(rank)/float64(len(seed.Recommended))
+			films[film.Id] = film
+		}
+	}
+
+	ranked := make([]common.Film, 0, len(films))
+	for _, film := range films {
+		ranked = append(ranked, film)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if scores[a.Id] != scores[b.Id] {
+			return scores[a.Id] > scores[b.Id]
+		}
+		if a.Vote_average != b.Vote_average {
+			return a.Vote_average > b.Vote_average
+		}
+		return a.Id < b.Id
+	})
+
+	if len(ranked) > maxSuggested {
+		ranked = ranked[:maxSuggested]
+	}
+	return ranked
+}
@@ -0,0 +1,344 @@
+package group
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/common/enums"
+	"github.com/zhengkyl/review-ssh/ui/util"
+)
+
+const loadWorkers = 4
+
+var (
+	pageStyle   = lipgloss.NewStyle().Margin(0, 1)
+	nameStyle   = lipgloss.NewStyle().Bold(true)
+	mutedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	activeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25D94"))
+	errStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("197"))
+)
+
+// One movie night list, with votes and who has seen what
+type Model struct {
+	props   common.Props
+	groupId int
+	group   common.Group
+	found   bool
+	// Members who completed each film
+	seen        map[int][]int
+	loadingSeen bool
+	picked      int
+	message     string
+	active      int
+	offset      int
+	seq         int
+	cancel      context.CancelFunc
+	rnd         *rand.Rand
+}
+
+func New(p common.Props) *Model {
+	m := &Model{
+		props: p,
+		rnd:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	m.SetSize(p.Width, p.Height)
+	return m
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.props.Width = width - pageStyle.GetHorizontalFrameSize()
+	m.props.Height = height - pageStyle.GetVerticalFrameSize()
+}
+
+func (m *Model) userId() int {
+	return m.props.Global.AuthState.User.Id
+}
+
+func (m *Model) Init(groupId int) tea.Cmd {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+	m.seq++
+	m.groupId = groupId
+	m.seen = map[int][]int{}
+	m.picked = 0
+	m.message = ""
+	m.active = 0
+	m.offset = 0
+	m.refresh()
+	if !m.found {
+		m.loadingSeen = false
+		return nil
+	}
+
+	g := m.props.Global
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.loadingSeen = true
+	seq := m.seq
+	members := m.group.Members
+
+	cmds := []tea.Cmd{m.loadFilms(), func() tea.Msg {
+		seen, err := loadSeen(ctx, g, members)
+		return func() tea.Msg {
+			if seq != m.seq {
+				return nil
+			}
+			m.loadingSeen = false
+			if err != nil {
+				m.message = fmt.Sprintf("Could not check who has seen what (%v)", err)
+			}
+			m.seen = seen
+			return nil
+		}
+	}}
+	for _, userId := range members {
+		cmds = append(cmds, common.GetUserCmd(g, userId))
+	}
+	return tea.Batch(cmds...)
+}
+
+// Reads the group again, since other members change it too
+func (m *Model) refresh() {
+	var activeId int
+	if m.active < len(m.group.Films) {
+		activeId = m.group.Films[m.active].Tmdb_id
+	}
+
+	m.group, m.found = m.props.Global.Groups.Get(m.groupId)
+
+	// Most votes first, then oldest
+	sort.SliceStable(m.group.Films, func(i, j int) bool {
+		return len(m.group.Films[i].Votes) > len(m.group.Films[j].Votes)
+	})
+
+	m.active = util.Max(util.Min(m.active, len(m.group.Films)-1), 0)
+	for i, f := range m.group.Films {
+		if f.Tmdb_id == activeId {
+			m.active = i
+		}
+	}
+	m.scroll()
+}
+
+// Completed films for each member. This blocks.
+func loadSeen(ctx context.Context, g common.Global, members []int) (map[int][]int, error) {
+	var (
+		mtx     sync.Mutex
+		seen    = map[int][]int{}
+		lastErr error
+	)
+
+	common.ForEach(ctx, len(members), loadWorkers, func(i int) {
+		userId := members[i]
+		reviews, err := common.FetchAllReviews(ctx, g, userId)
+
+		mtx.Lock()
+		defer mtx.Unlock()
+		if err != nil {
+			lastErr = err
+		}
+		for filmId, review := range reviews {
+			if review.Status == enums.Completed {
+				seen[filmId] = append(seen[filmId], userId)
+			}
+		}
+	})
+
+	return seen, lastErr
+}
+
+// Rows that fit below the header and above the hint
+func (m *Model) listHeight() int {
+	return util.Max(m.props.Height-6, 1)
+}
+
+func (m *Model) scroll() {
+	if m.active < m.offset {
+		m.offset = m.active
+	} else if m.active >= m.offset+m.listHeight() {
+		m.offset = m.active - m.listHeight() + 1
+	}
+}
+
+// Only titles on screen are fetched
+func (m *Model) loadFilms() tea.Cmd {
+	var cmds []tea.Cmd
+	end := util.Min(m.offset+m.listHeight(), len(m.group.Films))
+	for _, f := range m.group.Films[m.offset:end] {
+		cmds = append(cmds, common.GetFilmCmd(m.props.Global, f.Tmdb_id))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case *common.KeyEvent:
+		if !m.found || len(m.group.Films) == 0 {
+			return m, nil
+		}
+		km := m.props.Global.KeyMap
+		g := m.props.Global
+
+		switch {
+		case key.Matches(msg.KeyMsg, km.Down):
+			msg.Handled = true
+			m.active = util.Min(m.active+1, len(m.group.Films)-1)
+		case key.Matches(msg.KeyMsg, km.Up):
+			msg.Handled = true
+			m.active = util.Max(m.active-1, 0)
+		case key.Matches(msg.KeyMsg, km.Select):
+			msg.Handled = true
+			filmId := m.group.Films[m.active].Tmdb_id
+			return m, func() tea.Msg { return common.ShowFilm(filmId) }
+		case key.Matches(msg.KeyMsg, km.Vote):
+			msg.Handled = true
+			m.message = ""
+			if err := g.Groups.ToggleVote(m.groupId, m.userId(), m.group.Films[m.active].Tmdb_id); err != nil {
+				m.message = err.Error()
+			}
+			m.refresh()
+		case key.Matches(msg.KeyMsg, km.Remove):
+			msg.Handled = true
+			m.message = ""
+			if err := g.Groups.ToggleFilm(m.groupId, m.userId(), m.group.Films[m.active].Tmdb_id); err != nil {
+				m.message = err.Error()
+			}
+			m.refresh()
+		case key.Matches(msg.KeyMsg, km.Pick):
+			msg.Handled = true
+			if m.loadingSeen {
+				return m, nil
+			}
+			m.refresh()
+			filmId, ok := Pick(m.group.Films, m.seen, m.rnd)
+			if !ok {
+				m.message = "Everything here has been seen by someone. Add more films!"
+				return m, nil
+			}
+			m.message = ""
+			m.picked = filmId
+			for i, f := range m.group.Films {
+				if f.Tmdb_id == filmId {
+					m.active = i
+				}
+			}
+		}
+
+		m.scroll()
+		return m, m.loadFilms()
+	}
+	return m, nil
+}
+
+func (m *Model) userName(userId int) string {
+	if userId == m.userId() {
+		return "you"
+	}
+	if ok, _, user := m.props.Global.UserCache.Get(userId); ok {
+		return user.Name
+	}
+	return fmt.Sprintf("#%d", userId)
+}
+
+func (m *Model) filmTitle(filmId int) string {
+	if ok, _, film := m.props.Global.FilmCache.Get(filmId); ok {
+		if len(film.Release_date) >= 4 {
+			return film.Title + " (" + film.Release_date[:4] + ")"
+		}
+		return film.Title
+	}
+	return "Loading..."
+}
+
+func (m *Model) headerView() string {
+	members := []string{}
+	for _, userId := range m.group.Members {
+		members = append(members, m.userName(userId))
+	}
+	return nameStyle.Render(m.group.Name) + "  " +
+		mutedStyle.Render(fmt.Sprintf("invite %s · %s", m.group.Invite, strings.Join(members, ", ")))
+}
+
+func (m *Model) filmsView() string {
+	if len(m.group.Films) == 0 {
+		return mutedStyle.Render("No films yet. Press g on a film's details to add it here.")
+	}
+
+	voteWidth := 4
+	titleWidth := util.Max(m.props.Width/2, 10)
+	seenWidth := util.Max(m.props.Width-voteWidth-1-titleWidth-1, 4)
+
+	lines := []string{}
+	end := util.Min(m.offset+m.listHeight(), len(m.group.Films))
+	for i := m.offset; i < end; i++ {
+		f := m.group.Films[i]
+
+		votes := util.TruncAndPadUnicode(fmt.Sprintf("▲ %d", len(f.Votes)), voteWidth)
+		voted := false
+		for _, userId := range f.Votes {
+			voted = voted || userId == m.userId()
+		}
+		if voted {
+			votes = activeStyle.Render(votes)
+		} else {
+			votes = mutedStyle.Render(votes)
+		}
+
+		title := util.TruncAndPadUnicode(m.filmTitle(f.Tmdb_id), titleWidth)
+		if i == m.active {
+			title = activeStyle.Render(title)
+		}
+
+		seen := ""
+		if names := m.seen[f.Tmdb_id]; len(names) > 0 {
+			who := []string{}
+			for _, userId := range names {
+				who = append(who, m.userName(userId))
+			}
+			seen = "seen by " + strings.Join(who, ", ")
+		}
+		lines = append(lines, votes+" "+title+" "+mutedStyle.Render(util.TruncAndPadUnicode(seen, seenWidth)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) View() string {
+	if !m.found {
+		return pageStyle.Render("This group no longer exists.")
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(m.headerView())
+	sb.WriteString("\n\n")
+	sb.WriteString(m.filmsView())
+	sb.WriteString(strings.Repeat("\n", util.Max(m.props.Height-2-lipgloss.Height(sb.String()), 1)))
+
+	switch {
+	case m.message != "":
+		sb.WriteString(errStyle.Render(m.message))
+	case m.picked != 0:
+		sb.WriteString("Tonight's film: " + activeStyle.Render(m.filmTitle(m.picked)))
+	case m.loadingSeen:
+		sb.WriteString(mutedStyle.Render("Checking who has seen what..."))
+	}
+	sb.WriteString("\n")
+
+	hint := "enter details · v vote · x remove · p pick tonight's film"
+	if len(m.group.Films) == 0 {
+		hint = fmt.Sprintf("Others join with g then a, using invite code %s", m.group.Invite)
+	}
+	sb.WriteString(mutedStyle.Render(hint))
+
+	return pageStyle.Render(sb.String())
+}
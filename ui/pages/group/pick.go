@@ -0,0 +1,37 @@
+package group
+
+import (
+	"math/rand"
+
+	"github.com/zhengkyl/review-ssh/ui/common"
+)
+
+// Pick chooses a film no member has seen. Each vote makes a film more likely,
+// but films nobody voted for still have a chance.
+func Pick(films []common.GroupFilm, seen map[int][]int, rnd *rand.Rand) (int, bool) {
+	total := 0
+	for _, f := range films {
+		if len(seen[f.Tmdb_id]) == 0 {
+			total += weight(f)
+		}
+	}
+	if total == 0 {
+		return 0, false
+	}
+
+	n := rnd.Intn(total)
+	for _, f := range films {
+		if len(seen[f.Tmdb_id]) > 0 {
+			continue
+		}
+		n -= weight(f)
+		if n < 0 {
+			return f.Tmdb_id, true
+		}
+	}
+	return 0, false
+}
+
+func weight(f common.GroupFilm) int {
+	return 1 + 2*len(f.Votes)
+}
@@ -0,0 +1,253 @@
+package groups
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/util"
+)
+
+var (
+	pageStyle   = lipgloss.NewStyle().Margin(1, 2)
+	mutedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	accentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25D94"))
+	errStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("197"))
+)
+
+type prompt int
+
+const (
+	none prompt = iota
+	naming
+	joining
+)
+
+// The group lists you're in
+type Model struct {
+	props  common.Props
+	groups []common.Group
+	active int
+	offset int
+	prompt prompt
+	input  textinput.Model
+	err    string
+}
+
+func New(p common.Props) *Model {
+	input := textinput.New()
+	input.CharLimit = 40
+
+	m := &Model{
+		props: p,
+		input: input,
+	}
+	m.SetSize(p.Width, p.Height)
+	return m
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.props.Width = width - pageStyle.GetHorizontalFrameSize()
+	m.props.Height = height - pageStyle.GetVerticalFrameSize()
+}
+
+func (m *Model) userId() int {
+	return m.props.Global.AuthState.User.Id
+}
+
+// Every guest has the same id, so they would all share groups
+func (m *Model) guest() bool {
+	return m.userId() == common.GuestAuthState.User.Id
+}
+
+func (m *Model) Init() tea.Cmd {
+	m.prompt = none
+	m.input.Blur()
+	m.err = ""
+	m.refresh()
+	return nil
+}
+
+func (m *Model) refresh() {
+	m.groups = m.props.Global.Groups.Groups(m.userId())
+	m.active = util.Max(util.Min(m.active, len(m.groups)-1), 0)
+	m.scroll()
+}
+
+// Rows that fit below the title and above the hint
+func (m *Model) listHeight() int {
+	return util.Max(m.props.Height-4, 1)
+}
+
+func (m *Model) scroll() {
+	if m.active < m.offset {
+		m.offset = m.active
+	} else if m.active >= m.offset+m.listHeight() {
+		m.offset = m.active - m.listHeight() + 1
+	}
+}
+
+func (m *Model) startPrompt(p prompt) tea.Cmd {
+	m.prompt = p
+	m.err = ""
+	m.input.SetValue("")
+	if p == naming {
+		m.input.Prompt = "Name: "
+		m.input.Placeholder = "Friday movie night"
+	} else {
+		m.input.Prompt = "Invite code: "
+		m.input.Placeholder = ""
+	}
+	return m.input.Focus()
+}
+
+func (m *Model) submit() tea.Cmd {
+	g := m.props.Global
+	value := strings.TrimSpace(m.input.Value())
+
+	switch m.prompt {
+	case naming:
+		if value == "" {
+			m.err = "Groups need a name"
+			return nil
+		}
+		group := g.Groups.Create(m.userId(), value)
+		m.prompt = none
+		m.input.Blur()
+		return func() tea.Msg { return common.ShowGroup(group.Id) }
+	case joining:
+		group, err := g.Groups.Join(value, m.userId())
+		if err != nil {
+			m.err = err.Error()
+			return nil
+		}
+		m.prompt = none
+		m.input.Blur()
+		return func() tea.Msg { return common.ShowGroup(group.Id) }
+	}
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case *common.KeyEvent:
+		if m.guest() {
+			return m, nil
+		}
+		km := m.props.Global.KeyMap
+
+		if m.prompt != none {
+			msg.Handled = true
+			switch msg.KeyMsg.Type {
+			case tea.KeyEsc:
+				m.prompt = none
+				m.input.Blur()
+				return m, nil
+			case tea.KeyEnter:
+				return m, m.submit()
+			}
+			m.err = ""
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg.KeyMsg)
+			return m, cmd
+		}
+
+		switch {
+		case key.Matches(msg.KeyMsg, km.New):
+			msg.Handled = true
+			return m, m.startPrompt(naming)
+		case key.Matches(msg.KeyMsg, km.Add):
+			msg.Handled = true
+			return m, m.startPrompt(joining)
+		}
+
+		if len(m.groups) == 0 {
+			return m, nil
+		}
+
+		switch {
+		case key.Matches(msg.KeyMsg, km.Down):
+			msg.Handled = true
+			m.active = util.Min(m.active+1, len(m.groups)-1)
+		case key.Matches(msg.KeyMsg, km.Up):
+			msg.Handled = true
+			m.active = util.Max(m.active-1, 0)
+		case key.Matches(msg.KeyMsg, km.Select):
+			msg.Handled = true
+			groupId := m.groups[m.active].Id
+			return m, func() tea.Msg { return common.ShowGroup(groupId) }
+		case key.Matches(msg.KeyMsg, km.Remove):
+			msg.Handled = true
+			m.err = ""
+			if err := m.props.Global.Groups.Leave(m.groups[m.active].Id, m.userId()); err != nil {
+				m.err = err.Error()
+			}
+			m.refresh()
+		}
+		m.scroll()
+		return m, nil
+	}
+
+	if m.prompt != none {
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m *Model) listView() string {
+	if len(m.groups) == 0 {
+		return mutedStyle.Render("You're not in any groups. Make one with n, or join a friend's with a.")
+	}
+
+	lines := []string{}
+	end := util.Min(m.offset+m.listHeight(), len(m.groups))
+	for i := m.offset; i < end; i++ {
+		group := m.groups[i]
+		name := util.TruncAndPadUnicode(group.Name, util.Max(m.props.Width/2, 10))
+		if i == m.active {
+			name = accentStyle.Render(name)
+		}
+		info := fmt.Sprintf("#%d · %d members · %d films", group.Id, len(group.Members), len(group.Films))
+		lines = append(lines, name+" "+mutedStyle.Render(info))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) View() string {
+	sb := strings.Builder{}
+	sb.WriteString(accentStyle.Render("Groups"))
+	sb.WriteString(mutedStyle.Render("  shared watchlists for movie nights"))
+	sb.WriteString("\n\n")
+
+	if m.guest() {
+		sb.WriteString(mutedStyle.Render("Sign in to make group lists with friends."))
+		return pageStyle.Render(sb.String())
+	}
+
+	sb.WriteString(m.listView())
+	sb.WriteString(strings.Repeat("\n", util.Max(m.props.Height-1-lipgloss.Height(sb.String()), 1)))
+
+	switch {
+	case m.prompt != none:
+		sb.WriteString(m.input.View())
+		if m.err != "" {
+			sb.WriteString(" " + errStyle.Render(m.err))
+		}
+	case m.err != "":
+		sb.WriteString(errStyle.Render(m.err))
+	default:
+		hint := "n new · a join by invite code"
+		if len(m.groups) > 0 {
+			hint += " · enter open · x leave"
+		}
+		sb.WriteString(mutedStyle.Render(hint))
+	}
+
+	return pageStyle.Render(sb.String())
+}
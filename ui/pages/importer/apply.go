@@ -0,0 +1,187 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/common/enums"
+)
+
+type Action int
+
+const (
+	Add Action = iota
+	Update
+	Unchanged
+	Unresolved
+)
+
+type Change struct {
+	Action Action
+	Entry  Entry
+	Film   common.Film
+	// What the review should look like after applying, compared to Before
+	Status     enums.Status
+	Fun_during bool
+	Fun_after  bool
+	Before     *common.Review // nil if Add or Unresolved
+}
+
+// Imports only ever add information. Completed is never moved back to Plan To Watch,
+// and likes/stars are never removed.
+func Plan(resolutions []Resolution, existing map[int]common.Review) []Change {
+	changes := make([]Change, 0, len(resolutions))
+	planned := map[int]int{} // tmdb id -> index in changes
+
+	for _, res := range resolutions {
+		if !res.Resolved() {
+			changes = append(changes, Change{Action: Unresolved, Entry: res.Entry})
+			continue
+		}
+
+		// Different entries can resolve to the same film, merge them like dedupe()
+		if i, ok := planned[res.Film.Id]; ok {
+			c := &changes[i]
+			if res.Entry.Status == enums.Completed {
+				c.Status = enums.Completed
+			}
+			c.Fun_during = c.Fun_during || res.Entry.Like()
+			c.Fun_after = c.Fun_after || res.Entry.Star()
+			c.Action = diff(*c)
+			continue
+		}
+
+		change := Change{
+			Entry:      res.Entry,
+			Film:       res.Film,
+			Status:     res.Entry.Status,
+			Fun_during: res.Entry.Like(),
+			Fun_after:  res.Entry.Star(),
+		}
+
+		if review, ok := existing[res.Film.Id]; ok {
+			review := review
+			change.Before = &review
+			if review.Status == enums.Completed {
+				change.Status = enums.Completed
+			}
+			change.Fun_during = change.Fun_during || review.Fun_during
+			change.Fun_after = change.Fun_after || review.Fun_after
+		}
+		change.Action = diff(change)
+
+		planned[res.Film.Id] = len(changes)
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+func diff(c Change) Action {
+	if c.Before == nil {
+		return Add
+	}
+	if c.Before.Status == c.Status && c.Before.Fun_during == c.Fun_during && c.Before.Fun_after == c.Fun_after {
+		return Unchanged
+	}
+	return Update
+}
+
+func (c Change) Title() string {
+	if c.Film.Id == 0 {
+		return c.Entry.String()
+	}
+	if len(c.Film.Release_date) >= 4 {
+		return fmt.Sprintf("%s (%s)", c.Film.Title, c.Film.Release_date[:4])
+	}
+	return c.Film.Title
+}
+
+func funString(during, after bool) string {
+	return common.RenderRating(false, during, after)
+}
+
+// One line diff, like "+ Heat (1995)  Completed ██ ░░"
+func (c Change) String() string {
+	switch c.Action {
+	case Add:
+		return fmt.Sprintf("+ %s  %s %s", c.Title(), c.Status.DisplayString(), funString(c.Fun_during, c.Fun_after))
+	case Update:
+		before := fmt.Sprintf("%s %s", c.Before.Status.DisplayString(), funString(c.Before.Fun_during, c.Before.Fun_after))
+		after := fmt.Sprintf("%s %s", c.Status.DisplayString(), funString(c.Fun_during, c.Fun_after))
+		return fmt.Sprintf("~ %s  %s → %s", c.Title(), strings.TrimSpace(before), strings.TrimSpace(after))
+	case Unchanged:
+		return "  " + c.Title()
+	}
+	return "? " + c.Title() + "  no match"
+}
+
+type Summary struct {
+	Added, Updated, Unchanged, Unresolved int
+}
+
+func Summarize(changes []Change) Summary {
+	s := Summary{}
+	for _, c := range changes {
+		switch c.Action {
+		case Add:
+			s.Added++
+		case Update:
+			s.Updated++
+		case Unchanged:
+			s.Unchanged++
+		case Unresolved:
+			s.Unresolved++
+		}
+	}
+	return s
+}
+
+func (s Summary) String() string {
+	return fmt.Sprintf("%d to add, %d to update, %d unchanged, %d not found", s.Added, s.Updated, s.Unchanged, s.Unresolved)
+}
+
+// Pending returns only the changes that need a request, adds first then by title
+func Pending(changes []Change) []Change {
+	pending := []Change{}
+	for _, c := range changes {
+		if c.Action == Add || c.Action == Update {
+			pending = append(pending, c)
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool {
+		if pending[i].Action != pending[j].Action {
+			return pending[i].Action < pending[j].Action
+		}
+		return strings.ToLower(pending[i].Title()) < strings.ToLower(pending[j].Title())
+	})
+	return pending
+}
+
+// Apply makes the review match c. This blocks, so the caller should update ReviewMap with the result.
+func Apply(ctx context.Context, g common.Global, c Change) (common.Review, error) {
+	tmdbId := c.Film.Id
+	ctx = common.WithAudit(ctx, "via", "import")
+	if c.Before != nil {
+		ctx = common.WithAudit(ctx, "before", *c.Before)
+	}
+
+	var review common.Review
+	var err error
+	if c.Action == Add {
+		review, err = common.PostReview(ctx, g, tmdbId, c.Status.String())
+		if err != nil || (!c.Fun_during && !c.Fun_after) {
+			return review, err
+		}
+	}
+
+	updates := map[string]interface{}{
+		"status":     c.Status.String(),
+		"fun_during": c.Fun_during,
+		"fun_after":  c.Fun_after,
+	}
+	return common.PatchReview(ctx, g, tmdbId, updates)
+}
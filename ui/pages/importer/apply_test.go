@@ -0,0 +1,71 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/common/enums"
+)
+
+func TestPlan(t *testing.T) {
+	film := common.Film{Id: 348, Title: "Alien"}
+	resolved := func(status enums.Status, rating float64) Resolution {
+		return Resolution{Entry: Entry{Title: "Alien", Status: status, Rating: rating}, Film: film}
+	}
+
+	tests := []struct {
+		name        string
+		resolutions []Resolution
+		existing    map[int]common.Review
+		want        Change
+	}{
+		{
+			name:        "new film is added",
+			resolutions: []Resolution{resolved(enums.Completed, 0.8)},
+			want:        Change{Action: Add, Status: enums.Completed, Fun_during: true},
+		},
+		{
+			name:        "unresolved",
+			resolutions: []Resolution{{Entry: Entry{Title: "Alien"}}},
+			want:        Change{Action: Unresolved},
+		},
+		{
+			name:        "completed is never moved back to plan to watch",
+			resolutions: []Resolution{resolved(enums.PlanToWatch, -1)},
+			existing:    map[int]common.Review{348: {Tmdb_id: 348, Status: enums.Completed}},
+			want:        Change{Action: Unchanged, Status: enums.Completed},
+		},
+		{
+			name:        "likes and stars are never removed",
+			resolutions: []Resolution{resolved(enums.Completed, 0.2)},
+			existing:    map[int]common.Review{348: {Tmdb_id: 348, Status: enums.Completed, Fun_during: true, Fun_after: true}},
+			want:        Change{Action: Unchanged, Status: enums.Completed, Fun_during: true, Fun_after: true},
+		},
+		{
+			name:        "plan to watch is upgraded",
+			resolutions: []Resolution{resolved(enums.Completed, 0.9)},
+			existing:    map[int]common.Review{348: {Tmdb_id: 348, Status: enums.PlanToWatch}},
+			want:        Change{Action: Update, Status: enums.Completed, Fun_during: true, Fun_after: true},
+		},
+		{
+			name:        "entries for the same film are merged",
+			resolutions: []Resolution{resolved(enums.PlanToWatch, -1), resolved(enums.Completed, 0.9)},
+			existing:    map[int]common.Review{348: {Tmdb_id: 348, Status: enums.PlanToWatch}},
+			want:        Change{Action: Update, Status: enums.Completed, Fun_during: true, Fun_after: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := Plan(tt.resolutions, tt.existing)
+			if len(changes) != 1 {
+				t.Fatalf("Plan() made %d changes, want 1", len(changes))
+			}
+			got := changes[0]
+			if got.Action != tt.want.Action || got.Status != tt.want.Status ||
+				got.Fun_during != tt.want.Fun_during || got.Fun_after != tt.want.Fun_after {
+				t.Errorf("Plan() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,344 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zhengkyl/review-ssh/ui/common/enums"
+)
+
+type Format string
+
+const (
+	Auto       Format = ""
+	Letterboxd Format = "letterboxd"
+	// watchlist.csv has the same columns as watched.csv, so it can't be detected
+	LetterboxdWatchlist Format = "letterboxd-watchlist"
+	IMDb                Format = "imdb"
+	Trakt               Format = "trakt"
+)
+
+var Formats = []Format{Auto, Letterboxd, LetterboxdWatchlist, IMDb, Trakt}
+
+func (f Format) String() string {
+	if f == Auto {
+		return "auto"
+	}
+	return string(f)
+}
+
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(s, f.String()) {
+			return f, nil
+		}
+	}
+	return Auto, fmt.Errorf("unknown format %q", s)
+}
+
+// Rating thresholds, as a fraction of the source's max rating.
+// 4/5 stars on Letterboxd is a like, 4.5 is a star.
+const (
+	likeRating = 0.8
+	starRating = 0.9
+)
+
+type Entry struct {
+	Title   string
+	Year    int
+	TmdbId  int    // 0 if unknown
+	ImdbId  string // "" if unknown
+	Status  enums.Status
+	Rating  float64 // 0-1, or -1 if unrated
+	Watched time.Time
+}
+
+func (e Entry) Like() bool {
+	return e.Rating >= likeRating
+}
+
+func (e Entry) Star() bool {
+	return e.Rating >= starRating
+}
+
+func (e Entry) String() string {
+	if e.Year == 0 {
+		return e.Title
+	}
+	return fmt.Sprintf("%s (%d)", e.Title, e.Year)
+}
+
+// Detect guesses the format from the first line of an export
+func Detect(data []byte) (Format, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return Auto, errors.New("nothing to import")
+	}
+
+	if trimmed[0] == '[' {
+		return Trakt, nil
+	}
+
+	header, _, _ := bytes.Cut(trimmed, []byte("\n"))
+	switch {
+	// tmdbID is in Letterboxd's import format, which the exporter writes
+	case bytes.Contains(header, []byte("Letterboxd URI")), bytes.Contains(header, []byte("tmdbID")):
+		return Letterboxd, nil
+	case bytes.Contains(header, []byte("Const")):
+		return IMDb, nil
+	}
+
+	return Auto, errors.New("unrecognized format, expected Letterboxd or IMDb csv, or Trakt json")
+}
+
+func Parse(data []byte, format Format) ([]Entry, error) {
+	if format == Auto {
+		var err error
+		format, err = Detect(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var entries []Entry
+	var err error
+	switch format {
+	case Letterboxd:
+		entries, err = parseLetterboxd(bytes.NewReader(data), enums.Completed)
+	case LetterboxdWatchlist:
+		entries, err = parseLetterboxd(bytes.NewReader(data), enums.PlanToWatch)
+	case IMDb:
+		entries, err = parseIMDb(bytes.NewReader(data))
+	case Trakt:
+		entries, err = parseTrakt(data)
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return dedupe(entries), nil
+}
+
+// Maps header names to a row's values
+type csvRow map[string]string
+
+func readCSV(r io.Reader) ([]csvRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("empty csv")
+	}
+
+	header := records[0]
+	// Excel likes to add a byte order mark
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+	rows := make([]csvRow, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := csvRow{}
+		for i, name := range header {
+			if i < len(record) {
+				row[strings.TrimSpace(name)] = strings.TrimSpace(record[i])
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (r csvRow) int(name string) int {
+	n, _ := strconv.Atoi(r[name])
+	return n
+}
+
+// Returns -1 if blank or invalid
+func (r csvRow) rating(name string, max float64) float64 {
+	rating, err := strconv.ParseFloat(r[name], 64)
+	if err != nil || rating <= 0 {
+		return -1
+	}
+	return rating / max
+}
+
+func (r csvRow) date(names ...string) time.Time {
+	for _, name := range names {
+		if t, err := time.Parse("2006-01-02", r[name]); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// Handles diary.csv, watched.csv, ratings.csv and watchlist.csv, and the import
+// format the exporter writes, which names columns Title and WatchedDate and has tmdbID
+func parseLetterboxd(r io.Reader, status enums.Status) ([]Entry, error) {
+	rows, err := readCSV(r)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(rows))
+	for _, row := range rows {
+		title := row["Name"]
+		if title == "" {
+			title = row["Title"]
+		}
+		if title == "" {
+			continue
+		}
+
+		entries = append(entries, Entry{
+			Title:   title,
+			Year:    row.int("Year"),
+			TmdbId:  row.int("tmdbID"),
+			Status:  status,
+			Rating:  row.rating("Rating", 5),
+			Watched: row.date("Watched Date", "WatchedDate", "Date"),
+		})
+	}
+	return entries, nil
+}
+
+// Handles ratings.csv and watchlist csv exports
+func parseIMDb(r io.Reader) ([]Entry, error) {
+	rows, err := readCSV(r)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(rows))
+	for _, row := range rows {
+		if !strings.HasPrefix(row["Const"], "tt") {
+			continue
+		}
+
+		// Skip tv series and episodes, older exports call movies "Feature Film"
+		titleType := strings.ToLower(row["Title Type"])
+		if titleType != "" && !strings.Contains(titleType, "movie") && !strings.Contains(titleType, "film") {
+			continue
+		}
+
+		// Watchlist exports have the column too, just empty
+		status := enums.PlanToWatch
+		if row["Your Rating"] != "" {
+			status = enums.Completed
+		}
+
+		entries = append(entries, Entry{
+			Title:   row["Title"],
+			Year:    row.int("Year"),
+			ImdbId:  row["Const"],
+			Status:  status,
+			Rating:  row.rating("Your Rating", 10),
+			Watched: row.date("Date Rated", "Created"),
+		})
+	}
+	return entries, nil
+}
+
+type traktItem struct {
+	Listed_at    *time.Time // Only in watchlist
+	Last_watched *time.Time `json:"last_watched_at"`
+	Watched_at   *time.Time
+	Rated_at     *time.Time
+	Rating       float64
+	Movie        *struct {
+		Title string
+		Year  int
+		Ids   struct {
+			Tmdb int
+			Imdb string
+		}
+	}
+}
+
+// Handles watched-movies.json, ratings-movies.json, history and watchlist json exports
+func parseTrakt(data []byte) ([]Entry, error) {
+	var items []traktItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(items))
+	for _, item := range items {
+		if item.Movie == nil {
+			continue
+		}
+
+		entry := Entry{
+			Title:  item.Movie.Title,
+			Year:   item.Movie.Year,
+			TmdbId: item.Movie.Ids.Tmdb,
+			ImdbId: item.Movie.Ids.Imdb,
+			Status: enums.Completed,
+			Rating: -1,
+		}
+
+		if item.Listed_at != nil {
+			entry.Status = enums.PlanToWatch
+		}
+		if item.Rating > 0 {
+			entry.Rating = item.Rating / 10
+		}
+		for _, t := range []*time.Time{item.Last_watched, item.Watched_at, item.Rated_at, item.Listed_at} {
+			if t != nil {
+				entry.Watched = *t
+				break
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (e Entry) key() string {
+	switch {
+	case e.TmdbId != 0:
+		return "tmdb:" + strconv.Itoa(e.TmdbId)
+	case e.ImdbId != "":
+		return "imdb:" + e.ImdbId
+	}
+	return strings.ToLower(e.Title) + ":" + strconv.Itoa(e.Year)
+}
+
+// Diaries list rewatches separately. Merge them, preferring Completed and the best rating.
+func dedupe(entries []Entry) []Entry {
+	index := map[string]int{}
+	merged := make([]Entry, 0, len(entries))
+
+	for _, entry := range entries {
+		i, ok := index[entry.key()]
+		if !ok {
+			index[entry.key()] = len(merged)
+			merged = append(merged, entry)
+			continue
+		}
+
+		existing := &merged[i]
+		if entry.Status == enums.Completed {
+			existing.Status = enums.Completed
+		}
+		if entry.Rating > existing.Rating {
+			existing.Rating = entry.Rating
+		}
+		if entry.Watched.After(existing.Watched) {
+			existing.Watched = entry.Watched
+		}
+	}
+	return merged
+}
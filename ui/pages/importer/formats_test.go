@@ -0,0 +1,134 @@
+package importer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zhengkyl/review-ssh/ui/common/enums"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Format
+		wantErr bool
+	}{
+		{"letterboxd", "Date,Name,Year,Letterboxd URI,Rating\n", Letterboxd, false},
+		{"letterboxd with bom", "\ufeffDate,Name,Year,Letterboxd URI\n", Letterboxd, false},
+		{"exported letterboxd", "tmdbID,Title,Year,Rating,WatchedDate,Review\n", Letterboxd, false},
+		{"imdb", "Const,Your Rating,Date Rated,Title\n", IMDb, false},
+		{"trakt", "  [{\"movie\": {}}]", Trakt, false},
+		{"empty", " \n ", Auto, true},
+		{"unknown", "title,rating\nAlien,5\n", Auto, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Detect([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Detect() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Detect() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+
+	tests := []struct {
+		name   string
+		data   string
+		format Format
+		want   []Entry
+	}{
+		{
+			name:   "letterboxd diary merges rewatches",
+			format: Auto,
+			data: "\ufeffDate,Name,Year,Letterboxd URI,Rating,Rewatch,Watched Date\n" +
+				"2024-01-02,Alien,1979,https://boxd.it/a,3,,2024-01-01\n" +
+				"2024-03-02,Alien,1979,https://boxd.it/b,4.5,Yes,2024-03-01\n" +
+				",,,,,,\n",
+			want: []Entry{
+				{Title: "Alien", Year: 1979, Status: enums.Completed, Rating: 0.9, Watched: date("2024-03-01")},
+			},
+		},
+		{
+			name:   "letterboxd csv from the exporter",
+			format: Auto,
+			data: "tmdbID,Title,Year,Rating,WatchedDate,Review\n" +
+				"348,Alien,1979,4.5,2024-03-01,\"Great, again\"\n" +
+				"949,Heat,1995,,2024-01-02,\n",
+			want: []Entry{
+				{Title: "Alien", Year: 1979, TmdbId: 348, Status: enums.Completed, Rating: 0.9, Watched: date("2024-03-01")},
+				{Title: "Heat", Year: 1995, TmdbId: 949, Status: enums.Completed, Rating: -1, Watched: date("2024-01-02")},
+			},
+		},
+		{
+			name:   "letterboxd watchlist",
+			format: LetterboxdWatchlist,
+			data:   "Date,Name,Year,Letterboxd URI\n2024-01-02,Heat,1995,https://boxd.it/c\n",
+			want: []Entry{
+				{Title: "Heat", Year: 1995, Status: enums.PlanToWatch, Rating: -1, Watched: date("2024-01-02")},
+			},
+		},
+		{
+			name:   "imdb skips series and episodes",
+			format: Auto,
+			data: "Const,Your Rating,Date Rated,Title,Title Type,Year\n" +
+				"tt0078748,9,2024-01-01,Alien,Movie,1979\n" +
+				"tt0903747,10,2024-01-01,Breaking Bad,TV Series,2008\n" +
+				"tt0959621,8,2024-01-01,Pilot,TV Episode,2008\n" +
+				"tt0113277,,,Heat,Feature Film,1995\n" +
+				"nm0000123,7,2024-01-01,Not a title,,\n",
+			want: []Entry{
+				{Title: "Alien", Year: 1979, ImdbId: "tt0078748", Status: enums.Completed, Rating: 0.9, Watched: date("2024-01-01")},
+				{Title: "Heat", Year: 1995, ImdbId: "tt0113277", Status: enums.PlanToWatch, Rating: -1},
+			},
+		},
+		{
+			name:   "imdb with bom",
+			format: IMDb,
+			data:   "\ufeffConst,Your Rating,Title,Title Type,Year\ntt0078748,6,Alien,movie,1979\n",
+			want: []Entry{
+				{Title: "Alien", Year: 1979, ImdbId: "tt0078748", Status: enums.Completed, Rating: 0.6},
+			},
+		},
+		{
+			name:   "trakt watchlist and shows",
+			format: Auto,
+			data: `[
+				{"listed_at": "2024-01-02T00:00:00Z", "movie": {"title": "Heat", "year": 1995, "ids": {"tmdb": 949, "imdb": "tt0113277"}}},
+				{"watched_at": "2024-01-03T00:00:00Z", "rating": 8, "movie": {"title": "Alien", "year": 1979, "ids": {"tmdb": 348}}},
+				{"listed_at": "2024-01-02T00:00:00Z", "show": {"title": "Breaking Bad"}}
+			]`,
+			want: []Entry{
+				{Title: "Heat", Year: 1995, TmdbId: 949, ImdbId: "tt0113277", Status: enums.PlanToWatch, Rating: -1, Watched: date("2024-01-02")},
+				{Title: "Alien", Year: 1979, TmdbId: 348, Status: enums.Completed, Rating: 0.8, Watched: date("2024-01-03")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.data), tt.format)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Parse() = %#v, want %#v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Parse()[%d] = %#v, want %#v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,483 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/components/button"
+	"github.com/zhengkyl/review-ssh/ui/components/vlist"
+	"github.com/zhengkyl/review-ssh/ui/util"
+)
+
+var (
+	pageStyle     = lipgloss.NewStyle().Margin(1, 2)
+	hintStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	accentStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25D94"))
+	errStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("197"))
+	addStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	updateStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	progressWidth = 20
+)
+
+// Films resolved per tea.Cmd, so progress updates while resolving
+const resolveChunk = 8
+
+type step int
+
+const (
+	stepInput step = iota
+	stepResolving
+	stepReview // Picking between ambiguous matches
+	stepPreview
+	stepApplying
+	stepDone
+)
+
+type pickMsg int
+
+const skipPick pickMsg = -1
+
+type Model struct {
+	props  common.Props
+	step   step
+	input  textarea.Model
+	format Format
+	err    string
+
+	entries     []Entry
+	resolutions []Resolution
+	existing    map[int]common.Review
+
+	ambiguous []int // Indices into resolutions still to review
+	picker    *vlist.Model
+
+	changes []Change
+	pending []Change
+	offset  int // Preview scroll
+
+	applied int
+	failed  []string
+
+	// Incremented to ignore results from a cancelled run
+	seq    int
+	cancel context.CancelFunc
+}
+
+func New(p common.Props) *Model {
+	input := textarea.New()
+	input.ShowLineNumbers = false
+	input.CharLimit = 0
+	input.MaxHeight = 0
+	input.Placeholder = "Paste a Letterboxd or IMDb csv, or a Trakt json export..."
+
+	m := &Model{
+		props: p,
+		input: input,
+	}
+	m.SetSize(p.Width, p.Height)
+	return m
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.props.Width = width
+	m.props.Height = height
+
+	// margins + title + hint lines
+	m.input.SetWidth(util.Max(width-4, 10))
+	m.input.SetHeight(util.Max(height-7, 3))
+
+	if m.picker != nil {
+		m.picker.SetSize(util.Max(width-4, 10), util.Max(height-6, 2))
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	m.stop()
+	m.step = stepInput
+	m.err = ""
+	m.input.Reset()
+	return m.input.Focus()
+}
+
+func (m *Model) stop() {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+	m.seq++
+}
+
+func (m *Model) start() tea.Cmd {
+	entries, err := Parse([]byte(m.input.Value()), m.format)
+	if err != nil {
+		m.err = err.Error()
+		return nil
+	}
+	if len(entries) == 0 {
+		m.err = "No films found in that export."
+		return nil
+	}
+
+	m.err = ""
+	m.input.Blur()
+	m.entries = entries
+	m.resolutions = make([]Resolution, 0, len(entries))
+	m.step = stepResolving
+
+	m.stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	seq := m.seq
+	g := m.props.Global
+
+	// Existing reviews decide whether each film is added or updated
+	return func() tea.Msg {
+		existing, err := common.FetchAllReviews(ctx, g, g.AuthState.User.Id)
+		return func() tea.Msg {
+			if seq != m.seq {
+				return nil
+			}
+			if err != nil {
+				m.step = stepInput
+				m.err = fmt.Sprintf("Could not load your reviews (%v)", err)
+				return m.input.Focus()
+			}
+			m.existing = existing
+			return m.resolveNext(ctx, seq)
+		}
+	}
+}
+
+func (m *Model) resolveNext(ctx context.Context, seq int) tea.Cmd {
+	done := len(m.resolutions)
+	if done == len(m.entries) {
+		m.finishResolving()
+		return nil
+	}
+
+	chunk := m.entries[done:util.Min(done+resolveChunk, len(m.entries))]
+	g := m.props.Global
+
+	return func() tea.Msg {
+		results := ResolveAll(ctx, g, chunk, nil)
+		return func() tea.Msg {
+			if seq != m.seq {
+				return nil
+			}
+			for _, res := range results {
+				if res.Resolved() {
+					g.FilmCache.Set(res.Film.Id, res.Film)
+				}
+			}
+			m.resolutions = append(m.resolutions, results...)
+			return m.resolveNext(ctx, seq)
+		}
+	}
+}
+
+func (m *Model) finishResolving() {
+	m.ambiguous = nil
+	for i, res := range m.resolutions {
+		if res.Ambiguous() {
+			m.ambiguous = append(m.ambiguous, i)
+		}
+	}
+
+	if len(m.ambiguous) > 0 {
+		m.step = stepReview
+		m.buildPicker()
+		return
+	}
+	m.preview()
+}
+
+func filmLabel(film common.Film) string {
+	if len(film.Release_date) >= 4 {
+		return fmt.Sprintf("%s (%s)", film.Title, film.Release_date[:4])
+	}
+	return film.Title
+}
+
+func (m *Model) buildPicker() {
+	res := m.resolutions[m.ambiguous[0]]
+
+	buttons := make([]common.Focusable, 0, len(res.Candidates)+1)
+	for i, film := range res.Candidates {
+		i := i
+		buttons = append(buttons, button.New(m.props, filmLabel(film), func() tea.Msg { return pickMsg(i) }))
+	}
+	buttons = append(buttons, button.New(m.props, "Skip", func() tea.Msg { return skipPick }))
+
+	p := m.props
+	p.Width = util.Max(m.props.Width-4, 10)
+	p.Height = util.Max(m.props.Height-6, 2)
+	m.picker = vlist.New(p, 1, buttons...)
+}
+
+func (m *Model) pick(msg pickMsg) {
+	i := m.ambiguous[0]
+	if msg != skipPick {
+		film := m.resolutions[i].Candidates[msg]
+		m.resolutions[i].Film = film
+		m.props.Global.FilmCache.Set(film.Id, film)
+	}
+	m.resolutions[i].Candidates = nil
+
+	m.ambiguous = m.ambiguous[1:]
+	if len(m.ambiguous) > 0 {
+		m.buildPicker()
+		return
+	}
+	m.picker = nil
+	m.preview()
+}
+
+func (m *Model) preview() {
+	m.changes = Plan(m.resolutions, m.existing)
+	m.pending = Pending(m.changes)
+	m.offset = 0
+	m.step = stepPreview
+}
+
+func (m *Model) apply() tea.Cmd {
+	m.applied = 0
+	m.failed = nil
+	m.step = stepApplying
+
+	m.stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	common.Audit(m.props.Global, "import.start", "changes", len(m.pending))
+	return m.applyNext(ctx, m.seq)
+}
+
+// One request at a time, like batch edits on the lists page
+func (m *Model) applyNext(ctx context.Context, seq int) tea.Cmd {
+	if m.applied == len(m.pending) {
+		m.step = stepDone
+		common.Audit(m.props.Global, "import.done", "saved", len(m.pending)-len(m.failed), "failed", len(m.failed))
+		return nil
+	}
+
+	change := m.pending[m.applied]
+	g := m.props.Global
+
+	return func() tea.Msg {
+		review, err := Apply(ctx, g, change)
+		return func() tea.Msg {
+			if seq != m.seq {
+				return nil
+			}
+			if err != nil {
+				m.failed = append(m.failed, fmt.Sprintf("%s (%v)", change.Title(), err))
+			} else {
+				g.ReviewMap[change.Film.Id] = review
+			}
+			m.applied++
+			return m.applyNext(ctx, seq)
+		}
+	}
+}
+
+func (m *Model) previewLines() []string {
+	lines := []string{}
+	for _, c := range m.pending {
+		if c.Action == Add {
+			lines = append(lines, addStyle.Render(c.String()))
+		} else {
+			lines = append(lines, updateStyle.Render(c.String()))
+		}
+	}
+	for _, c := range m.changes {
+		if c.Action == Unresolved {
+			lines = append(lines, errStyle.Render(c.String()))
+		}
+	}
+	return lines
+}
+
+func (m *Model) previewHeight() int {
+	// margins + title + summary + hint
+	return util.Max(m.props.Height-7, 1)
+}
+
+func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case pickMsg:
+		if m.step == stepReview {
+			m.pick(msg)
+		}
+		return m, nil
+	case *common.KeyEvent:
+		return m, m.updateKeys(msg)
+	}
+
+	if m.step == stepInput {
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m *Model) updateKeys(msg *common.KeyEvent) tea.Cmd {
+	km := m.props.Global.KeyMap
+
+	// Unhandled back goes to the previous page, except while there's work to cancel
+	if key.Matches(msg.KeyMsg, km.Back) {
+		switch m.step {
+		case stepResolving, stepReview, stepPreview:
+			msg.Handled = true
+			m.stop()
+			m.step = stepInput
+			return m.input.Focus()
+		case stepApplying:
+			msg.Handled = true
+			m.stop()
+			m.step = stepDone
+		}
+		return nil
+	}
+
+	switch m.step {
+	case stepInput:
+		switch {
+		case key.Matches(msg.KeyMsg, km.Submit):
+			msg.Handled = true
+			return m.start()
+		case key.Matches(msg.KeyMsg, km.Format):
+			msg.Handled = true
+			for i, f := range Formats {
+				if f == m.format {
+					m.format = Formats[(i+1)%len(Formats)]
+					break
+				}
+			}
+			return nil
+		case msg.KeyMsg.Type == tea.KeyCtrlC:
+			return nil
+		}
+
+		if !m.input.Focused() {
+			return nil
+		}
+		// Everything else is typing, including keys like q and s
+		msg.Handled = true
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg.KeyMsg)
+		return cmd
+
+	case stepReview:
+		_, cmd := m.picker.Update(msg)
+		return cmd
+
+	case stepPreview:
+		switch {
+		case key.Matches(msg.KeyMsg, km.Down):
+			msg.Handled = true
+			m.offset = util.Min(m.offset+1, util.Max(len(m.previewLines())-m.previewHeight(), 0))
+		case key.Matches(msg.KeyMsg, km.Up):
+			msg.Handled = true
+			m.offset = util.Max(m.offset-1, 0)
+		case key.Matches(msg.KeyMsg, km.Select):
+			msg.Handled = true
+			return m.apply()
+		}
+
+	case stepApplying:
+		if !key.Matches(msg.KeyMsg, km.Quit) {
+			// Ignore input until finished
+			msg.Handled = true
+		}
+	}
+	return nil
+}
+
+func progressBar(done, total int) string {
+	filled := 0
+	if total > 0 {
+		filled = done * progressWidth / total
+	}
+	return accentStyle.Render(strings.Repeat("█", filled)) + strings.Repeat("░", progressWidth-filled)
+}
+
+func (m *Model) View() string {
+	view := strings.Builder{}
+
+	switch m.step {
+	case stepInput:
+		view.WriteString(accentStyle.Render("Import watch history"))
+		view.WriteString(hintStyle.Render(fmt.Sprintf("  format: %s (ctrl+t)", m.format)))
+		view.WriteString("\n\n")
+		view.WriteString(m.input.View())
+		view.WriteString("\n")
+		if m.err != "" {
+			view.WriteString(errStyle.Render(m.err))
+		} else {
+			view.WriteString(hintStyle.Render("ctrl+s preview changes · esc back"))
+		}
+
+	case stepResolving:
+		view.WriteString(accentStyle.Render("Finding films"))
+		view.WriteString("\n\n")
+		if m.existing == nil {
+			view.WriteString("Loading your reviews...")
+		} else {
+			view.WriteString(fmt.Sprintf("%s %d/%d", progressBar(len(m.resolutions), len(m.entries)), len(m.resolutions), len(m.entries)))
+		}
+		view.WriteString("\n\n")
+		view.WriteString(hintStyle.Render("esc cancel"))
+
+	case stepReview:
+		res := m.resolutions[m.ambiguous[0]]
+		view.WriteString(accentStyle.Render(fmt.Sprintf("Which film is %q?", res.Entry.String())))
+		view.WriteString(hintStyle.Render(fmt.Sprintf("  %d left", len(m.ambiguous))))
+		view.WriteString("\n\n")
+		view.WriteString(m.picker.View())
+
+	case stepPreview:
+		view.WriteString(accentStyle.Render("Dry run"))
+		view.WriteString("\n")
+		view.WriteString(Summarize(m.changes).String())
+		view.WriteString("\n\n")
+
+		lines := m.previewLines()
+		end := util.Min(m.offset+m.previewHeight(), len(lines))
+		for _, line := range lines[m.offset:end] {
+			view.WriteString(line)
+			view.WriteString("\n")
+		}
+		if len(m.pending) == 0 {
+			view.WriteString(hintStyle.Render("Nothing to import. esc back"))
+		} else {
+			view.WriteString(hintStyle.Render(fmt.Sprintf("enter import %d changes · ↑↓ scroll · esc cancel", len(m.pending))))
+		}
+
+	case stepApplying, stepDone:
+		if m.step == stepApplying {
+			view.WriteString(accentStyle.Render("Importing"))
+		} else {
+			view.WriteString(accentStyle.Render("Import finished"))
+		}
+		view.WriteString("\n\n")
+		view.WriteString(fmt.Sprintf("%s %d/%d", progressBar(m.applied, len(m.pending)), m.applied, len(m.pending)))
+		view.WriteString("\n\n")
+
+		if m.step == stepDone {
+			view.WriteString(fmt.Sprintf("%d of %d reviews saved.", m.applied-len(m.failed), len(m.pending)))
+			if len(m.failed) > 0 {
+				view.WriteString(errStyle.Render(" Failed: " + strings.Join(m.failed, ", ")))
+			}
+			view.WriteString("\n")
+			view.WriteString(hintStyle.Render("esc back"))
+		}
+	}
+
+	return lipgloss.NewStyle().MaxWidth(m.props.Width).Render(pageStyle.Render(view.String()))
+}
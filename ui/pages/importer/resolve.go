@@ -0,0 +1,126 @@
+package importer
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/zhengkyl/review-ssh/ui/common"
+)
+
+const (
+	tmdbBase       = "https://api.themoviedb.org/3"
+	maxCandidates  = 5
+	resolveWorkers = 4
+)
+
+type Resolution struct {
+	Entry Entry
+	Film  common.Film // Zero if unresolved or ambiguous
+	// Set when title matching found several plausible films
+	Candidates []common.Film
+	Err        error
+}
+
+func (r Resolution) Resolved() bool {
+	return r.Film.Id != 0
+}
+
+func (r Resolution) Ambiguous() bool {
+	return !r.Resolved() && len(r.Candidates) > 0
+}
+
+// Resolve finds the TMDB film for entry, by id if the export has one, otherwise by title and year.
+func Resolve(ctx context.Context, g common.Global, entry Entry) Resolution {
+	res := Resolution{Entry: entry}
+	apiKey := "api_key=" + g.Config.TMDB_API_KEY
+
+	if entry.TmdbId != 0 {
+		res.Film, res.Err = common.Do[common.Film](ctx, g, "GET", common.FilmURL(g, entry.TmdbId, ""), nil)
+		if res.Err == nil {
+			return res
+		}
+	}
+
+	if entry.ImdbId != "" {
+		found, err := common.Do[common.FindResults](ctx, g, "GET", tmdbBase+"/find/"+url.PathEscape(entry.ImdbId)+"?external_source=imdb_id&"+apiKey, nil)
+		if err == nil && len(found.Movie_results) > 0 {
+			res.Film, res.Err = found.Movie_results[0], nil
+			return res
+		}
+		if err != nil {
+			res.Err = err
+		}
+	}
+
+	if entry.Title == "" {
+		return res
+	}
+
+	query := "query=" + url.QueryEscape(entry.Title)
+	if entry.Year != 0 {
+		query += "&primary_release_year=" + strconv.Itoa(entry.Year)
+	}
+	results, err := common.Do[common.Paged[common.Film]](ctx, g, "GET", tmdbBase+"/search/movie?"+query+"&"+apiKey, nil)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	res.Err = nil
+
+	films := results.Results
+	if len(films) == 0 && entry.Year != 0 {
+		// Release years differ between regions, so try once more without one
+		entry.Year = 0
+		retry := Resolve(ctx, g, entry)
+		retry.Entry = res.Entry
+		return retry
+	}
+
+	exact := []common.Film{}
+	for _, film := range films {
+		if strings.EqualFold(film.Title, entry.Title) {
+			exact = append(exact, film)
+		}
+	}
+
+	switch {
+	case len(exact) == 1:
+		res.Film = exact[0]
+	case len(films) == 1:
+		res.Film = films[0]
+	case len(exact) > 1:
+		res.Candidates = exact
+	default:
+		res.Candidates = films
+	}
+
+	if len(res.Candidates) > maxCandidates {
+		res.Candidates = res.Candidates[:maxCandidates]
+	}
+	return res
+}
+
+// ResolveAll resolves entries concurrently, keeping their order.
+// progress, if not nil, is called with the number done after each entry.
+func ResolveAll(ctx context.Context, g common.Global, entries []Entry, progress func(done int)) []Resolution {
+	results := make([]Resolution, len(entries))
+
+	var mtx sync.Mutex
+	done := 0
+
+	common.ForEach(ctx, len(entries), resolveWorkers, func(i int) {
+		results[i] = Resolve(ctx, g, entries[i])
+
+		mtx.Lock()
+		done++
+		if progress != nil {
+			progress(done)
+		}
+		mtx.Unlock()
+	})
+
+	return results
+}
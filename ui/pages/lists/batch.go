@@ -0,0 +1,271 @@
+package lists
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/common/enums"
+	"github.com/zhengkyl/review-ssh/ui/components/button"
+	"github.com/zhengkyl/review-ssh/ui/components/dialog"
+	"github.com/zhengkyl/review-ssh/ui/components/vlist"
+)
+
+var (
+	menuStyle     = lipgloss.NewStyle().Padding(1, 3).Border(lipgloss.RoundedBorder(), true)
+	progressStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25D94"))
+	failStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("197"))
+)
+
+const progressWidth = 20
+
+type batchAction struct {
+	name string
+	verb string // "Mark 3 films Completed?"
+	// Reviews that don't need changing are skipped
+	skip  func(review common.Review) bool
+	apply func(g common.Global, tmdbId int, callback func(err error) tea.Msg) tea.Cmd
+}
+
+// Reviews in targets the action would change
+func (a batchAction) pending(targets []common.Review) []common.Review {
+	reviews := []common.Review{}
+	for _, review := range targets {
+		if !a.skip(review) {
+			reviews = append(reviews, review)
+		}
+	}
+	return reviews
+}
+
+func patchAction(name, verb string, field string, value interface{}, skip func(common.Review) bool) batchAction {
+	return batchAction{
+		name: name,
+		verb: verb,
+		skip: skip,
+		apply: func(g common.Global, tmdbId int, callback func(err error) tea.Msg) tea.Cmd {
+			return common.PatchReviewCmd(g, tmdbId, map[string]interface{}{field: value}, callback)
+		},
+	}
+}
+
+var batchActions = []batchAction{
+	patchAction("Plan To Watch", "Move %d films to Plan To Watch?", "status", enums.PlanToWatch.String(),
+		func(r common.Review) bool { return r.Status == enums.PlanToWatch }),
+	patchAction("Completed", "Mark %d films Completed?", "status", enums.Completed.String(),
+		func(r common.Review) bool { return r.Status == enums.Completed }),
+	patchAction("Like", "Like %d films?", "fun_during", true,
+		func(r common.Review) bool { return r.Fun_during }),
+	patchAction("Unlike", "Unlike %d films?", "fun_during", false,
+		func(r common.Review) bool { return !r.Fun_during }),
+	patchAction("Star", "Star %d films?", "fun_after", true,
+		func(r common.Review) bool { return r.Fun_after }),
+	patchAction("Unstar", "Unstar %d films?", "fun_after", false,
+		func(r common.Review) bool { return !r.Fun_after }),
+	{
+		name:  "Remove",
+		verb:  "Remove %d films? This can't be undone.",
+		skip:  func(r common.Review) bool { return false },
+		apply: common.DeleteReviewCmd,
+	},
+}
+
+type batchChooseMsg int
+type batchConfirmMsg struct{}
+type batchCancelMsg struct{}
+
+type batchFailure struct {
+	tmdbId int
+	err    error
+}
+
+type batchJob struct {
+	action  batchAction
+	reviews []common.Review
+	done    int
+	failed  []batchFailure
+	running bool
+}
+
+type batchModel struct {
+	props   common.Props
+	menu    *vlist.Model
+	dialog  *dialog.Model
+	menuOn  bool
+	chosen  int
+	targets []common.Review
+	job     *batchJob
+}
+
+func newBatchModel(p common.Props) *batchModel {
+	m := &batchModel{
+		props:  p,
+		menu:   vlist.New(common.Props{Width: 24, Height: len(batchActions) * 2, Global: p.Global}, 1),
+		dialog: dialog.New(p, ""),
+	}
+
+	m.dialog.Buttons(
+		*button.New(p, "Yes", func() tea.Msg { return batchConfirmMsg{} }),
+		*button.New(p, "No", func() tea.Msg { return batchCancelMsg{} }),
+	)
+
+	return m
+}
+
+// Running or showing a result that hasn't been dismissed
+func (m *batchModel) Active() bool {
+	return m.menuOn || m.dialog.Focused() || (m.job != nil && m.job.running)
+}
+
+func (m *batchModel) Open(targets []common.Review) {
+	if len(targets) == 0 {
+		return
+	}
+	m.targets = targets
+	m.menuOn = true
+	m.job = nil
+
+	// Each action says how many films it would change, and is disabled if none
+	buttons := make([]common.Focusable, 0, len(batchActions))
+	for i, action := range batchActions {
+		i := i
+		count := len(action.pending(targets))
+		b := button.New(m.props, fmt.Sprintf("%s (%d)", action.name, count), func() tea.Msg { return batchChooseMsg(i) })
+		b.SetDisabled(count == 0)
+		buttons = append(buttons, b)
+	}
+	m.menu.SetItems(buttons)
+}
+
+func (m *batchModel) DismissResult() {
+	if m.job != nil && !m.job.running {
+		m.job = nil
+	}
+}
+
+func (m *batchModel) start(onDone func()) tea.Cmd {
+	action := batchActions[m.chosen]
+	reviews := action.pending(m.targets)
+	m.job = &batchJob{action: action, reviews: reviews, running: true}
+	common.Audit(m.props.Global, "bulk.start", "action", action.name, "tmdb_ids", tmdbIds(reviews))
+	return m.runNext(onDone)
+}
+
+func tmdbIds(reviews []common.Review) []int {
+	ids := make([]int, 0, len(reviews))
+	for _, review := range reviews {
+		ids = append(ids, review.Tmdb_id)
+	}
+	return ids
+}
+
+// One request at a time, to be gentle on review-api and give a useful progress bar
+func (m *batchModel) runNext(onDone func()) tea.Cmd {
+	job := m.job
+	if job.done == len(job.reviews) {
+		job.running = false
+		failed := make([]int, 0, len(job.failed))
+		for _, failure := range job.failed {
+			failed = append(failed, failure.tmdbId)
+		}
+		common.Audit(m.props.Global, "bulk.done", "action", job.action.name, "updated", len(job.reviews)-len(failed), "failed_tmdb_ids", failed)
+		onDone()
+		return nil
+	}
+
+	tmdbId := job.reviews[job.done].Tmdb_id
+	return job.action.apply(m.props.Global, tmdbId, func(err error) tea.Msg {
+		if err != nil {
+			job.failed = append(job.failed, batchFailure{tmdbId, err})
+		}
+		job.done++
+		return m.runNext(onDone)
+	})
+}
+
+func (m *batchModel) Update(msg tea.Msg, onDone func()) tea.Cmd {
+	switch msg := msg.(type) {
+	case batchChooseMsg:
+		m.menuOn = false
+		m.chosen = int(msg)
+		action := batchActions[m.chosen]
+		m.dialog.SetText(fmt.Sprintf(action.verb, len(action.pending(m.targets))))
+		m.dialog.Focus()
+		return nil
+	case batchConfirmMsg:
+		m.dialog.Blur()
+		return m.start(onDone)
+	case batchCancelMsg:
+		m.dialog.Blur()
+		return nil
+	case *common.KeyEvent:
+		if m.dialog.Focused() {
+			_, cmd := m.dialog.Update(msg)
+			msg.Handled = true
+			return cmd
+		}
+		if m.menuOn {
+			if msg.KeyMsg.Type == tea.KeyEsc {
+				m.menuOn = false
+			} else {
+				_, cmd := m.menu.Update(msg)
+				msg.Handled = true
+				return cmd
+			}
+			msg.Handled = true
+			return nil
+		}
+		if m.job != nil && m.job.running && !key.Matches(msg.KeyMsg, m.props.Global.KeyMap.Quit) {
+			// Ignore input until finished
+			msg.Handled = true
+		}
+	}
+	return nil
+}
+
+// Status line shown above the list while running and after finishing
+func (m *batchModel) StatusView() string {
+	if m.job == nil {
+		return ""
+	}
+	job := m.job
+	total := len(job.reviews)
+
+	if job.running {
+		filled := 0
+		if total > 0 {
+			filled = job.done * progressWidth / total
+		}
+		bar := progressStyle.Render(strings.Repeat("█", filled)) + strings.Repeat("░", progressWidth-filled)
+		return fmt.Sprintf(" %s %s %d/%d", job.action.name, bar, job.done, total)
+	}
+
+	status := fmt.Sprintf(" %s: %d of %d updated.", job.action.name, total-len(job.failed), total)
+	if len(job.failed) == 0 {
+		return status
+	}
+
+	failures := make([]string, 0, len(job.failed))
+	for _, failure := range job.failed {
+		title := fmt.Sprint(failure.tmdbId)
+		if ok, _, film := m.props.Global.FilmCache.Get(failure.tmdbId); ok {
+			title = film.Title
+		}
+		failures = append(failures, fmt.Sprintf("%s (%v)", title, failure.err))
+	}
+	return status + failStyle.Render(" Failed: "+strings.Join(failures, ", "))
+}
+
+// Menu or dialog to overlay on the page, if any
+func (m *batchModel) OverlayView() string {
+	if m.dialog.Focused() {
+		return m.dialog.View()
+	}
+	if m.menuOn {
+		return menuStyle.Render(fmt.Sprintf("Edit %d marked films\n\n", len(m.targets)) + m.menu.View())
+	}
+	return ""
+}
@@ -1,16 +1,20 @@
 package lists
 
 import (
-	"sort"
-	"strconv"
+	"context"
+	"errors"
+	"net/http"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/zhengkyl/review-ssh/ui/common"
 	"github.com/zhengkyl/review-ssh/ui/common/enums"
+	"github.com/zhengkyl/review-ssh/ui/components/tabs"
 	"github.com/zhengkyl/review-ssh/ui/pages/lists/reviewlist"
+	"github.com/zhengkyl/review-ssh/ui/util"
 )
 
 var tabNames = []string{
@@ -29,9 +33,9 @@ var tabStatuses = []enums.Status{
 var NUM_LISTS = len(tabNames)
 
 var (
-	tabBorder      = lipgloss.NormalBorder()
-	tabStyle       = lipgloss.NewStyle().Padding(0, 1).Border(tabBorder, true)
-	activeTabStyle = lipgloss.NewStyle().Padding(0, 1).BorderForeground(lipgloss.Color("#F25D94")).Border(tabBorder, true).Foreground((lipgloss.Color("#F25D94")))
+	controlStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8")).PaddingLeft(1)
+	toggleStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	activeToggle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25D94"))
 )
 
 type Model struct {
@@ -39,13 +43,28 @@ type Model struct {
 	activeTab int
 	list      *reviewlist.Model
 	err       string
+	sorts     []sortState // Per tab
+	filter    listFilter
+	find      textinput.Model
+	batch     *batchModel
+	// Waiting on film titles before sorting/filtering by them
+	waitingFilms bool
+	// Waiting on where to watch before filtering by it
+	waitingProviders bool
 }
 
 func New(p common.Props) *Model {
+	find := textinput.New()
+	find.Prompt = "/"
+	find.Placeholder = "title"
+
 	return &Model{
 		props:     p,
 		activeTab: 0,
 		list:      reviewlist.New(p),
+		sorts:     make([]sortState, NUM_LISTS),
+		find:      find,
+		batch:     newBatchModel(p),
 	}
 }
 
@@ -53,102 +72,318 @@ func (m *Model) SetSize(width, height int) {
 	m.props.Width = width
 	m.props.Height = height
 
-	m.list.SetSize(width, height-3)
+	// tabs + controls
+	m.list.SetSize(width, height-4)
+}
+
+// Sorts are kept in the user's settings, so they carry over to their next session
+func (m *Model) loadSorts() {
+	settings := m.props.Global.Settings.Get(m.props.Global.AuthState.User.Id)
+	for i, name := range tabNames {
+		m.sorts[i] = sortFromSetting(settings.ListSorts[name])
+	}
+}
+
+func (m *Model) saveSorts() {
+	g := m.props.Global
+	settings := g.Settings.Get(g.AuthState.User.Id)
+	settings.ListSorts = map[string]common.ListSort{}
+	for i, name := range tabNames {
+		settings.ListSorts[name] = m.sorts[i].setting()
+	}
+	g.Settings.Set(g.AuthState.User.Id, settings)
+}
+
+func (m *Model) needsFilms() bool {
+	return m.sorts[m.activeTab].needsFilms() || m.filter.pattern != ""
 }
 
 func (m *Model) ReloadReviews() {
 	reviews := make([]common.Review, 0)
 
-	if m.activeTab == 0 {
-		for _, review := range m.props.Global.ReviewMap {
-			reviews = append(reviews, review)
+	for _, review := range m.props.Global.ReviewMap {
+		if m.activeTab != 0 && tabStatuses[m.activeTab] != review.Status {
+			continue
 		}
-	} else {
-		for _, review := range m.props.Global.ReviewMap {
-			if tabStatuses[m.activeTab] == review.Status {
-				reviews = append(reviews, review)
-			}
+		if m.filter.keep(review) && (!m.filter.available || m.available(review)) {
+			reviews = append(reviews, review)
 		}
 	}
 
-	sort.Sort(common.ByStatusAndUpdate(reviews))
+	sortReviews(reviews, m.sorts[m.activeTab], m.props.Global.FilmCache)
+	reviews = fuzzyFilter(reviews, m.filter.pattern, m.props.Global.FilmCache)
+
 	m.list.SetReviews(reviews)
 }
 
-func (m *Model) Init() tea.Cmd {
-	user_id := m.props.Global.AuthState.User.Id
+// Fetches every film this tab needs to sort or filter by title
+func (m *Model) loadFilms() tea.Cmd {
+	if !m.needsFilms() {
+		m.waitingFilms = false
+		return nil
+	}
 
-	callback := func(data common.Paged[common.Review], err error) tea.Msg {
+	var cmds []tea.Cmd
+	waiting := false
+	for _, review := range m.props.Global.ReviewMap {
+		ok, loading, _ := m.props.Global.FilmCache.Get(review.Tmdb_id)
+		if ok {
+			continue
+		}
+		waiting = true
+		if !loading {
+			cmds = append(cmds, common.GetFilmCmd(m.props.Global, review.Tmdb_id))
+		}
+	}
 
-		if err == nil {
-			reviews := make([]common.Review, 0, len(data.Results))
-			for _, review := range data.Results {
-				reviews = append(reviews, review)
-				m.props.Global.ReviewMap[review.Tmdb_id] = review
-			}
+	if m.waitingFilms && !waiting {
+		m.ReloadReviews()
+	}
+	m.waitingFilms = waiting
 
-			sort.Sort(common.ByStatusAndUpdate(reviews))
-			m.list.SetReviews(reviews)
-		}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) available(review common.Review) bool {
+	if review.Status != enums.PlanToWatch {
+		return false
+	}
+	g := m.props.Global
+	_, _, providers := g.ProviderCache.Get(review.Tmdb_id)
+	return g.Settings.Get(g.AuthState.User.Id).Available(providers)
+}
+
+// Fetches where to watch every Plan To Watch film, for the available filter
+func (m *Model) loadProviders() tea.Cmd {
+	if !m.filter.available {
+		m.waitingProviders = false
 		return nil
 	}
-	cmds := []tea.Cmd{m.list.Init()}
 
-	if user_id == common.GuestAuthState.User.Id {
-		cmds = append(cmds,
-			common.Get[common.Paged[common.Review]](m.props.Global, reviewsEndpoint+"&user_id=1", callback))
-	} else {
-		cmds = append(cmds, common.Get[common.Paged[common.Review]](m.props.Global, reviewsEndpoint+"&user_id="+strconv.Itoa(user_id), callback))
+	var cmds []tea.Cmd
+	waiting := false
+	for _, review := range m.props.Global.ReviewMap {
+		if review.Status != enums.PlanToWatch {
+			continue
+		}
+		ok, loading, _ := m.props.Global.ProviderCache.Get(review.Tmdb_id)
+		if ok {
+			continue
+		}
+		waiting = true
+		if !loading {
+			cmds = append(cmds, common.GetWatchProvidersCmd(m.props.Global, review.Tmdb_id))
+		}
+	}
+
+	if m.waitingProviders && !waiting {
+		m.ReloadReviews()
 	}
+	m.waitingProviders = waiting
 
 	return tea.Batch(cmds...)
 }
 
+func (m *Model) Init() tea.Cmd {
+	g := m.props.Global
+	userId := g.AuthState.User.Id
+	m.loadSorts()
+
+	// Every page, since imports make hundreds of reviews normal and other pages read ReviewMap
+	load := func() tea.Msg {
+		reviews, err := common.FetchAllReviews(context.Background(), g, userId)
+		return func() tea.Msg {
+			for _, review := range reviews {
+				g.ReviewMap[review.Tmdb_id] = review
+			}
+			m.ReloadReviews()
+			if errors.Is(err, common.StatusError(http.StatusUnauthorized)) {
+				return tea.Cmd(func() tea.Msg { return common.Unauthorized{} })
+			}
+			return nil
+		}
+	}
+
+	return tea.Batch(m.list.Init(), load)
+}
+
+func (m *Model) updateFind(msg *common.KeyEvent) tea.Cmd {
+	msg.Handled = true
+
+	switch msg.KeyMsg.Type {
+	case tea.KeyEnter:
+		// Keep the filter, give keys back to the list
+		m.find.Blur()
+		return nil
+	case tea.KeyEsc:
+		m.find.Blur()
+		m.find.SetValue("")
+	default:
+		var cmd tea.Cmd
+		m.find, cmd = m.find.Update(msg.KeyMsg)
+		if m.find.Value() == m.filter.pattern {
+			return cmd
+		}
+		m.filter.pattern = m.find.Value()
+		m.ReloadReviews()
+		return tea.Batch(cmd, m.loadFilms())
+	}
+
+	m.filter.pattern = ""
+	m.ReloadReviews()
+	return nil
+}
+
+func (m *Model) onBatchDone() {
+	m.list.ClearMarks()
+	m.ReloadReviews()
+}
+
 func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg.(type) {
+	case batchChooseMsg, batchConfirmMsg, batchCancelMsg:
+		return m, m.batch.Update(msg, m.onBatchDone)
+	}
+
 	switch msg := msg.(type) {
 	case *common.KeyEvent:
+		if m.find.Focused() {
+			return m, m.updateFind(msg)
+		}
+
+		if m.batch.Active() {
+			cmd := m.batch.Update(msg, m.onBatchDone)
+			if msg.Handled {
+				return m, cmd
+			}
+		}
+
 		prevActive := m.activeTab
-		if key.Matches(msg.KeyMsg, m.props.Global.KeyMap.NextX) {
+		prevSort := m.sorts[m.activeTab]
+		prevFilter := m.filter
+		km := m.props.Global.KeyMap
+
+		switch {
+		case key.Matches(msg.KeyMsg, km.NextX):
 			msg.Handled = true
 			m.activeTab = (m.activeTab + 1) % NUM_LISTS
-		} else if key.Matches(msg.KeyMsg, m.props.Global.KeyMap.PrevX) {
+		case key.Matches(msg.KeyMsg, km.PrevX):
 			msg.Handled = true
 			m.activeTab = (m.activeTab - 1 + NUM_LISTS) % NUM_LISTS
+		case key.Matches(msg.KeyMsg, km.Sort):
+			msg.Handled = true
+			m.sorts[m.activeTab].key = sortKey((int(m.sorts[m.activeTab].key) + 1) % NUM_SORTS)
+			m.sorts[m.activeTab].reverse = false
+		case key.Matches(msg.KeyMsg, km.Reverse):
+			msg.Handled = true
+			m.sorts[m.activeTab].reverse = !m.sorts[m.activeTab].reverse
+		case key.Matches(msg.KeyMsg, km.Liked):
+			msg.Handled = true
+			m.filter.liked = !m.filter.liked
+		case key.Matches(msg.KeyMsg, km.Starred):
+			msg.Handled = true
+			m.filter.starred = !m.filter.starred
+		case key.Matches(msg.KeyMsg, km.HasText):
+			msg.Handled = true
+			m.filter.hasText = !m.filter.hasText
+		case key.Matches(msg.KeyMsg, km.Available):
+			msg.Handled = true
+			m.filter.available = !m.filter.available
+		case key.Matches(msg.KeyMsg, km.Find):
+			msg.Handled = true
+			return m, m.find.Focus()
+		case key.Matches(msg.KeyMsg, km.Batch):
+			msg.Handled = true
+			m.batch.Open(m.list.Marked())
+		case key.Matches(msg.KeyMsg, km.Back):
+			if m.filter.pattern != "" {
+				msg.Handled = true
+				m.find.SetValue("")
+				m.filter.pattern = ""
+			}
+			m.batch.DismissResult()
 		}
 
-		if m.activeTab != prevActive {
+		if m.activeTab == prevActive && m.sorts[m.activeTab] != prevSort {
+			m.saveSorts()
+		}
+		if m.activeTab != prevActive || m.sorts[m.activeTab] != prevSort || m.filter != prevFilter {
 			m.ReloadReviews()
+			cmds = append(cmds, m.loadFilms(), m.loadProviders())
+		}
+	default:
+		if m.waitingFilms {
+			cmds = append(cmds, m.loadFilms())
+		}
+		if m.waitingProviders {
+			cmds = append(cmds, m.loadProviders())
 		}
+		var cmd tea.Cmd
+		m.find, cmd = m.find.Update(msg)
+		cmds = append(cmds, cmd)
 	}
 
 	_, cmd := m.list.Update(msg)
-	return m, cmd
+	cmds = append(cmds, cmd)
+	return m, tea.Batch(cmds...)
 }
 
-func (m *Model) View() string {
-	view := strings.Builder{}
-	names := []string{}
+func (m *Model) availableLabel() string {
+	g := m.props.Global
+	if m.filter.available && len(g.Settings.Get(g.AuthState.User.Id).Services) == 0 {
+		return "(4) on my services, none set, press ,"
+	}
+	return "(4) on my services"
+}
 
-	for i, tabName := range tabNames {
-		var name string
-		if i == m.activeTab {
-			name = activeTabStyle.Render(tabName)
-		} else {
-			name = tabStyle.Render(tabName)
+func (m *Model) controlsView() string {
+	toggle := func(on bool, text string) string {
+		if on {
+			return activeToggle.Render(text)
 		}
-		names = append(names, name)
+		return toggleStyle.Render(text)
 	}
 
-	tabs := lipgloss.JoinHorizontal(lipgloss.Top,
-		names...,
-	)
+	controls := []string{
+		"(o)rder " + m.sorts[m.activeTab].String(),
+		toggle(m.filter.liked, "(1) liked"),
+		toggle(m.filter.starred, "(2) starred"),
+		toggle(m.filter.hasText, "(3) has text"),
+		toggle(m.filter.available, m.availableLabel()),
+	}
 
-	view.WriteString(tabs)
+	if m.find.Focused() || m.filter.pattern != "" {
+		controls = append(controls, m.find.View())
+	} else {
+		controls = append(controls, "(/) find")
+	}
+
+	return controlStyle.Render(strings.Join(controls, "  "))
+}
+
+func (m *Model) View() string {
+	view := strings.Builder{}
+	view.WriteString(tabs.View(tabNames, m.activeTab))
+	view.WriteString("\n")
+	if status := m.batch.StatusView(); status != "" {
+		view.WriteString(lipgloss.NewStyle().MaxWidth(m.props.Width).Render(status))
+	} else {
+		view.WriteString(m.controlsView())
+	}
 	view.WriteString("\n")
 
 	view.WriteString(m.list.View())
 
 	view.WriteString(m.err)
 
-	return view.String()
+	app := view.String()
+	if overlay := m.batch.OverlayView(); overlay != "" {
+		x := util.Max((m.props.Width-lipgloss.Width(overlay))/2, 0)
+		y := util.Max((m.props.Height-lipgloss.Height(overlay))/2, 0)
+		app = util.RenderOverlay(app, overlay, x, y)
+	}
+
+	return app
 }
@@ -39,6 +39,9 @@ type Model struct {
 	itemSpinner   spinner.Model
 	spinning      bool
 	loadedReviews bool
+
+	marked map[int]bool // By tmdb id, so marks survive re-sorting
+	anchor int          // Start of a range mark
 }
 
 var (
@@ -54,6 +57,7 @@ func New(p common.Props) *Model {
 		active:      0,
 		itemSpinner: spinner.New(spinner.WithSpinner(dotdotdot)),
 		spinning:    true,
+		marked:      map[int]bool{},
 	}
 	m.SetSize(p.Width, p.Height)
 
@@ -80,13 +84,63 @@ func (m *Model) SetSize(width, height int) {
 
 func (m *Model) SetReviews(reviews []common.Review) {
 	m.loadedReviews = true
-	// TODO quick dirty dirty check
-	if len(reviews) == len(m.reviews) {
-		return
+
+	// Reviews may be updated in place, only reset position if order changed
+	sameOrder := len(reviews) == len(m.reviews)
+	for i := 0; sameOrder && i < len(reviews); i++ {
+		sameOrder = reviews[i].Tmdb_id == m.reviews[i].Tmdb_id
 	}
+
 	m.reviews = reviews
-	m.active = 0
-	m.offset = 0
+	if !sameOrder {
+		m.active = 0
+		m.offset = 0
+		m.anchor = 0
+	}
+}
+
+// Marked returns marked reviews that are still in the list, in list order
+func (m *Model) Marked() []common.Review {
+	marked := []common.Review{}
+	for _, review := range m.reviews {
+		if m.marked[review.Tmdb_id] {
+			marked = append(marked, review)
+		}
+	}
+	return marked
+}
+
+func (m *Model) ClearMarks() {
+	m.marked = map[int]bool{}
+}
+
+func (m *Model) toggleMark() {
+	id := m.reviews[m.active].Tmdb_id
+	if m.marked[id] {
+		delete(m.marked, id)
+	} else {
+		m.marked[id] = true
+	}
+	m.anchor = m.active
+}
+
+// Marks everything between the last toggled item and the active one
+func (m *Model) markRange() {
+	start, end := util.Min(m.anchor, m.active), util.Max(m.anchor, m.active)
+	for i := start; i <= end && i < len(m.reviews); i++ {
+		m.marked[m.reviews[i].Tmdb_id] = true
+	}
+}
+
+func (m *Model) markAll() {
+	// Toggle off if everything's already marked
+	if len(m.Marked()) == len(m.reviews) {
+		m.ClearMarks()
+		return
+	}
+	for _, review := range m.reviews {
+		m.marked[review.Tmdb_id] = true
+	}
 }
 
 func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
@@ -117,11 +171,31 @@ func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
 					m.offset = m.active
 				}
 			case key.Matches(msg.KeyMsg, m.props.Global.KeyMap.Select):
+				if len(m.reviews) == 0 {
+					break
+				}
 				msg.Handled = true
 				cmd = func() tea.Msg {
 					return common.ShowFilm(m.reviews[m.active].Tmdb_id)
 				}
 				cmds = append(cmds, cmd)
+			case key.Matches(msg.KeyMsg, m.props.Global.KeyMap.Mark):
+				if len(m.reviews) == 0 {
+					break
+				}
+				msg.Handled = true
+				m.toggleMark()
+			case key.Matches(msg.KeyMsg, m.props.Global.KeyMap.MarkRange):
+				msg.Handled = true
+				m.markRange()
+			case key.Matches(msg.KeyMsg, m.props.Global.KeyMap.MarkAll):
+				msg.Handled = true
+				m.markAll()
+			case key.Matches(msg.KeyMsg, m.props.Global.KeyMap.Back):
+				if len(m.marked) > 0 {
+					msg.Handled = true
+					m.ClearMarks()
+				}
 			}
 		}
 	}
@@ -173,15 +247,18 @@ func (m *Model) View() string {
 		leftPad := (m.props.Width - lipgloss.Width(noMoviesArt)) / 2
 		style := lipgloss.NewStyle().PaddingTop(util.Max(topPad, 0)).PaddingLeft(util.Max(leftPad, 0))
 		viewSb.WriteString(style.Render(noMoviesArt))
+		viewSb.WriteString("\n")
+		viewSb.WriteString(lipgloss.PlaceHorizontal(m.props.Width, lipgloss.Center, "(i)mport from Letterboxd, IMDb or Trakt"))
 		return viewSb.String()
 	}
 
+	// 2 wide mark
 	// 5 wide review
 	// 13 status
 	// 5 gaps
 	// 3 wide scrollbar
 	hf := listStyle.GetHorizontalFrameSize()
-	titleWidth := m.props.Width - 5 - 13 - 5 - 3 - hf
+	titleWidth := m.props.Width - 2 - 5 - 13 - 5 - 3 - hf
 
 	for i := m.offset; i < m.offset+m.visibleItems && i < len(m.reviews); i++ {
 
@@ -199,6 +276,12 @@ func (m *Model) View() string {
 
 		sectionSb := strings.Builder{}
 
+		if m.marked[review.Tmdb_id] {
+			sectionSb.WriteString("● ")
+		} else {
+			sectionSb.WriteString("  ")
+		}
+
 		sectionSb.WriteString(util.TruncAndPadUnicode(title, titleWidth))
 		sectionSb.WriteString("  ")
 
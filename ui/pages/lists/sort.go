@@ -0,0 +1,178 @@
+package lists
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/sahilm/fuzzy"
+	"github.com/zhengkyl/review-ssh/ui/common"
+)
+
+type sortKey int
+
+const (
+	byStatus sortKey = iota
+	byTitle
+	byYear
+	byUpdated
+	byCreated
+	byRating
+)
+
+// This must match the order of sortKeys
+var sortNames = []string{
+	"status",
+	"title",
+	"year",
+	"updated",
+	"created",
+	"rating",
+}
+
+var NUM_SORTS = len(sortNames)
+
+type sortState struct {
+	key     sortKey
+	reverse bool
+}
+
+// Title sorts A-Z by default, everything else is newest/best first
+func (s sortState) ascending() bool {
+	return (s.key == byTitle) != s.reverse
+}
+
+func (s sortState) String() string {
+	if s.ascending() {
+		return sortNames[s.key] + " ↑"
+	}
+	return sortNames[s.key] + " ↓"
+}
+
+func sortFromSetting(setting common.ListSort) sortState {
+	for i, name := range sortNames {
+		if name == setting.By {
+			return sortState{sortKey(i), setting.Reverse}
+		}
+	}
+	return sortState{}
+}
+
+func (s sortState) setting() common.ListSort {
+	return common.ListSort{By: sortNames[s.key], Reverse: s.reverse}
+}
+
+type listFilter struct {
+	liked   bool
+	starred bool
+	hasText bool
+	// Plan To Watch on the user's services, needs ProviderCache
+	available bool
+	pattern   string
+}
+
+func (f listFilter) keep(review common.Review) bool {
+	return (!f.liked || review.Fun_during) &&
+		(!f.starred || review.Fun_after) &&
+		(!f.hasText || strings.TrimSpace(review.Text) != "")
+}
+
+// Only title and year need film data, everything else is on the review
+func (s sortState) needsFilms() bool {
+	return s.key == byTitle || s.key == byYear
+}
+
+func ratingScore(r common.Review) int {
+	// Same order as common.RenderRating()
+	score := 0
+	if r.Fun_during {
+		score += 1
+	}
+	if r.Fun_after {
+		score += 2
+	}
+	return score
+}
+
+func sortReviews(reviews []common.Review, s sortState, films common.Cache[common.Film]) {
+	// Reviews come from a map, so ties fall back to tmdb id to keep the same order every reload
+	sort.Slice(reviews, func(i, j int) bool { return reviews[i].Tmdb_id < reviews[j].Tmdb_id })
+
+	if s.key == byStatus {
+		if s.reverse {
+			sort.Stable(sort.Reverse(common.ByStatusAndUpdate(reviews)))
+		} else {
+			sort.Stable(common.ByStatusAndUpdate(reviews))
+		}
+		return
+	}
+
+	film := func(r common.Review) common.Film {
+		_, _, f := films.Get(r.Tmdb_id)
+		return f
+	}
+
+	// less is always ascending, direction is applied after
+	var less func(a, b common.Review) bool
+	switch s.key {
+	case byTitle:
+		less = func(a, b common.Review) bool {
+			return strings.ToLower(film(a).Title) < strings.ToLower(film(b).Title)
+		}
+	case byYear:
+		less = func(a, b common.Review) bool {
+			return film(a).Release_date < film(b).Release_date
+		}
+	case byUpdated:
+		less = func(a, b common.Review) bool {
+			return a.Updated_at.Before(b.Updated_at)
+		}
+	case byCreated:
+		less = func(a, b common.Review) bool {
+			return a.Created_at.Before(b.Created_at)
+		}
+	case byRating:
+		less = func(a, b common.Review) bool {
+			if ratingScore(a) == ratingScore(b) {
+				return a.Updated_at.Before(b.Updated_at)
+			}
+			return ratingScore(a) < ratingScore(b)
+		}
+	}
+
+	ascending := s.ascending()
+	sort.SliceStable(reviews, func(i, j int) bool {
+		if ascending {
+			return less(reviews[i], reviews[j])
+		}
+		return less(reviews[j], reviews[i])
+	})
+}
+
+type titleSource struct {
+	reviews []common.Review
+	films   common.Cache[common.Film]
+}
+
+func (t titleSource) String(i int) string {
+	_, _, film := t.films.Get(t.reviews[i].Tmdb_id)
+	return film.Title
+}
+
+func (t titleSource) Len() int {
+	return len(t.reviews)
+}
+
+// Best matches first
+func fuzzyFilter(reviews []common.Review, pattern string, films common.Cache[common.Film]) []common.Review {
+	if pattern == "" {
+		return reviews
+	}
+
+	matches := fuzzy.FindFrom(pattern, titleSource{reviews, films})
+
+	filtered := make([]common.Review, 0, len(matches))
+	for _, match := range matches {
+		filtered = append(filtered, reviews[match.Index])
+	}
+	return filtered
+}
@@ -0,0 +1,288 @@
+package person
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/common/enums"
+	"github.com/zhengkyl/review-ssh/ui/components/poster"
+	"github.com/zhengkyl/review-ssh/ui/util"
+)
+
+const (
+	posterWidth  = 20
+	posterHeight = 15
+	bioHeight    = 6
+)
+
+var (
+	viewStyle   = lipgloss.NewStyle().Margin(1)
+	nameStyle   = lipgloss.NewStyle().Bold(true)
+	mutedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	activeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25D94"))
+)
+
+// One row per film, roles merged
+type role struct {
+	film  common.Film
+	roles []string
+}
+
+type Model struct {
+	props       common.Props
+	personId    int
+	person      common.Person
+	loaded      bool
+	err         string
+	poster      *poster.Model
+	films       []role
+	loadedFilms bool
+	active      int
+	offset      int
+}
+
+func New(p common.Props) *Model {
+	m := &Model{
+		props: p,
+	}
+	m.SetSize(p.Width, p.Height)
+	return m
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.props.Width = width - viewStyle.GetHorizontalFrameSize()
+	m.props.Height = height - viewStyle.GetVerticalFrameSize()
+}
+
+func (m *Model) Init(personId int) tea.Cmd {
+	m.personId = personId
+	m.person = common.Person{}
+	m.loaded = false
+	m.loadedFilms = false
+	m.err = ""
+	m.films = nil
+	m.active = 0
+	m.offset = 0
+
+	getPerson := common.GetPersonCmd(m.props.Global, personId, func(data common.Person, err error) tea.Msg {
+		if personId != m.personId {
+			return nil
+		}
+		m.loaded = true
+		if err != nil {
+			m.err = "Could not load person."
+			return nil
+		}
+		m.person = data
+		m.poster = poster.New(common.Props{Width: posterWidth, Height: posterHeight, Global: m.props.Global}, "https://image.tmdb.org/t/p/w200"+data.Profile_path)
+		return m.poster.Init()
+	})
+
+	getCredits := common.GetPersonCreditsCmd(m.props.Global, personId, func(data common.PersonCredits, err error) tea.Msg {
+		if personId != m.personId {
+			return nil
+		}
+		m.loadedFilms = true
+		if err == nil {
+			m.setFilms(data)
+		}
+		return nil
+	})
+
+	return tea.Batch(getPerson, getCredits)
+}
+
+func (m *Model) setFilms(data common.PersonCredits) {
+	index := map[int]int{}
+	add := func(credit common.FilmCredit, r string) {
+		if i, ok := index[credit.Id]; ok {
+			if r != "" {
+				m.films[i].roles = append(m.films[i].roles, r)
+			}
+			return
+		}
+		index[credit.Id] = len(m.films)
+		roles := []string{}
+		if r != "" {
+			roles = append(roles, r)
+		}
+		m.films = append(m.films, role{credit.Film, roles})
+	}
+
+	for _, credit := range data.Cast {
+		r := ""
+		if credit.Character != "" {
+			r = "as " + credit.Character
+		}
+		add(credit, r)
+	}
+	for _, credit := range data.Crew {
+		add(credit, credit.Job)
+	}
+
+	// Newest first, unreleased at the top
+	sort.SliceStable(m.films, func(i, j int) bool {
+		a, b := m.films[i].film.Release_date, m.films[j].film.Release_date
+		if a == "" || b == "" {
+			return a == "" && b != ""
+		}
+		return a > b
+	})
+
+	for _, r := range m.films {
+		if ok, _, _ := m.props.Global.FilmCache.Get(r.film.Id); !ok {
+			m.props.Global.FilmCache.Set(r.film.Id, r.film)
+		}
+	}
+}
+
+// Filmography rows that fit below the header and above the hint
+func (m *Model) listHeight() int {
+	return util.Max(m.props.Height-posterHeight-3, 3)
+}
+
+func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case *common.KeyEvent:
+		if len(m.films) == 0 {
+			return m, nil
+		}
+		km := m.props.Global.KeyMap
+
+		switch {
+		case key.Matches(msg.KeyMsg, km.Down):
+			msg.Handled = true
+			m.active = util.Min(m.active+1, len(m.films)-1)
+		case key.Matches(msg.KeyMsg, km.Up):
+			msg.Handled = true
+			m.active = util.Max(m.active-1, 0)
+		case key.Matches(msg.KeyMsg, km.Select):
+			msg.Handled = true
+			filmId := m.films[m.active].film.Id
+			return m, func() tea.Msg { return common.ShowFilm(filmId) }
+		case key.Matches(msg.KeyMsg, km.Add):
+			msg.Handled = true
+			filmId := m.films[m.active].film.Id
+			if _, ok := m.props.Global.ReviewMap[filmId]; ok {
+				return m, nil
+			}
+			return m, common.PostReviewCmd(m.props.Global, filmId, enums.PlanToWatch.String(), nil)
+		}
+
+		if m.active < m.offset {
+			m.offset = m.active
+		} else if m.active >= m.offset+m.listHeight() {
+			m.offset = m.active - m.listHeight() + 1
+		}
+		return m, nil
+	}
+
+	if m.poster != nil {
+		_, cmd := m.poster.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m *Model) headerView() string {
+	p := m.person
+	rightWidth := util.Max(m.props.Width-posterWidth-2, 10)
+
+	sb := strings.Builder{}
+	sb.WriteString(nameStyle.Render(p.Name))
+	sb.WriteString("\n")
+
+	facts := []string{}
+	if p.Known_for_department != "" {
+		facts = append(facts, p.Known_for_department)
+	}
+	if p.Birthday != "" {
+		born := "Born " + p.Birthday
+		if p.Place_of_birth != "" {
+			born += " in " + p.Place_of_birth
+		}
+		facts = append(facts, born)
+	}
+	if p.Deathday != "" {
+		facts = append(facts, "Died "+p.Deathday)
+	}
+	sb.WriteString(mutedStyle.Render(strings.Join(facts, " · ")))
+	sb.WriteString("\n\n")
+
+	bio := p.Biography
+	if bio == "" {
+		bio = "No biography."
+	}
+	bioLines := strings.Split(lipgloss.NewStyle().Width(rightWidth).Render(bio), "\n")
+	maxLines := util.Max(posterHeight-lipgloss.Height(sb.String()), bioHeight)
+	if len(bioLines) > maxLines {
+		bioLines = bioLines[:maxLines]
+		bioLines[maxLines-1] = util.TruncAndPadUnicode(strings.TrimRight(bioLines[maxLines-1], " ")+" …", rightWidth)
+	}
+	sb.WriteString(strings.Join(bioLines, "\n"))
+
+	right := lipgloss.NewStyle().Width(rightWidth).MaxHeight(posterHeight).Render(sb.String())
+	return lipgloss.JoinHorizontal(lipgloss.Top, m.poster.View(), "  ", right)
+}
+
+func (m *Model) filmsView() string {
+	if !m.loadedFilms {
+		return mutedStyle.Render("Loading filmography...")
+	}
+	if len(m.films) == 0 {
+		return mutedStyle.Render("No films")
+	}
+
+	statusWidth := 14
+	titleWidth := util.Max(m.props.Width/2, 10)
+	roleWidth := util.Max(m.props.Width-4-1-titleWidth-1-statusWidth-1, 2)
+
+	lines := []string{}
+	end := util.Min(m.offset+m.listHeight(), len(m.films))
+	for i := m.offset; i < end; i++ {
+		r := m.films[i]
+
+		year := "    "
+		if len(r.film.Release_date) >= 4 {
+			year = r.film.Release_date[:4]
+		}
+
+		status := ""
+		if review, ok := m.props.Global.ReviewMap[r.film.Id]; ok {
+			status = review.Status.DisplayString()
+		}
+
+		title := util.TruncAndPadUnicode(r.film.Title, titleWidth)
+		if i == m.active {
+			title = activeStyle.Render(title)
+		}
+
+		line := mutedStyle.Render(year) + " " + title + " " +
+			mutedStyle.Render(util.TruncAndPadUnicode(strings.Join(r.roles, ", "), roleWidth)) + " " +
+			util.TruncAndPadUnicode(status, statusWidth)
+		lines = append(lines, line)
+	}
+
+	hint := "enter details"
+	if _, ok := m.props.Global.ReviewMap[m.films[m.active].film.Id]; !ok {
+		hint += " · a plan to watch"
+	}
+	lines = append(lines, mutedStyle.Render(hint))
+
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) View() string {
+	if !m.loaded {
+		return viewStyle.Render("loading...")
+	}
+	if m.err != "" {
+		return viewStyle.Render(m.err)
+	}
+
+	return viewStyle.Render(m.headerView() + "\n\n" + m.filmsView())
+}
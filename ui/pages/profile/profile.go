@@ -0,0 +1,298 @@
+package profile
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/common/enums"
+	"github.com/zhengkyl/review-ssh/ui/components/tabs"
+	"github.com/zhengkyl/review-ssh/ui/util"
+)
+
+type tab int
+
+const (
+	all tab = iota
+	planToWatch
+	completed
+)
+
+// This must match the order of tabs
+var tabNames = []string{
+	"All",
+	"Plan To Watch",
+	"Completed",
+}
+
+var NUM_TABS = len(tabNames)
+
+var (
+	pageStyle   = lipgloss.NewStyle().Margin(0, 1)
+	nameStyle   = lipgloss.NewStyle().Bold(true)
+	mutedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	activeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25D94"))
+)
+
+// Someone else's public lists, read only
+type Model struct {
+	props     common.Props
+	userId    int
+	reviews   []common.Review
+	loading   bool
+	err       string
+	activeTab tab
+	active    int
+	offset    int
+	seq       int
+	cancel    context.CancelFunc
+}
+
+func New(p common.Props) *Model {
+	m := &Model{
+		props: p,
+	}
+	m.SetSize(p.Width, p.Height)
+	return m
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.props.Width = width - pageStyle.GetHorizontalFrameSize()
+	m.props.Height = height - pageStyle.GetVerticalFrameSize()
+}
+
+func (m *Model) Init(userId int) tea.Cmd {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+	m.seq++
+	m.userId = userId
+	m.reviews = nil
+	m.err = ""
+	m.activeTab = all
+	m.active = 0
+	m.offset = 0
+	m.loading = true
+
+	g := m.props.Global
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	seq := m.seq
+
+	cmds := []tea.Cmd{func() tea.Msg {
+		reviews, err := common.FetchAllReviews(ctx, g, userId)
+		return func() tea.Msg {
+			if seq != m.seq {
+				return nil
+			}
+			m.loading = false
+			if err != nil {
+				m.err = fmt.Sprintf("Could not load lists (%v)", err)
+				return nil
+			}
+			for _, review := range reviews {
+				m.reviews = append(m.reviews, review)
+			}
+			sort.Sort(common.ByStatusAndUpdate(m.reviews))
+			return m.loadFilms()
+		}
+	}}
+	cmds = append(cmds, common.GetUserCmd(g, userId))
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) shown() []common.Review {
+	switch m.activeTab {
+	case planToWatch:
+		return m.filter(enums.PlanToWatch)
+	case completed:
+		return m.filter(enums.Completed)
+	}
+	return m.reviews
+}
+
+func (m *Model) filter(status enums.Status) []common.Review {
+	filtered := []common.Review{}
+	for _, review := range m.reviews {
+		if review.Status == status {
+			filtered = append(filtered, review)
+		}
+	}
+	return filtered
+}
+
+// Rows that fit below the header and tabs, and above the hint
+func (m *Model) listHeight() int {
+	return util.Max(m.props.Height-6, 1)
+}
+
+// Only titles on screen are fetched
+func (m *Model) loadFilms() tea.Cmd {
+	var cmds []tea.Cmd
+	shown := m.shown()
+	end := util.Min(m.offset+m.listHeight(), len(shown))
+	for _, review := range shown[m.offset:end] {
+		cmds = append(cmds, common.GetFilmCmd(m.props.Global, review.Tmdb_id))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) self() bool {
+	return m.userId == m.props.Global.AuthState.User.Id
+}
+
+func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case *common.KeyEvent:
+		km := m.props.Global.KeyMap
+
+		switch {
+		case key.Matches(msg.KeyMsg, km.NextX):
+			msg.Handled = true
+			m.activeTab = tab((int(m.activeTab) + 1) % NUM_TABS)
+			m.active = 0
+			m.offset = 0
+			return m, m.loadFilms()
+		case key.Matches(msg.KeyMsg, km.PrevX):
+			msg.Handled = true
+			m.activeTab = tab((int(m.activeTab) - 1 + NUM_TABS) % NUM_TABS)
+			m.active = 0
+			m.offset = 0
+			return m, m.loadFilms()
+		case key.Matches(msg.KeyMsg, km.Follow):
+			msg.Handled = true
+			if m.self() {
+				return m, nil
+			}
+			g := m.props.Global
+			settings := g.Settings.Get(g.AuthState.User.Id)
+			settings.ToggleFollow(m.userId)
+			g.Settings.Set(g.AuthState.User.Id, settings)
+			return m, nil
+		}
+
+		shown := m.shown()
+		if len(shown) == 0 {
+			return m, nil
+		}
+
+		switch {
+		case key.Matches(msg.KeyMsg, km.Down):
+			msg.Handled = true
+			m.active = util.Min(m.active+1, len(shown)-1)
+		case key.Matches(msg.KeyMsg, km.Up):
+			msg.Handled = true
+			m.active = util.Max(m.active-1, 0)
+		case key.Matches(msg.KeyMsg, km.Select):
+			msg.Handled = true
+			filmId := shown[m.active].Tmdb_id
+			return m, func() tea.Msg { return common.ShowFilm(filmId) }
+		}
+
+		if m.active < m.offset {
+			m.offset = m.active
+		} else if m.active >= m.offset+m.listHeight() {
+			m.offset = m.active - m.listHeight() + 1
+		}
+		return m, m.loadFilms()
+	}
+
+	return m, nil
+}
+
+func (m *Model) headerView() string {
+	g := m.props.Global
+	name := fmt.Sprintf("User #%d", m.userId)
+	if ok, _, user := g.UserCache.Get(m.userId); ok {
+		name = user.Name
+	}
+
+	facts := []string{}
+	if !m.loading && m.err == "" {
+		facts = append(facts, fmt.Sprintf("%d films", len(m.reviews)))
+	}
+	switch {
+	case m.self():
+		facts = append(facts, "you")
+	case g.Settings.Get(g.AuthState.User.Id).Follows(m.userId):
+		facts = append(facts, activeStyle.Render("✓ following"))
+	}
+	return nameStyle.Render(name) + "  " + mutedStyle.Render(strings.Join(facts, " · "))
+}
+
+func (m *Model) listView() string {
+	switch {
+	case m.loading:
+		return mutedStyle.Render("Loading lists...")
+	case m.err != "":
+		return m.err
+	}
+
+	shown := m.shown()
+	if len(shown) == 0 {
+		return mutedStyle.Render("Nothing here")
+	}
+
+	statusWidth := 14
+	ratingWidth := 5
+	titleWidth := util.Max(m.props.Width-4-1-statusWidth-1-ratingWidth-1, 10)
+
+	lines := []string{}
+	end := util.Min(m.offset+m.listHeight(), len(shown))
+	for i := m.offset; i < end; i++ {
+		review := shown[i]
+
+		title := "Loading..."
+		year := "    "
+		if ok, _, film := m.props.Global.FilmCache.Get(review.Tmdb_id); ok {
+			title = film.Title
+			if len(film.Release_date) >= 4 {
+				year = film.Release_date[:4]
+			}
+		}
+		title = util.TruncAndPadUnicode(title, titleWidth)
+		if i == m.active {
+			title = activeStyle.Render(title)
+		}
+
+		rating := ""
+		if review.Status == enums.Completed {
+			rating = common.RenderRating(review.Fun_before, review.Fun_during, review.Fun_after)
+		}
+
+		lines = append(lines, mutedStyle.Render(year)+" "+title+" "+
+			util.TruncAndPadUnicode(review.Status.DisplayString(), statusWidth)+" "+rating)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) View() string {
+	sb := strings.Builder{}
+	sb.WriteString(m.headerView())
+	sb.WriteString("\n")
+
+	sb.WriteString(tabs.View(tabNames, int(m.activeTab)))
+	sb.WriteString("\n")
+	sb.WriteString(m.listView())
+
+	sb.WriteString(strings.Repeat("\n", util.Max(m.props.Height-1-lipgloss.Height(sb.String()), 1)))
+
+	hint := "←→ tabs · enter details"
+	if !m.self() {
+		g := m.props.Global
+		if g.Settings.Get(g.AuthState.User.Id).Follows(m.userId) {
+			hint += " · f unfollow"
+		} else {
+			hint += " · f follow"
+		}
+	}
+	sb.WriteString(mutedStyle.Render(hint))
+
+	return pageStyle.Render(sb.String())
+}
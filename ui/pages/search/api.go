@@ -0,0 +1,119 @@
+package search
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"golang.org/x/exp/slices"
+)
+
+const searchEndpoint = common.ReviewBase + "/search/Film"
+
+const (
+	tmdbSearchEndpoint   = "https://api.themoviedb.org/3/search/movie"
+	tmdbDiscoverEndpoint = "https://api.themoviedb.org/3/discover/movie"
+)
+
+var discoverSorts = map[string]string{
+	SortRelevance:  "popularity.desc",
+	SortPopularity: "popularity.desc",
+	SortRating:     "vote_average.desc",
+	SortDate:       "primary_release_date.desc",
+}
+
+func searchURL(g common.Global, query string, f Filters) string {
+	// Plain text searches go through review-api like before
+	if f == (Filters{}) {
+		return searchEndpoint + "?query=" + url.QueryEscape(query)
+	}
+
+	params := url.Values{}
+	params.Set("api_key", g.Config.TMDB_API_KEY)
+	params.Set("include_adult", strconv.FormatBool(f.Adult))
+
+	// TMDB search only takes a query and a single year, the rest is filtered client side.
+	if query != "" {
+		params.Set("query", query)
+		if f.YearMin != 0 && f.YearMin == f.YearMax {
+			params.Set("primary_release_year", strconv.Itoa(f.YearMin))
+		}
+		return tmdbSearchEndpoint + "?" + params.Encode()
+	}
+
+	params.Set("sort_by", discoverSorts[f.Sort])
+	if f.Sort == SortRating {
+		// Otherwise the top is all films with a single 10/10 vote
+		params.Set("vote_count.gte", "100")
+	}
+	if f.YearMin != 0 {
+		params.Set("primary_release_date.gte", strconv.Itoa(f.YearMin)+"-01-01")
+	}
+	if f.YearMax != 0 {
+		params.Set("primary_release_date.lte", strconv.Itoa(f.YearMax)+"-12-31")
+	}
+	if f.Genre != 0 {
+		params.Set("with_genres", strconv.Itoa(f.Genre))
+	}
+	if f.Language != "" {
+		params.Set("with_original_language", f.Language)
+	}
+
+	return tmdbDiscoverEndpoint + "?" + params.Encode()
+}
+
+func filterFilms(films []common.Film, f Filters) []common.Film {
+	filtered := make([]common.Film, 0, len(films))
+
+	for _, film := range films {
+		if f.YearMin != 0 || f.YearMax != 0 {
+			if len(film.Release_date) < 4 {
+				continue
+			}
+			year, err := strconv.Atoi(film.Release_date[:4])
+			if err != nil ||
+				(f.YearMin != 0 && year < f.YearMin) ||
+				(f.YearMax != 0 && year > f.YearMax) {
+				continue
+			}
+		}
+
+		if f.Genre != 0 && !slices.Contains(film.Genre_ids, f.Genre) {
+			continue
+		}
+
+		if f.Language != "" && film.Original_language != f.Language {
+			continue
+		}
+
+		if !f.Adult && film.Adult {
+			continue
+		}
+
+		filtered = append(filtered, film)
+	}
+
+	switch f.Sort {
+	case SortPopularity:
+		sort.SliceStable(filtered, func(i, j int) bool { return filtered[i].Popularity > filtered[j].Popularity })
+	case SortRating:
+		sort.SliceStable(filtered, func(i, j int) bool { return filtered[i].Vote_average > filtered[j].Vote_average })
+	case SortDate:
+		// YYYY-MM-DD sorts correctly as a string
+		sort.SliceStable(filtered, func(i, j int) bool { return filtered[i].Release_date > filtered[j].Release_date })
+	}
+
+	return filtered
+}
+
+func searchCmd(ctx context.Context, g common.Global, query string, f Filters, callback func(films []common.Film) tea.Msg) tea.Cmd {
+	return common.FetchWithContext[common.Paged[common.Film]](ctx, g, "GET", searchURL(g, query, f), nil, func(data common.Paged[common.Film], err error) tea.Msg {
+		if err != nil {
+			return nil
+		}
+		return callback(filterFilms(data.Results, f))
+	})
+}
@@ -19,6 +19,7 @@ var (
 	titleStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#fff"))
 	subtitleStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
 	contentStyle  = lipgloss.NewStyle().MarginLeft(2)
+	badgeStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25D94"))
 )
 
 // NOTE: Fullwidth spaces are 2 wide
@@ -97,6 +98,18 @@ func (m *Model) View() string {
 
 	str := lipgloss.JoinHorizontal(lipgloss.Top, titleStyle.Render(m.film.Title), " ", subtitleStyle.Render(releaseYear))
 
+	// Already in one of the user's lists
+	if review, ok := m.props.Global.ReviewMap[m.film.Id]; ok {
+		badge := review.Status.DisplayString()
+		if review.Fun_during {
+			badge += " · liked"
+		}
+		if review.Fun_after {
+			badge += " · starred"
+		}
+		str = lipgloss.JoinHorizontal(lipgloss.Top, str, "  ", badgeStyle.Render("✓ "+badge))
+	}
+
 	str = lipgloss.JoinVertical(lipgloss.Left, str, textStyle.Width(contentWidth).Render(desc))
 
 	str += "\n\n"
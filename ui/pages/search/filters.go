@@ -0,0 +1,239 @@
+package search
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/components/checkbox"
+	"github.com/zhengkyl/review-ssh/ui/components/dropdown"
+	"github.com/zhengkyl/review-ssh/ui/components/textfield"
+	"github.com/zhengkyl/review-ssh/ui/util"
+)
+
+const (
+	yearWidth     = 10
+	genreWidth    = 22
+	languageWidth = 14
+	sortWidth     = 16
+	panelHeight   = 3
+)
+
+var languageOptions = []dropdown.Option{
+	{Text: "Any language", Value: ""},
+	{Text: "English", Value: "en"},
+	{Text: "French", Value: "fr"},
+	{Text: "Spanish", Value: "es"},
+	{Text: "German", Value: "de"},
+	{Text: "Italian", Value: "it"},
+	{Text: "Japanese", Value: "ja"},
+	{Text: "Korean", Value: "ko"},
+	{Text: "Chinese", Value: "zh"},
+	{Text: "Hindi", Value: "hi"},
+	{Text: "Russian", Value: "ru"},
+	{Text: "Portuguese", Value: "pt"},
+	{Text: "Swedish", Value: "sv"},
+}
+
+var sortOptions = []dropdown.Option{
+	{Text: "Relevance", Value: SortRelevance},
+	{Text: "Popularity", Value: SortPopularity},
+	{Text: "Rating", Value: SortRating},
+	{Text: "Release date", Value: SortDate},
+}
+
+type filterPanel struct {
+	props      common.Props
+	yearMin    *textfield.Model
+	yearMax    *textfield.Model
+	genre      *dropdown.Model
+	language   *dropdown.Model
+	sort       *dropdown.Model
+	adult      *checkbox.Model
+	inputs     []common.Focusable
+	focusIndex int
+	focused    bool
+	// Called with the panel's filters whenever an input is committed
+	OnChange func(f Filters) tea.Cmd
+}
+
+func newFilterPanel(p common.Props) *filterPanel {
+	m := &filterPanel{
+		props:    p,
+		yearMin:  textfield.New(common.Props{Width: yearWidth, Height: 3, Global: p.Global}),
+		yearMax:  textfield.New(common.Props{Width: yearWidth, Height: 3, Global: p.Global}),
+		genre:    dropdown.New(common.Props{Width: genreWidth, Height: 3, Global: p.Global}, "Any genre", nil),
+		language: dropdown.New(common.Props{Width: languageWidth, Height: 3, Global: p.Global}, "Any language", languageOptions),
+		sort:     dropdown.New(common.Props{Width: sortWidth, Height: 3, Global: p.Global}, "Relevance", sortOptions),
+		adult:    checkbox.New(p),
+		OnChange: func(f Filters) tea.Cmd { return nil },
+	}
+
+	m.yearMin.CharLimit(4)
+	m.yearMin.Placeholder("from")
+	m.yearMax.CharLimit(4)
+	m.yearMax.Placeholder("to")
+	m.adult.Label = "NSFW"
+
+	commit := func(string) tea.Cmd { return m.OnChange(m.Filters()) }
+	m.genre.OnChange = commit
+	m.language.OnChange = commit
+	m.sort.OnChange = commit
+	m.adult.OnChange = func(bool) tea.Cmd { return m.OnChange(m.Filters()) }
+
+	m.inputs = []common.Focusable{m.yearMin, m.yearMax, m.genre, m.language, m.sort, m.adult}
+
+	return m
+}
+
+func (m *filterPanel) SetGenres(genres map[int]string) {
+	ids := make([]int, 0, len(genres))
+	for id := range genres {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return genres[ids[i]] < genres[ids[j]] })
+
+	options := []dropdown.Option{{Text: "Any genre", Value: ""}}
+	for _, id := range ids {
+		options = append(options, dropdown.Option{Text: genres[id], Value: strconv.Itoa(id)})
+	}
+	m.genre.SetItems(options)
+}
+
+func (m *filterPanel) Focused() bool {
+	return m.focused
+}
+
+func (m *filterPanel) Focus() {
+	m.focused = true
+	m.inputs[m.focusIndex].Focus()
+}
+
+func (m *filterPanel) Blur() {
+	m.focused = false
+	m.inputs[m.focusIndex].Blur()
+}
+
+func (m *filterPanel) Filters() Filters {
+	f := Filters{
+		Adult: m.adult.Checked,
+	}
+	f.YearMin, _ = strconv.Atoi(strings.TrimSpace(m.yearMin.Value()))
+	f.YearMax, _ = strconv.Atoi(strings.TrimSpace(m.yearMax.Value()))
+	f.Genre, _ = strconv.Atoi(selectedValue(m.genre))
+	f.Language = selectedValue(m.language)
+	f.Sort = selectedValue(m.sort)
+	return f
+}
+
+// Sync inputs with filters parsed from query syntax
+func (m *filterPanel) SetFilters(f Filters) {
+	m.yearMin.SetValue(intString(f.YearMin))
+	m.yearMax.SetValue(intString(f.YearMax))
+	selectValue(m.genre, intString(f.Genre))
+	selectValue(m.language, f.Language)
+	selectValue(m.sort, f.Sort)
+	m.adult.Checked = f.Adult
+}
+
+func intString(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+func selectedValue(d *dropdown.Model) string {
+	if d.Selected == -1 {
+		return ""
+	}
+	return d.Options()[d.Selected].Value
+}
+
+func selectValue(d *dropdown.Model, value string) {
+	d.Selected = -1
+	for i, option := range d.Options() {
+		if option.Value == value && value != "" {
+			d.Selected = i
+			return
+		}
+	}
+}
+
+func (m *filterPanel) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case *common.KeyEvent:
+		_, isTextfield := m.inputs[m.focusIndex].(*textfield.Model)
+		// Textfields blur themselves on back, but back should close the whole panel
+		if !isTextfield || !key.Matches(msg.KeyMsg, m.props.Global.KeyMap.Back) {
+			_, cmd := m.inputs[m.focusIndex].Update(msg)
+			if msg.Handled {
+				return m, cmd
+			}
+		}
+
+		prevFocus := m.focusIndex
+		switch {
+		case key.Matches(msg.KeyMsg, m.props.Global.KeyMap.NextX):
+			msg.Handled = true
+			m.focusIndex = util.Mod(m.focusIndex+1, len(m.inputs))
+		case key.Matches(msg.KeyMsg, m.props.Global.KeyMap.PrevX):
+			msg.Handled = true
+			m.focusIndex = util.Mod(m.focusIndex-1, len(m.inputs))
+		case key.Matches(msg.KeyMsg, m.props.Global.KeyMap.Select):
+			// Only year textfields reach here, other inputs handle select
+			msg.Handled = true
+			cmds = append(cmds, m.OnChange(m.Filters()))
+		}
+
+		if m.focusIndex != prevFocus {
+			m.inputs[prevFocus].Blur()
+			m.inputs[m.focusIndex].Focus()
+
+			if prevFocus <= 1 {
+				cmds = append(cmds, m.OnChange(m.Filters()))
+			}
+			// Textfields need an update to start the cursor
+			_, cmd := m.inputs[m.focusIndex].Update(nil)
+			cmds = append(cmds, cmd)
+		}
+		return m, tea.Batch(cmds...)
+	}
+
+	for _, input := range m.inputs {
+		_, cmd := input.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+// Dropdowns are drawn over the returned view with Overlay(), since they can expand.
+func (m *filterPanel) View() string {
+	gap := func(d *dropdown.Model) string { return strings.Repeat(" ", lipgloss.Width(d.View())) }
+
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		m.yearMin.View(), " ",
+		m.yearMax.View(), " ",
+		gap(m.genre), " ",
+		gap(m.language), " ",
+		gap(m.sort), " ",
+		m.adult.View(),
+	)
+}
+
+func (m *filterPanel) Overlay(view string, top int) string {
+	left := lipgloss.Width(m.yearMin.View()) + 1 + lipgloss.Width(m.yearMax.View()) + 1
+	for _, d := range []*dropdown.Model{m.genre, m.language, m.sort} {
+		dropdownView := d.View()
+		view = util.RenderOverlay(view, dropdownView, left, top)
+		left += lipgloss.Width(dropdownView) + 1
+	}
+	return view
+}
@@ -0,0 +1,252 @@
+package search
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	SortRelevance  = ""
+	SortPopularity = "popularity"
+	SortRating     = "rating"
+	SortDate       = "date"
+)
+
+// Zero values mean "any". Must stay comparable, it's used to skip repeat searches.
+type Filters struct {
+	YearMin  int
+	YearMax  int
+	Genre    int
+	Language string
+	Sort     string
+	Adult    bool
+}
+
+var sortAliases = map[string]string{
+	"pop":        SortPopularity,
+	"popular":    SortPopularity,
+	"popularity": SortPopularity,
+	"rating":     SortRating,
+	"rated":      SortRating,
+	"top":        SortRating,
+	"date":       SortDate,
+	"new":        SortDate,
+	"newest":     SortDate,
+	"release":    SortDate,
+}
+
+// Shorthands that don't prefix match a TMDB genre name
+var genreAliases = map[string]string{
+	"scifi":    "sciencefiction",
+	"sf":       "sciencefiction",
+	"romcom":   "romance",
+	"doc":      "documentary",
+	"docs":     "documentary",
+	"animated": "animation",
+	"anime":    "animation",
+	"tv":       "tvmovie",
+}
+
+// ParseQuery splits filter tokens like "y:1999 g:scifi" out of input.
+// Tokens that fail to parse are left in the returned query text.
+// Only the fields mentioned in input are overwritten in f.
+func ParseQuery(input string, genres map[int]string, f Filters) (string, Filters) {
+	words := []string{}
+
+	for _, word := range strings.Fields(input) {
+		name, value, found := strings.Cut(word, ":")
+		if !found || value == "" {
+			words = append(words, word)
+			continue
+		}
+
+		ok := true
+		switch strings.ToLower(name) {
+		case "y", "year":
+			f.YearMin, f.YearMax, ok = parseYears(value)
+		case "g", "genre":
+			var id int
+			id, ok = matchGenre(value, genres)
+			if ok {
+				f.Genre = id
+			}
+		case "l", "lang", "language":
+			ok = len(value) == 2
+			if ok {
+				f.Language = strings.ToLower(value)
+			}
+		case "s", "sort":
+			var sort string
+			sort, ok = sortAliases[strings.ToLower(value)]
+			if ok {
+				f.Sort = sort
+			}
+		case "adult":
+			switch strings.ToLower(value) {
+			case "y", "yes", "true", "1":
+				f.Adult = true
+			case "n", "no", "false", "0":
+				f.Adult = false
+			default:
+				ok = false
+			}
+		default:
+			ok = false
+		}
+
+		if !ok {
+			words = append(words, word)
+		}
+	}
+
+	return strings.Join(words, " "), f
+}
+
+// Accepts "1999", "1990-1999", "1990-" and "-1999"
+func parseYears(value string) (int, int, bool) {
+	minStr, maxStr, isRange := strings.Cut(value, "-")
+	if !isRange {
+		maxStr = minStr
+	}
+
+	var min, max int
+	var err error
+	if minStr != "" {
+		min, err = strconv.Atoi(minStr)
+		if err != nil {
+			return 0, 0, false
+		}
+	}
+	if maxStr != "" {
+		max, err = strconv.Atoi(maxStr)
+		if err != nil {
+			return 0, 0, false
+		}
+	}
+
+	if min == 0 && max == 0 {
+		return 0, 0, false
+	}
+	if min != 0 && max != 0 && min > max {
+		min, max = max, min
+	}
+	return min, max, true
+}
+
+func normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
+
+func matchGenre(value string, genres map[int]string) (int, bool) {
+	if id, err := strconv.Atoi(value); err == nil {
+		_, ok := genres[id]
+		return id, ok
+	}
+
+	value = normalize(value)
+	if alias, ok := genreAliases[value]; ok {
+		value = alias
+	}
+	if value == "" {
+		return 0, false
+	}
+
+	// Sort ids so prefix matches are deterministic
+	ids := make([]int, 0, len(genres))
+	for id := range genres {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		if normalize(genres[id]) == value {
+			return id, true
+		}
+	}
+	for _, id := range ids {
+		if strings.HasPrefix(normalize(genres[id]), value) {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+func (f Filters) Chips(genres map[int]string) []string {
+	chips := []string{}
+
+	switch {
+	case f.YearMin != 0 && f.YearMin == f.YearMax:
+		chips = append(chips, strconv.Itoa(f.YearMin))
+	case f.YearMin != 0 && f.YearMax != 0:
+		chips = append(chips, strconv.Itoa(f.YearMin)+"–"+strconv.Itoa(f.YearMax))
+	case f.YearMin != 0:
+		chips = append(chips, strconv.Itoa(f.YearMin)+"+")
+	case f.YearMax != 0:
+		chips = append(chips, "≤"+strconv.Itoa(f.YearMax))
+	}
+
+	if f.Genre != 0 {
+		name, ok := genres[f.Genre]
+		if !ok {
+			name = "genre " + strconv.Itoa(f.Genre)
+		}
+		chips = append(chips, name)
+	}
+
+	if f.Language != "" {
+		chips = append(chips, "lang: "+f.Language)
+	}
+
+	if f.Sort != SortRelevance {
+		chips = append(chips, "sort: "+f.Sort)
+	}
+
+	if f.Adult {
+		chips = append(chips, "adult")
+	}
+
+	return chips
+}
+
+// String returns the query syntax for f, so ParseQuery(f.String(genres), genres, Filters{}) == f
+func (f Filters) String(genres map[int]string) string {
+	tokens := []string{}
+
+	switch {
+	case f.YearMin != 0 && f.YearMin == f.YearMax:
+		tokens = append(tokens, "y:"+strconv.Itoa(f.YearMin))
+	case f.YearMin != 0 || f.YearMax != 0:
+		tokens = append(tokens, "y:"+intString(f.YearMin)+"-"+intString(f.YearMax))
+	}
+
+	if f.Genre != 0 {
+		// Names are nicer to read, but only if they match back to the same id
+		name := normalize(genres[f.Genre])
+		if id, ok := matchGenre(name, genres); ok && id == f.Genre {
+			tokens = append(tokens, "g:"+name)
+		} else {
+			tokens = append(tokens, "g:"+strconv.Itoa(f.Genre))
+		}
+	}
+
+	if f.Language != "" {
+		tokens = append(tokens, "l:"+f.Language)
+	}
+
+	if f.Sort != SortRelevance {
+		tokens = append(tokens, "s:"+f.Sort)
+	}
+
+	if f.Adult {
+		tokens = append(tokens, "adult:y")
+	}
+
+	return strings.Join(tokens, " ")
+}
@@ -1,27 +1,40 @@
 package search
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/zhengkyl/review-ssh/ui/common"
 	"github.com/zhengkyl/review-ssh/ui/components/textfield"
 	"github.com/zhengkyl/review-ssh/ui/components/vlist"
+	"github.com/zhengkyl/review-ssh/ui/pages/search/filmitem"
 	"github.com/zhengkyl/review-ssh/ui/util"
 )
 
 var (
-	viewStyle = lipgloss.NewStyle().MarginTop(1)
+	chipStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFF7DB")).Background(lipgloss.Color("#888B7E")).Padding(0, 1)
+	promptStyle = lipgloss.NewStyle().Padding(1, 3).Border(lipgloss.RoundedBorder(), true)
 )
 
 type Model struct {
-	props       common.Props
-	list        *vlist.Model
-	searchField *textfield.Model
-	focused     bool
-	Query       string
+	props         common.Props
+	list          *vlist.Model
+	searchField   *textfield.Model
+	panel         *filterPanel
+	pinField      *textfield.Model
+	showPanel     bool
+	genresFetched bool
+	focused       bool
+	filters       Filters
+	Query         string
+	// Incremented per search so stale responses can be dropped
+	seq    int
+	cancel context.CancelFunc
 }
 
 func New(p common.Props, searchField *textfield.Model) *Model {
@@ -29,11 +42,20 @@ func New(p common.Props, searchField *textfield.Model) *Model {
 		props:       p,
 		list:        vlist.New(p, 6),
 		searchField: searchField,
+		panel:       newFilterPanel(p),
+		pinField:    textfield.New(common.Props{Width: 30, Height: 3, Global: p.Global}),
 		focused:     false,
 	}
 
+	m.pinField.CharLimit(40)
+	m.pinField.Placeholder("Name")
+
 	m.list.Overflow = vlist.Paginate
 
+	m.panel.OnChange = func(f Filters) tea.Cmd {
+		return m.search(m.Query, f)
+	}
+
 	m.SetSize(p.Width, p.Height)
 
 	return m
@@ -47,12 +69,183 @@ func (m *Model) SetSize(width, height int) {
 	m.props.Width = width
 	m.props.Height = height
 
-	vf := viewStyle.GetVerticalFrameSize()
+	// chips + paginator
+	listH := height - 2
+	if m.showPanel {
+		listH -= panelHeight
+	}
+
+	m.list.SetSize(width, listH)
+}
+
+// Search parses filter syntax out of input, leaving the free text in the search field.
+func (m *Model) Search(input string) tea.Cmd {
+	if !m.genresFetched {
+		m.genresFetched = true
+		// Genres are needed to parse g:name tokens
+		return common.GetGenresCmd(m.props.Global, func() tea.Msg {
+			m.panel.SetGenres(m.props.Global.GenreMap)
+			return m.Search(input)
+		})
+	}
+
+	query, filters := ParseQuery(input, m.props.Global.GenreMap, m.filters)
+	m.searchField.SetValue(query)
+	m.panel.SetFilters(filters)
+
+	if entry := m.entry(query, filters); entry.Query != "" {
+		m.props.Global.SearchHistory.AddRecent(m.props.Global.AuthState.User.Id, entry)
+	}
+
+	return m.search(query, filters)
+}
+
+// Replay runs a search from history, ignoring the current filters.
+func (m *Model) Replay(entry common.SearchEntry) tea.Cmd {
+	m.filters = Filters{}
+	m.Query = ""
+	return m.Search(entry.Query)
+}
+
+// Entries store filters as query syntax, so they replay exactly
+func (m *Model) entry(query string, f Filters) common.SearchEntry {
+	return common.SearchEntry{
+		Query: strings.TrimSpace(f.String(m.props.Global.GenreMap) + " " + query),
+		Time:  time.Now(),
+	}
+}
+
+func (m *Model) search(query string, f Filters) tea.Cmd {
+	if query == m.Query && f == m.filters {
+		return nil
+	}
+	m.Query = query
+	m.filters = f
+	m.SetItems([]common.Focusable{})
+
+	m.seq++
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+
+	if query == "" && f == (Filters{}) {
+		return nil
+	}
+
+	seq := m.seq
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+
+	return searchCmd(ctx, m.props.Global, query, f, func(films []common.Film) tea.Msg {
+		if seq != m.seq {
+			return nil
+		}
+		inits := make([]tea.Cmd, 0, len(films))
+		items := make([]common.Focusable, 0, len(films))
+		for _, film := range films {
+			m.props.Global.FilmCache.Set(film.Id, film)
 
-	m.list.SetSize(width, height-vf-1)
+			item := filmitem.New(
+				common.Props{
+					Width:  m.props.Width,
+					Height: 6,
+					Global: m.props.Global,
+				}, film)
+			items = append(items, item)
+			inits = append(inits, item.Init())
+		}
+		m.SetItems(items)
+		return tea.Batch(inits...)
+	})
+}
+
+func (m *Model) Filters() Filters {
+	return m.filters
+}
+
+func (m *Model) togglePanel() tea.Cmd {
+	m.showPanel = !m.showPanel
+	m.SetSize(m.props.Width, m.props.Height)
+
+	if !m.showPanel {
+		m.panel.Blur()
+		return nil
+	}
+
+	m.panel.Focus()
+	if !m.genresFetched {
+		m.genresFetched = true
+		return common.GetGenresCmd(m.props.Global, func() tea.Msg {
+			m.panel.SetGenres(m.props.Global.GenreMap)
+			return nil
+		})
+	}
+	return nil
+}
+
+func (m *Model) openPinPrompt() tea.Cmd {
+	if m.Query == "" && m.filters == (Filters{}) {
+		return nil
+	}
+	m.pinField.SetValue(m.Query)
+	m.pinField.Focus()
+	_, cmd := m.pinField.Update(nil)
+	return cmd
 }
 
 func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case *common.KeyEvent:
+		if m.pinField.Focused() {
+			// Not KeyMap.Select, since names can have spaces
+			switch msg.KeyMsg.Type {
+			case tea.KeyEnter:
+				name := strings.TrimSpace(m.pinField.Value())
+				if name != "" {
+					entry := m.entry(m.Query, m.filters)
+					entry.Name = name
+					m.props.Global.SearchHistory.Save(m.props.Global.AuthState.User.Id, entry)
+				}
+				m.pinField.Blur()
+			default:
+				// Back is handled by blurring
+				_, cmd := m.pinField.Update(msg)
+				msg.Handled = true
+				return m, cmd
+			}
+			msg.Handled = true
+			return m, nil
+		}
+
+		if m.panel.Focused() {
+			_, cmd := m.panel.Update(msg)
+			if msg.Handled {
+				return m, cmd
+			}
+
+			if key.Matches(msg.KeyMsg, m.props.Global.KeyMap.Back) {
+				msg.Handled = true
+				return m, m.togglePanel()
+			}
+			// Other keys fall through to the list
+		}
+
+		switch {
+		case key.Matches(msg.KeyMsg, m.props.Global.KeyMap.Filter):
+			msg.Handled = true
+			return m, m.togglePanel()
+		case key.Matches(msg.KeyMsg, m.props.Global.KeyMap.Pin):
+			msg.Handled = true
+			return m, m.openPinPrompt()
+		}
+	default:
+		_, cmd := m.panel.Update(msg)
+		_, listCmd := m.list.Update(msg)
+		_, pinCmd := m.pinField.Update(msg)
+		return m, tea.Batch(cmd, listCmd, pinCmd)
+	}
+
 	_, cmd := m.list.Update(msg)
 
 	return m, cmd
@@ -60,6 +253,19 @@ func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
 
 func (m *Model) View() string {
 	sb := strings.Builder{}
+
+	chips := []string{}
+	for _, chip := range m.filters.Chips(m.props.Global.GenreMap) {
+		chips = append(chips, chipStyle.Render(chip), " ")
+	}
+	sb.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, chips...))
+	sb.WriteString("\n")
+
+	if m.showPanel {
+		sb.WriteString(m.panel.View())
+		sb.WriteString("\n")
+	}
+
 	if m.list.Length() == 0 {
 		sb.WriteString("No results.")
 	} else {
@@ -67,7 +273,7 @@ func (m *Model) View() string {
 	}
 
 	viewH := m.props.Height - 1
-	sb.WriteString(strings.Repeat("\n", viewH-lipgloss.Height(sb.String())))
+	sb.WriteString(strings.Repeat("\n", util.Max(viewH-lipgloss.Height(sb.String()), 0)))
 
 	start := m.list.Offset() + 1
 	last := m.list.Offset() + m.list.PerPage()
@@ -77,5 +283,17 @@ func (m *Model) View() string {
 	sb.WriteString(strings.Repeat(" ", m.props.Width-len(paginator)-2))
 	sb.WriteString(paginator)
 
-	return viewStyle.Render(sb.String())
+	view := sb.String()
+	if m.showPanel {
+		view = m.panel.Overlay(view, 1)
+	}
+
+	if m.pinField.Focused() {
+		prompt := promptStyle.Render("Save search as\n\n" + m.pinField.View())
+		x := util.Max((m.props.Width-lipgloss.Width(prompt))/2, 0)
+		y := util.Max((m.props.Height-lipgloss.Height(prompt))/2, 0)
+		view = util.RenderOverlay(view, prompt, x, y)
+	}
+
+	return view
 }
@@ -0,0 +1,277 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/util"
+)
+
+const (
+	minQueryLength = 3
+	maxSuggestions = 6
+	debounceTime   = 300 * time.Millisecond
+)
+
+var (
+	suggestionsStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder(), false, true, true).BorderForeground(lipgloss.Color("#F25D94"))
+	suggestionStyle  = lipgloss.NewStyle().Padding(0, 1)
+	activeSuggestion = lipgloss.NewStyle().Background(lipgloss.Color("#F25D94")).Padding(0, 1)
+	yearStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+)
+
+type debounceMsg struct {
+	seq int
+}
+
+// Suggestions shows films matching the search field as it's typed in.
+type Suggestions struct {
+	props   common.Props
+	search  *Model
+	query   string
+	filters Filters
+	films   []common.Film
+	entries []common.SearchEntry // History shown instead of films when input is empty
+	active  int                  // -1 if none
+	seq     int
+	cancel  context.CancelFunc
+}
+
+func NewSuggestions(p common.Props, search *Model) *Suggestions {
+	return &Suggestions{
+		props:  p,
+		search: search,
+		active: -1,
+	}
+}
+
+func (m *Suggestions) SetSize(width, height int) {
+	m.props.Width = width
+	m.props.Height = height
+}
+
+// Active returns the highlighted film, if any
+func (m *Suggestions) Active() (common.Film, bool) {
+	if m.active == -1 || m.active >= len(m.films) {
+		return common.Film{}, false
+	}
+	return m.films[m.active], true
+}
+
+// ActiveEntry returns the highlighted history entry, if any
+func (m *Suggestions) ActiveEntry() (common.SearchEntry, bool) {
+	if m.active == -1 || m.active >= len(m.entries) {
+		return common.SearchEntry{}, false
+	}
+	return m.entries[m.active], true
+}
+
+func (m *Suggestions) length() int {
+	return len(m.films) + len(m.entries)
+}
+
+func (m *Suggestions) loadHistory() {
+	userId := m.props.Global.AuthState.User.Id
+	m.entries = m.props.Global.SearchHistory.Saved(userId)
+	for _, entry := range m.props.Global.SearchHistory.Recent(userId) {
+		if len(m.entries) >= maxSuggestions+2 {
+			break
+		}
+		m.entries = append(m.entries, entry)
+	}
+	m.active = util.Min(m.active, len(m.entries)-1)
+}
+
+func (m *Suggestions) removeActiveEntry() {
+	entry, ok := m.ActiveEntry()
+	if !ok {
+		return
+	}
+
+	userId := m.props.Global.AuthState.User.Id
+	if entry.Name != "" {
+		m.props.Global.SearchHistory.Unsave(userId, entry.Name)
+	} else {
+		m.props.Global.SearchHistory.RemoveRecent(userId, entry.Query)
+	}
+	m.loadHistory()
+}
+
+func (m *Suggestions) Clear() {
+	m.seq++
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+	m.query = ""
+	m.films = nil
+	m.entries = nil
+	m.active = -1
+}
+
+// SetInput shows cached matches immediately, then schedules a debounced remote search.
+func (m *Suggestions) SetInput(input string) tea.Cmd {
+	if strings.TrimSpace(input) == "" {
+		m.Clear()
+		m.loadHistory()
+		return nil
+	}
+
+	query, filters := ParseQuery(input, m.props.Global.GenreMap, m.search.Filters())
+	query = strings.TrimSpace(query)
+
+	if query == m.query && len(m.entries) == 0 {
+		return nil
+	}
+
+	m.Clear()
+	m.query = query
+	m.filters = filters
+
+	if len([]rune(query)) < minQueryLength {
+		return nil
+	}
+
+	m.films = filterFilms(m.localMatches(query), filters)
+	if len(m.films) > maxSuggestions {
+		m.films = m.films[:maxSuggestions]
+	}
+
+	seq := m.seq
+	return tea.Tick(debounceTime, func(time.Time) tea.Msg {
+		return debounceMsg{seq}
+	})
+}
+
+func (m *Suggestions) localMatches(query string) []common.Film {
+	query = strings.ToLower(query)
+	matches := []common.Film{}
+
+	for _, info := range m.props.Global.FilmCache {
+		if info.Loading {
+			continue
+		}
+		if strings.Contains(strings.ToLower(info.Data.Title), query) {
+			matches = append(matches, info.Data)
+		}
+	}
+
+	// Map order is random, keep suggestions stable between keystrokes
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Title < matches[j].Title })
+	return matches
+}
+
+func (m *Suggestions) fetch() tea.Cmd {
+	seq := m.seq
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+
+	return searchCmd(ctx, m.props.Global, m.query, m.filters, func(films []common.Film) tea.Msg {
+		if seq != m.seq {
+			return nil
+		}
+
+		// Keep local matches first so the list doesn't jump around
+		for _, film := range films {
+			if len(m.films) >= maxSuggestions {
+				break
+			}
+			duplicate := false
+			for _, existing := range m.films {
+				if existing.Id == film.Id {
+					duplicate = true
+					break
+				}
+			}
+			if !duplicate {
+				m.props.Global.FilmCache.Set(film.Id, film)
+				m.films = append(m.films, film)
+			}
+		}
+		return nil
+	})
+}
+
+func (m *Suggestions) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case debounceMsg:
+		if msg.seq == m.seq {
+			return m, m.fetch()
+		}
+	case *common.KeyEvent:
+		if m.length() == 0 {
+			return m, nil
+		}
+		// Only arrows, letters are for typing
+		switch msg.KeyMsg.Type {
+		case tea.KeyDown:
+			msg.Handled = true
+			m.active = util.Min(m.active+1, m.length()-1)
+		case tea.KeyUp:
+			msg.Handled = true
+			m.active = util.Max(m.active-1, -1)
+		case tea.KeyDelete:
+			if m.active != -1 && len(m.entries) > 0 {
+				msg.Handled = true
+				m.removeActiveEntry()
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *Suggestions) View() string {
+	if m.length() == 0 {
+		return ""
+	}
+
+	width := m.props.Width - suggestionsStyle.GetHorizontalFrameSize() - suggestionStyle.GetHorizontalFrameSize()
+
+	if len(m.entries) > 0 {
+		return m.historyView(width)
+	}
+
+	lines := make([]string, 0, len(m.films))
+	for i, film := range m.films {
+		year := ""
+		if len(film.Release_date) >= 4 {
+			year = " " + film.Release_date[:4]
+		}
+		title := util.TruncAndPadUnicode(film.Title, util.Max(width-len(year), 2))
+
+		if i == m.active {
+			lines = append(lines, activeSuggestion.Render(title+year))
+		} else {
+			lines = append(lines, suggestionStyle.Render(title+yearStyle.Render(year)))
+		}
+	}
+
+	return suggestionsStyle.Render(strings.Join(lines, "\n"))
+}
+
+func (m *Suggestions) historyView(width int) string {
+	lines := make([]string, 0, len(m.entries))
+	for i, entry := range m.entries {
+		// "★ name  query" for saved, "  query" for recent
+		label := "  "
+		detail := entry.Query
+		if entry.Name != "" {
+			label = "★ " + entry.Name + "  "
+		}
+		label = util.TruncAndPadUnicode(label, util.Min(lipgloss.Width(label), width))
+		detail = util.TruncAndPadUnicode(detail, util.Max(width-lipgloss.Width(label), 2))
+
+		if i == m.active {
+			lines = append(lines, activeSuggestion.Render(label+detail))
+		} else {
+			lines = append(lines, suggestionStyle.Render(label+yearStyle.Render(detail)))
+		}
+	}
+
+	return suggestionsStyle.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,318 @@
+package settings
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/util"
+)
+
+var (
+	pageStyle   = lipgloss.NewStyle().Margin(1, 2)
+	hintStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	accentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25D94"))
+	errStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("197"))
+)
+
+// Where to watch: a region, and services to check in it
+type Model struct {
+	props     common.Props
+	region    textinput.Model
+	regions   []common.Region
+	providers []common.Provider
+	loading   bool
+	err       string
+	inList    bool // Focus is on services instead of region
+	active    int
+	offset    int
+}
+
+func New(p common.Props) *Model {
+	region := textinput.New()
+	region.Prompt = ""
+	region.Placeholder = "US"
+	region.CharLimit = 40
+
+	m := &Model{
+		props:  p,
+		region: region,
+	}
+	m.SetSize(p.Width, p.Height)
+	return m
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.props.Width = width - pageStyle.GetHorizontalFrameSize()
+	m.props.Height = height - pageStyle.GetVerticalFrameSize()
+}
+
+func (m *Model) settings() common.Settings {
+	return m.props.Global.Settings.Get(m.props.Global.AuthState.User.Id)
+}
+
+func (m *Model) save(s common.Settings) {
+	m.props.Global.Settings.Set(m.props.Global.AuthState.User.Id, s)
+}
+
+func (m *Model) Init() tea.Cmd {
+	m.err = ""
+	m.inList = false
+	m.active = 0
+	m.offset = 0
+	m.region.SetValue(m.settings().Region)
+
+	cmds := []tea.Cmd{m.region.Focus(), m.loadProviders()}
+	if m.regions == nil {
+		cmds = append(cmds, common.GetRegionListCmd(m.props.Global, func(data common.RegionList, err error) tea.Msg {
+			if err == nil {
+				sort.Slice(data.Results, func(i, j int) bool {
+					return data.Results[i].English_name < data.Results[j].English_name
+				})
+				m.regions = data.Results
+			}
+			return nil
+		}))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) loadProviders() tea.Cmd {
+	region := m.settings().Region
+	m.loading = true
+	m.providers = nil
+
+	return common.GetProviderListCmd(m.props.Global, region, func(data common.ProviderList, err error) tea.Msg {
+		if region != m.settings().Region {
+			return nil
+		}
+		m.loading = false
+		if err != nil {
+			m.err = fmt.Sprintf("Could not load services (%v)", err)
+			return nil
+		}
+		sort.SliceStable(data.Results, func(i, j int) bool {
+			return data.Results[i].Display_priority < data.Results[j].Display_priority
+		})
+		m.providers = data.Results
+		return nil
+	})
+}
+
+// Matches a code like "gb", or the start of a name like "united k"
+func (m *Model) matchRegion(input string) (common.Region, bool) {
+	input = strings.TrimSpace(strings.ToLower(input))
+	if input == "" {
+		return common.Region{}, false
+	}
+
+	var found []common.Region
+	for _, r := range m.regions {
+		if strings.ToLower(r.Iso_3166_1) == input {
+			return r, true
+		}
+		if strings.HasPrefix(strings.ToLower(r.English_name), input) {
+			found = append(found, r)
+		}
+	}
+	if len(found) == 1 {
+		return found[0], true
+	}
+	return common.Region{}, false
+}
+
+func (m *Model) regionName(code string) string {
+	for _, r := range m.regions {
+		if r.Iso_3166_1 == code {
+			return r.English_name
+		}
+	}
+	return code
+}
+
+func (m *Model) applyRegion() tea.Cmd {
+	r, ok := m.matchRegion(m.region.Value())
+	if !ok {
+		m.err = "Unknown region, try a code like GB or a name like Canada."
+		return nil
+	}
+	m.err = ""
+	m.region.SetValue(r.Iso_3166_1)
+
+	s := m.settings()
+	if s.Region == r.Iso_3166_1 {
+		return nil
+	}
+	s.Region = r.Iso_3166_1
+	m.save(s)
+	m.active = 0
+	m.offset = 0
+	return m.loadProviders()
+}
+
+func (m *Model) toggle() {
+	if len(m.providers) == 0 {
+		return
+	}
+	id := m.providers[m.active].Provider_id
+
+	s := m.settings()
+	if s.Subscribed(id) {
+		services := []int{}
+		for _, service := range s.Services {
+			if service != id {
+				services = append(services, service)
+			}
+		}
+		s.Services = services
+	} else {
+		s.Services = append(s.Services, id)
+	}
+	m.save(s)
+}
+
+func (m *Model) focusList(inList bool) tea.Cmd {
+	m.inList = inList
+	if inList {
+		m.region.Blur()
+		return nil
+	}
+	return m.region.Focus()
+}
+
+// Rows of services below the region field and above the hint
+func (m *Model) listHeight() int {
+	return util.Max(m.props.Height-7, 3)
+}
+
+func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case *common.KeyEvent:
+		if !m.inList {
+			return m, m.updateRegion(msg)
+		}
+
+		km := m.props.Global.KeyMap
+		switch {
+		case msg.KeyMsg.Type == tea.KeyTab || msg.KeyMsg.Type == tea.KeyShiftTab:
+			msg.Handled = true
+			return m, m.focusList(false)
+		case key.Matches(msg.KeyMsg, km.Up):
+			msg.Handled = true
+			if m.active == 0 {
+				return m, m.focusList(false)
+			}
+			m.active--
+		case key.Matches(msg.KeyMsg, km.Down):
+			msg.Handled = true
+			m.active = util.Max(util.Min(m.active+1, len(m.providers)-1), 0)
+		case key.Matches(msg.KeyMsg, km.Select):
+			msg.Handled = true
+			m.toggle()
+		}
+
+		if m.active < m.offset {
+			m.offset = m.active
+		} else if m.active >= m.offset+m.listHeight() {
+			m.offset = m.active - m.listHeight() + 1
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.region, cmd = m.region.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) updateRegion(msg *common.KeyEvent) tea.Cmd {
+	switch msg.KeyMsg.Type {
+	case tea.KeyEsc:
+		// Leave the page
+		return nil
+	case tea.KeyEnter:
+		msg.Handled = true
+		cmd := m.applyRegion()
+		if m.err != "" {
+			return cmd
+		}
+		return tea.Batch(cmd, m.focusList(true))
+	case tea.KeyTab, tea.KeyShiftTab, tea.KeyDown:
+		msg.Handled = true
+		m.region.SetValue(m.settings().Region)
+		m.err = ""
+		return m.focusList(true)
+	}
+
+	msg.Handled = true
+	var cmd tea.Cmd
+	m.region, cmd = m.region.Update(msg.KeyMsg)
+	return cmd
+}
+
+func (m *Model) servicesView() string {
+	s := m.settings()
+
+	switch {
+	case m.loading:
+		return hintStyle.Render("Loading services...")
+	case len(m.providers) == 0:
+		return hintStyle.Render("No services found")
+	}
+
+	lines := []string{}
+	end := util.Min(m.offset+m.listHeight(), len(m.providers))
+	for i := m.offset; i < end; i++ {
+		p := m.providers[i]
+
+		box := "[ ] "
+		if s.Subscribed(p.Provider_id) {
+			box = "[x] "
+		}
+		name := util.TruncAndPadUnicode(p.Provider_name, util.Max(m.props.Width-4, 2))
+		if m.inList && i == m.active {
+			name = accentStyle.Render(name)
+		}
+		lines = append(lines, box+name)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) View() string {
+	s := m.settings()
+	view := strings.Builder{}
+
+	view.WriteString(accentStyle.Render("Where to watch"))
+	view.WriteString("\n\n")
+
+	label := "Region   "
+	if !m.inList {
+		label = accentStyle.Render(label)
+	}
+	view.WriteString(label)
+	view.WriteString(m.region.View())
+	view.WriteString("  ")
+	if m.err != "" {
+		view.WriteString(errStyle.Render(m.err))
+	} else if r, ok := m.matchRegion(m.region.Value()); ok {
+		view.WriteString(hintStyle.Render(r.English_name))
+	}
+	view.WriteString("\n\n")
+
+	view.WriteString(hintStyle.Render(fmt.Sprintf("Services you subscribe to in %s (%d selected)", m.regionName(s.Region), len(s.Services))))
+	view.WriteString("\n")
+	view.WriteString(m.servicesView())
+	view.WriteString("\n\n")
+
+	if m.inList {
+		view.WriteString(hintStyle.Render("space toggle · tab region · esc back"))
+	} else {
+		view.WriteString(hintStyle.Render("enter set region · tab services · esc back"))
+	}
+
+	return pageStyle.Render(lipgloss.NewStyle().MaxWidth(m.props.Width).Render(view.String()))
+}
@@ -0,0 +1,106 @@
+package social
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/common/enums"
+)
+
+const (
+	maxActivity = 100
+	loadWorkers = 4
+	// Pages of 50 reviews per followed user, enough to fill the feed from one of them.
+	// Loading every page would make the feed slower the more they've reviewed.
+	feedPages = 2
+)
+
+// review-api only keeps the latest state of a review, so each review is one
+// event at its last update
+type Activity struct {
+	UserId int
+	Review common.Review
+}
+
+// What the review says someone did, most notable first
+func (a Activity) Verb() string {
+	r := a.Review
+	switch {
+	case strings.TrimSpace(r.Text) != "":
+		return "reviewed"
+	case r.Status == enums.Completed && r.Fun_after:
+		return "starred"
+	case r.Status == enums.Completed && r.Fun_during:
+		return "liked"
+	case r.Status == enums.Completed:
+		return "completed"
+	case r.Status == enums.PlanToWatch:
+		return "wants to watch"
+	case r.Status == enums.Watching:
+		return "is watching"
+	case r.Status == enums.Dropped:
+		return "dropped"
+	}
+	return "added"
+}
+
+// LoadFeed gets followed users' reviews, newest first. This blocks.
+// Users that fail to load are skipped, unless every one does.
+func LoadFeed(ctx context.Context, g common.Global, following []int) ([]Activity, error) {
+	var (
+		mtx      sync.Mutex
+		feed     []Activity
+		failures int
+		lastErr  error
+	)
+
+	common.ForEach(ctx, len(following), loadWorkers, func(i int) {
+		userId := following[i]
+		reviews, err := common.FetchReviews(ctx, g, userId, feedPages)
+
+		mtx.Lock()
+		defer mtx.Unlock()
+		if err != nil {
+			failures++
+			lastErr = err
+		}
+		for _, review := range reviews {
+			feed = append(feed, Activity{userId, review})
+		}
+	})
+
+	if len(following) > 0 && failures == len(following) {
+		return nil, lastErr
+	}
+
+	sort.Slice(feed, func(i, j int) bool {
+		return feed[i].Review.Updated_at.After(feed[j].Review.Updated_at)
+	})
+	if len(feed) > maxActivity {
+		feed = feed[:maxActivity]
+	}
+	return feed, ctx.Err()
+}
+
+// Short relative times, like "5m" or "3d", then dates
+func ago(t time.Time, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case t.Year() == now.Year():
+		return t.Format("Jan 2")
+	}
+	return t.Format("Jan 2006")
+}
@@ -0,0 +1,387 @@
+package social
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/components/tabs"
+	"github.com/zhengkyl/review-ssh/ui/util"
+)
+
+type tab int
+
+const (
+	feedTab tab = iota
+	followingTab
+)
+
+// This must match the order of tabs
+var tabNames = []string{
+	"Feed",
+	"Following",
+}
+
+var NUM_TABS = len(tabNames)
+
+var (
+	pageStyle   = lipgloss.NewStyle().Margin(0, 1)
+	mutedStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	activeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25D94"))
+	errStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("197"))
+)
+
+// What the people you follow have been watching
+type Model struct {
+	props     common.Props
+	activeTab tab
+	feed      []Activity
+	loading   bool
+	err       string
+	seq       int
+	cancel    context.CancelFunc
+	active    []int // Per tab
+	offset    []int
+	finding   bool // Typing a user id to look up
+	find      textinput.Model
+}
+
+func New(p common.Props) *Model {
+	find := textinput.New()
+	find.Prompt = "User id: "
+	find.CharLimit = 10
+
+	m := &Model{
+		props:  p,
+		find:   find,
+		active: make([]int, NUM_TABS),
+		offset: make([]int, NUM_TABS),
+	}
+	m.SetSize(p.Width, p.Height)
+	return m
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.props.Width = width - pageStyle.GetHorizontalFrameSize()
+	m.props.Height = height - pageStyle.GetVerticalFrameSize()
+}
+
+func (m *Model) following() []int {
+	return m.props.Global.Settings.Get(m.props.Global.AuthState.User.Id).Following
+}
+
+func (m *Model) stop() {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+	m.seq++
+}
+
+// The feed reloads on every visit, follows may have changed
+func (m *Model) Init() tea.Cmd {
+	m.stop()
+	m.feed = nil
+	m.err = ""
+	m.finding = false
+	m.find.Blur()
+	for i := range m.active {
+		m.active[i] = 0
+		m.offset[i] = 0
+	}
+
+	following := m.following()
+	cmds := []tea.Cmd{m.loadUsers(following)}
+	if len(following) == 0 {
+		m.loading = false
+		return tea.Batch(cmds...)
+	}
+
+	g := m.props.Global
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.loading = true
+	seq := m.seq
+
+	cmds = append(cmds, func() tea.Msg {
+		feed, err := LoadFeed(ctx, g, following)
+		return func() tea.Msg {
+			if seq != m.seq {
+				return nil
+			}
+			m.loading = false
+			if err != nil {
+				m.err = fmt.Sprintf("Could not load activity (%v)", err)
+				return nil
+			}
+			m.feed = feed
+
+			userIds := []int{}
+			for _, a := range feed {
+				userIds = append(userIds, a.UserId)
+			}
+			return tea.Batch(m.loadUsers(userIds), m.loadFilms())
+		}
+	})
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) loadUsers(userIds []int) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, userId := range userIds {
+		cmds = append(cmds, common.GetUserCmd(m.props.Global, userId))
+	}
+	return tea.Batch(cmds...)
+}
+
+// Only titles on screen are fetched, the feed can be long
+func (m *Model) loadFilms() tea.Cmd {
+	var cmds []tea.Cmd
+	end := util.Min(m.offset[feedTab]+m.listHeight(), len(m.feed))
+	for _, a := range m.feed[m.offset[feedTab]:end] {
+		cmds = append(cmds, common.GetFilmCmd(m.props.Global, a.Review.Tmdb_id))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) userName(userId int) string {
+	if ok, _, user := m.props.Global.UserCache.Get(userId); ok {
+		return user.Name
+	}
+	return "#" + strconv.Itoa(userId)
+}
+
+func (m *Model) length() int {
+	if m.activeTab == feedTab {
+		return len(m.feed)
+	}
+	return len(m.following())
+}
+
+// Rows that fit below the tabs and above the hint
+func (m *Model) listHeight() int {
+	return util.Max(m.props.Height-5, 1)
+}
+
+func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case *common.KeyEvent:
+		km := m.props.Global.KeyMap
+
+		if m.finding {
+			msg.Handled = true
+			switch msg.KeyMsg.Type {
+			case tea.KeyEsc:
+				m.finding = false
+				m.find.Blur()
+				return m, nil
+			case tea.KeyEnter:
+				userId, err := strconv.Atoi(strings.TrimSpace(m.find.Value()))
+				if err != nil || userId <= 0 {
+					m.err = "User ids are positive numbers"
+					return m, nil
+				}
+				m.finding = false
+				m.find.Blur()
+				return m, func() tea.Msg { return common.ShowUser(userId) }
+			}
+			m.err = ""
+			var cmd tea.Cmd
+			m.find, cmd = m.find.Update(msg.KeyMsg)
+			return m, cmd
+		}
+
+		switch {
+		case key.Matches(msg.KeyMsg, km.NextX):
+			msg.Handled = true
+			m.activeTab = tab((int(m.activeTab) + 1) % NUM_TABS)
+			return m, nil
+		case key.Matches(msg.KeyMsg, km.PrevX):
+			msg.Handled = true
+			m.activeTab = tab((int(m.activeTab) - 1 + NUM_TABS) % NUM_TABS)
+			return m, nil
+		case key.Matches(msg.KeyMsg, km.Add):
+			msg.Handled = true
+			m.finding = true
+			m.err = ""
+			m.find.SetValue("")
+			return m, m.find.Focus()
+		}
+
+		if m.length() == 0 {
+			return m, nil
+		}
+
+		t := m.activeTab
+		switch {
+		case key.Matches(msg.KeyMsg, km.Down):
+			msg.Handled = true
+			m.active[t] = util.Min(m.active[t]+1, m.length()-1)
+		case key.Matches(msg.KeyMsg, km.Up):
+			msg.Handled = true
+			m.active[t] = util.Max(m.active[t]-1, 0)
+		case t == feedTab && key.Matches(msg.KeyMsg, km.Select):
+			msg.Handled = true
+			filmId := m.feed[m.active[t]].Review.Tmdb_id
+			return m, func() tea.Msg { return common.ShowFilm(filmId) }
+		case t == feedTab && key.Matches(msg.KeyMsg, km.Profile),
+			t == followingTab && key.Matches(msg.KeyMsg, km.Select):
+			msg.Handled = true
+			var userId int
+			if t == feedTab {
+				userId = m.feed[m.active[t]].UserId
+			} else {
+				userId = m.following()[m.active[t]]
+			}
+			return m, func() tea.Msg { return common.ShowUser(userId) }
+		case t == followingTab && key.Matches(msg.KeyMsg, km.Follow):
+			msg.Handled = true
+			g := m.props.Global
+			settings := g.Settings.Get(g.AuthState.User.Id)
+			settings.ToggleFollow(settings.Following[m.active[t]])
+			g.Settings.Set(g.AuthState.User.Id, settings)
+			m.active[t] = util.Max(util.Min(m.active[t], len(settings.Following)-1), 0)
+			// Their activity stays until the next visit
+		}
+
+		if m.active[t] < m.offset[t] {
+			m.offset[t] = m.active[t]
+		} else if m.active[t] >= m.offset[t]+m.listHeight() {
+			m.offset[t] = m.active[t] - m.listHeight() + 1
+		}
+		if t == feedTab {
+			return m, m.loadFilms()
+		}
+		return m, nil
+	}
+
+	if m.finding {
+		var cmd tea.Cmd
+		m.find, cmd = m.find.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m *Model) feedView() string {
+	following := m.following()
+	switch {
+	case len(following) == 0:
+		return mutedStyle.Render("You don't follow anyone yet. Press a to look someone up by id, then f on their lists.")
+	case m.loading:
+		return mutedStyle.Render(fmt.Sprintf("Loading activity from %d people...", len(following)))
+	case len(m.feed) == 0:
+		return mutedStyle.Render("Nothing yet.")
+	}
+
+	now := time.Now()
+	nameWidth := 14
+	verbWidth := 14
+	ratingWidth := 5
+	titleWidth := util.Max(m.props.Width-4-1-nameWidth-1-verbWidth-1-ratingWidth-1, 10)
+
+	lines := []string{}
+	end := util.Min(m.offset[feedTab]+m.listHeight(), len(m.feed))
+	for i := m.offset[feedTab]; i < end; i++ {
+		a := m.feed[i]
+
+		title := "Loading..."
+		if ok, _, film := m.props.Global.FilmCache.Get(a.Review.Tmdb_id); ok {
+			title = film.Title
+			if len(film.Release_date) >= 4 {
+				title += " (" + film.Release_date[:4] + ")"
+			}
+		}
+		title = util.TruncAndPadUnicode(title, titleWidth)
+		if i == m.active[feedTab] {
+			title = activeStyle.Render(title)
+		}
+
+		rating := ""
+		if a.Review.Fun_during || a.Review.Fun_after {
+			rating = common.RenderRating(a.Review.Fun_before, a.Review.Fun_during, a.Review.Fun_after)
+		}
+
+		lines = append(lines, mutedStyle.Render(util.TruncAndPadUnicode(ago(a.Review.Updated_at, now), 4))+" "+
+			util.TruncAndPadUnicode(m.userName(a.UserId), nameWidth)+" "+
+			mutedStyle.Render(util.TruncAndPadUnicode(a.Verb(), verbWidth))+" "+
+			title+" "+rating)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) followingView() string {
+	following := m.following()
+	if len(following) == 0 {
+		return mutedStyle.Render("You don't follow anyone yet. Press a to look someone up by id.")
+	}
+
+	// Counts only cover what the feed loaded
+	counts := map[int]int{}
+	for _, a := range m.feed {
+		counts[a.UserId]++
+	}
+
+	lines := []string{}
+	end := util.Min(m.offset[followingTab]+m.listHeight(), len(following))
+	for i := m.offset[followingTab]; i < end; i++ {
+		userId := following[i]
+		name := util.TruncAndPadUnicode(m.userName(userId), 24)
+		if i == m.active[followingTab] {
+			name = activeStyle.Render(name)
+		}
+		recent := ""
+		if counts[userId] > 0 {
+			recent = fmt.Sprintf("%d recent", counts[userId])
+		}
+		lines = append(lines, name+" "+mutedStyle.Render(util.TruncAndPadUnicode("#"+strconv.Itoa(userId), 8)+" "+recent))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m *Model) hintView() string {
+	if m.finding {
+		return m.find.View() + mutedStyle.Render("  enter open · esc cancel")
+	}
+
+	hint := "←→ tabs · a find user"
+	if m.length() > 0 {
+		if m.activeTab == feedTab {
+			hint += " · enter details · @ their lists"
+		} else {
+			hint += " · enter their lists · f unfollow"
+		}
+	}
+	return mutedStyle.Render(hint)
+}
+
+func (m *Model) View() string {
+	sb := strings.Builder{}
+
+	sb.WriteString(tabs.View(tabNames, int(m.activeTab)))
+	sb.WriteString("\n")
+
+	switch {
+	case m.activeTab == feedTab && m.err != "" && !m.finding:
+		sb.WriteString(errStyle.Render(m.err))
+	case m.activeTab == feedTab:
+		sb.WriteString(m.feedView())
+	default:
+		sb.WriteString(m.followingView())
+	}
+
+	sb.WriteString(strings.Repeat("\n", util.Max(m.props.Height-1-lipgloss.Height(sb.String()), 1)))
+	if m.finding && m.err != "" {
+		sb.WriteString(errStyle.Render(m.err) + " ")
+	}
+	sb.WriteString(m.hintView())
+
+	return pageStyle.Render(sb.String())
+}
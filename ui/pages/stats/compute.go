@@ -0,0 +1,219 @@
+package stats
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/zhengkyl/review-ssh/ui/common/enums"
+	"github.com/zhengkyl/review-ssh/ui/pages/exporter"
+)
+
+const topN = 10
+
+type Count struct {
+	Label string
+	N     int
+}
+
+// Consecutive days or weeks with at least one film completed
+type Streak struct {
+	Len   int
+	Start time.Time
+	End   time.Time
+}
+
+type Stats struct {
+	Total       int
+	Completed   int
+	Watching    int
+	PlanToWatch int
+	Dropped     int
+	// Of completed films
+	Liked   int
+	Starred int
+	Minutes int
+	Timed   int // Completed films with a known runtime
+
+	Years     []Count // Oldest first
+	Months    []Count // The last 12, oldest first
+	Genres    []Count // Most first
+	Decades   []Count // Oldest first
+	Directors []Count // Most first
+
+	LongestDays  Streak
+	LongestWeeks Streak
+	CurrentDays  int
+}
+
+// Compute goes by when reviews were last updated, since review-api doesn't keep
+// watch dates. directors maps film ids to their directors' names, and genres
+// names genre ids for films without details.
+func Compute(items []exporter.Item, directors map[int][]string, genres map[int]string, now time.Time) Stats {
+	s := Stats{Total: len(items)}
+
+	years := map[int]int{}
+	months := map[string]int{}
+	genreCounts := map[string]int{}
+	decades := map[int]int{}
+	directorCounts := map[string]int{}
+	days := []time.Time{}
+
+	for _, item := range items {
+		r := item.Review
+		switch r.Status {
+		case enums.Watching:
+			s.Watching++
+		case enums.PlanToWatch:
+			s.PlanToWatch++
+		case enums.Dropped:
+			s.Dropped++
+		}
+		if r.Status != enums.Completed {
+			continue
+		}
+
+		s.Completed++
+		if r.Fun_during {
+			s.Liked++
+		}
+		if r.Fun_after {
+			s.Starred++
+		}
+
+		film := item.Film
+		if film.Runtime > 0 {
+			s.Minutes += film.Runtime
+			s.Timed++
+		}
+
+		when := r.Updated_at.In(now.Location())
+		years[when.Year()]++
+		months[when.Format("2006-01")]++
+		days = append(days, time.Date(when.Year(), when.Month(), when.Day(), 0, 0, 0, 0, now.Location()))
+
+		if len(film.Genres) > 0 {
+			for _, genre := range film.Genres {
+				genreCounts[genre.Name]++
+			}
+		} else {
+			for _, id := range film.Genre_ids {
+				if name, ok := genres[id]; ok {
+					genreCounts[name]++
+				}
+			}
+		}
+
+		if year, err := strconv.Atoi(item.Year()); err == nil {
+			decades[year/10*10]++
+		}
+
+		for _, name := range directors[film.Id] {
+			directorCounts[name]++
+		}
+	}
+
+	for year := range years {
+		s.Years = append(s.Years, Count{strconv.Itoa(year), years[year]})
+	}
+	sort.Slice(s.Years, func(i, j int) bool { return s.Years[i].Label < s.Years[j].Label })
+
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, -11, 0)
+	for i := 0; i < 12; i++ {
+		month := start.AddDate(0, i, 0)
+		s.Months = append(s.Months, Count{month.Format("Jan 06"), months[month.Format("2006-01")]})
+	}
+
+	for decade := range decades {
+		s.Decades = append(s.Decades, Count{strconv.Itoa(decade) + "s", decades[decade]})
+	}
+	sort.Slice(s.Decades, func(i, j int) bool { return s.Decades[i].Label < s.Decades[j].Label })
+
+	s.Genres = top(genreCounts)
+	s.Directors = top(directorCounts)
+
+	s.LongestDays, s.CurrentDays = dayStreaks(days, now)
+	s.LongestWeeks = weekStreak(days)
+
+	return s
+}
+
+// Most first, ties alphabetical so the order is stable
+func top(counts map[string]int) []Count {
+	list := make([]Count, 0, len(counts))
+	for label, n := range counts {
+		list = append(list, Count{label, n})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].N != list[j].N {
+			return list[i].N > list[j].N
+		}
+		return list[i].Label < list[j].Label
+	})
+	if len(list) > topN {
+		list = list[:topN]
+	}
+	return list
+}
+
+func uniqueSorted(times []time.Time) []time.Time {
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	unique := []time.Time{}
+	for _, t := range times {
+		if len(unique) == 0 || !unique[len(unique)-1].Equal(t) {
+			unique = append(unique, t)
+		}
+	}
+	return unique
+}
+
+// longest is judged by days run, current only counts if it reaches today or yesterday
+func dayStreaks(days []time.Time, now time.Time) (Streak, int) {
+	days = uniqueSorted(days)
+
+	longest := Streak{}
+	run := Streak{}
+	for i, day := range days {
+		if i > 0 && days[i-1].AddDate(0, 0, 1).Equal(day) {
+			run.Len++
+			run.End = day
+		} else {
+			run = Streak{1, day, day}
+		}
+		if run.Len > longest.Len {
+			longest = run
+		}
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	current := 0
+	if run.Len > 0 && !run.End.Before(today.AddDate(0, 0, -1)) {
+		current = run.Len
+	}
+	return longest, current
+}
+
+// Weeks start on Monday
+func weekStreak(days []time.Time) Streak {
+	weeks := make([]time.Time, 0, len(days))
+	for _, day := range days {
+		offset := (int(day.Weekday()) + 6) % 7
+		weeks = append(weeks, day.AddDate(0, 0, -offset))
+	}
+	weeks = uniqueSorted(weeks)
+
+	longest := Streak{}
+	run := Streak{}
+	for i, week := range weeks {
+		if i > 0 && weeks[i-1].AddDate(0, 0, 7).Equal(week) {
+			run.Len++
+			run.End = week
+		} else {
+			run = Streak{1, week, week}
+		}
+		if run.Len > longest.Len {
+			longest = run
+		}
+	}
+	return longest
+}
@@ -0,0 +1,52 @@
+package stats
+
+import (
+	"context"
+	"sync"
+
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/common/enums"
+	"github.com/zhengkyl/review-ssh/ui/pages/exporter"
+)
+
+const loadWorkers = 4
+
+// Load gets every review joined with its film, and the directors of completed
+// films. This blocks. known films must have details, or runtimes and genres
+// will be missing.
+func Load(ctx context.Context, g common.Global, userId int, known map[int]common.Film) ([]exporter.Item, map[int][]string, error) {
+	items, err := exporter.Load(ctx, g, userId, known)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var mtx sync.Mutex
+	directors := map[int][]string{}
+
+	completed := []int{}
+	for _, item := range items {
+		if item.Review.Status == enums.Completed {
+			completed = append(completed, item.Review.Tmdb_id)
+		}
+	}
+
+	common.ForEach(ctx, len(completed), loadWorkers, func(i int) {
+		id := completed[i]
+		// Films without credits just don't count towards directors
+		credits, err := common.Do[common.Credits](ctx, g, "GET", common.FilmURL(g, id, "/credits"), nil)
+		if err != nil {
+			return
+		}
+		names := []string{}
+		for _, crew := range credits.Crew {
+			if crew.Job == "Director" {
+				names = append(names, crew.Name)
+			}
+		}
+		mtx.Lock()
+		directors[id] = names
+		mtx.Unlock()
+	})
+
+	return items, directors, ctx.Err()
+}
@@ -0,0 +1,175 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/zhengkyl/review-ssh/ui/common"
+	"github.com/zhengkyl/review-ssh/ui/util"
+)
+
+var (
+	pageStyle   = lipgloss.NewStyle().Margin(1, 2)
+	hintStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	accentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F25D94"))
+	errStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("197"))
+)
+
+type Model struct {
+	props   common.Props
+	pager   viewport.Model
+	stats   *Stats // nil until loaded
+	loading bool
+	status  string
+	err     string
+	seq     int
+	cancel  context.CancelFunc
+}
+
+func New(p common.Props) *Model {
+	m := &Model{
+		props: p,
+		pager: viewport.New(p.Width, p.Height),
+	}
+	m.SetSize(p.Width, p.Height)
+	return m
+}
+
+func (m *Model) SetSize(width, height int) {
+	m.props.Width = width
+	m.props.Height = height
+
+	// margins + title + hint
+	m.pager.Width = util.Max(width-4, 10)
+	m.pager.Height = util.Max(height-6, 1)
+	m.render()
+}
+
+func (m *Model) stop() {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+	m.seq++
+}
+
+// Reviews may have changed since the last visit, so this always reloads
+func (m *Model) Init() tea.Cmd {
+	m.stop()
+	m.stats = nil
+	m.status = ""
+	m.err = ""
+	m.loading = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	seq := m.seq
+	g := m.props.Global
+
+	// Runtimes and genres need details, other cached films are fetched again
+	known := map[int]common.Film{}
+	for id := range g.FilmCache {
+		if ok, _, film := g.FilmCache.Get(id); ok && film.HasDetails() {
+			known[id] = film
+		}
+	}
+
+	return func() tea.Msg {
+		items, directors, err := Load(ctx, g, g.AuthState.User.Id, known)
+		return func() tea.Msg {
+			if seq != m.seq {
+				return nil
+			}
+			m.loading = false
+			if err != nil {
+				m.err = fmt.Sprintf("Could not load reviews (%v)", err)
+				return nil
+			}
+			for _, item := range items {
+				if item.Film.Id != 0 {
+					g.FilmCache.Set(item.Film.Id, item.Film)
+				}
+			}
+			stats := Compute(items, directors, g.GenreMap, time.Now())
+			m.stats = &stats
+			m.render()
+			m.pager.GotoTop()
+			return nil
+		}
+	}
+}
+
+func (m *Model) render() {
+	if m.stats == nil {
+		return
+	}
+	sb := strings.Builder{}
+	Write(&sb, *m.stats, m.pager.Width)
+	m.pager.SetContent(sb.String())
+}
+
+func (m *Model) copy() {
+	if m.props.Global.Output == nil {
+		m.status = "Copying isn't supported here."
+		return
+	}
+
+	// Copies are sized for a typical editor rather than this terminal
+	sb := strings.Builder{}
+	Write(&sb, *m.stats, 80)
+	m.props.Global.Output.Copy(sb.String())
+	m.status = fmt.Sprintf("Copied %d bytes. Not every terminal supports OSC 52.", sb.Len())
+}
+
+func (m *Model) Update(msg tea.Msg) (common.Model, tea.Cmd) {
+	event, ok := msg.(*common.KeyEvent)
+	if !ok || m.stats == nil {
+		return m, nil
+	}
+
+	if key.Matches(event.KeyMsg, m.props.Global.KeyMap.Copy) {
+		event.Handled = true
+		m.copy()
+		return m, nil
+	}
+
+	// viewport keys are pgup/pgdown/up/down/j/k etc
+	var cmd tea.Cmd
+	m.pager, cmd = m.pager.Update(event.KeyMsg)
+	if key.Matches(event.KeyMsg, m.pager.KeyMap.Up, m.pager.KeyMap.Down, m.pager.KeyMap.PageUp, m.pager.KeyMap.PageDown, m.pager.KeyMap.HalfPageUp, m.pager.KeyMap.HalfPageDown) {
+		event.Handled = true
+	}
+	return m, cmd
+}
+
+func (m *Model) View() string {
+	view := strings.Builder{}
+	view.WriteString(accentStyle.Render("Stats"))
+
+	switch {
+	case m.loading:
+		view.WriteString("\n\nLoading reviews, films and credits...")
+	case m.err != "":
+		view.WriteString("\n\n")
+		view.WriteString(errStyle.Render(m.err))
+	default:
+		view.WriteString(hintStyle.Render(fmt.Sprintf("  %d%%", int(m.pager.ScrollPercent()*100))))
+		view.WriteString("\n")
+		view.WriteString(m.pager.View())
+		view.WriteString("\n")
+
+		if m.status != "" {
+			view.WriteString(hintStyle.Render(m.status))
+		} else {
+			view.WriteString(hintStyle.Render("c copy · ↑↓ scroll · or ssh you@example.com@<host> stats > stats.txt"))
+		}
+	}
+
+	return pageStyle.Render(view.String())
+}
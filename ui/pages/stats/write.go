@@ -0,0 +1,120 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/zhengkyl/review-ssh/ui/util"
+)
+
+const (
+	summaryLabelWidth = 15
+	maxLabelWidth     = 18
+	maxBarWidth       = 40
+)
+
+// Eighths of a block, for bars that aren't a whole number of cells
+var partials = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+func bar(n, max, width int) string {
+	if max == 0 {
+		return ""
+	}
+	eighths := n * width * 8 / max
+	if n > 0 && eighths == 0 {
+		eighths = 1
+	}
+	return strings.Repeat("█", eighths/8) + partials[eighths%8]
+}
+
+func chart(sb *strings.Builder, title string, counts []Count, width int) {
+	fmt.Fprintf(sb, "\n%s\n", title)
+	if len(counts) == 0 {
+		sb.WriteString("Nothing yet\n")
+		return
+	}
+
+	labelWidth, max := 0, 0
+	for _, c := range counts {
+		labelWidth = util.Max(labelWidth, len([]rune(c.Label)))
+		max = util.Max(max, c.N)
+	}
+	labelWidth = util.Min(labelWidth, maxLabelWidth)
+	countWidth := len(strconv.Itoa(max))
+	barWidth := util.Min(util.Max(width-labelWidth-countWidth-3, 4), maxBarWidth)
+
+	for _, c := range counts {
+		fmt.Fprintf(sb, "%s %*d", util.TruncAndPadUnicode(c.Label, labelWidth), countWidth, c.N)
+		if b := bar(c.N, max, barWidth); b != "" {
+			sb.WriteString(" " + b)
+		}
+		sb.WriteString("\n")
+	}
+}
+
+func percent(n, of int) string {
+	if of == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d%% (%d of %d)", n*100/of, n, of)
+}
+
+func duration(minutes int) string {
+	days, hours, mins := minutes/(24*60), minutes/60%24, minutes%60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh %dm", days, hours, mins)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, mins)
+	}
+	return fmt.Sprintf("%dm", mins)
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// Write renders s as plain text that fits in width columns, bars and all
+func Write(w io.Writer, s Stats, width int) error {
+	sb := strings.Builder{}
+
+	row := func(label, value string) {
+		sb.WriteString(util.TruncAndPadUnicode(label, summaryLabelWidth))
+		sb.WriteString(value)
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("Summary\n")
+	row("Completed", strconv.Itoa(s.Completed))
+	row("Watching", strconv.Itoa(s.Watching))
+	row("Plan to watch", strconv.Itoa(s.PlanToWatch))
+	row("Dropped", strconv.Itoa(s.Dropped))
+	row("Liked ♥", percent(s.Liked, s.Completed))
+	row("Starred ★", percent(s.Starred, s.Completed))
+
+	watched := duration(s.Minutes)
+	if s.Timed < s.Completed {
+		watched += fmt.Sprintf(", %d films without a runtime", s.Completed-s.Timed)
+	}
+	row("Time watched", watched)
+
+	if s.LongestDays.Len > 0 {
+		row("Longest streak", plural(s.LongestDays.Len, "day")+", ending "+s.LongestDays.End.Format("Jan 2 2006"))
+		row("Weekly streak", plural(s.LongestWeeks.Len, "week")+", ending "+s.LongestWeeks.End.Format("Jan 2 2006"))
+		row("Current streak", plural(s.CurrentDays, "day"))
+	}
+
+	chart(&sb, "By year", s.Years, width)
+	chart(&sb, "Last 12 months", s.Months, width)
+	chart(&sb, "Genres", s.Genres, width)
+	chart(&sb, "Release decades", s.Decades, width)
+	chart(&sb, "Directors", s.Directors, width)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
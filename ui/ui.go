@@ -1,23 +1,36 @@
 package ui
 
 import (
-	"net/url"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/ansi"
+	"github.com/zhengkyl/review-ssh/metrics"
 	"github.com/zhengkyl/review-ssh/ui/common"
 	"github.com/zhengkyl/review-ssh/ui/components/button"
 	"github.com/zhengkyl/review-ssh/ui/components/dialog"
 	"github.com/zhengkyl/review-ssh/ui/components/textfield"
 	"github.com/zhengkyl/review-ssh/ui/pages/account"
+	"github.com/zhengkyl/review-ssh/ui/pages/diary"
+	"github.com/zhengkyl/review-ssh/ui/pages/discover"
+	"github.com/zhengkyl/review-ssh/ui/pages/exporter"
 	"github.com/zhengkyl/review-ssh/ui/pages/filmdetails"
+	"github.com/zhengkyl/review-ssh/ui/pages/foryou"
+	"github.com/zhengkyl/review-ssh/ui/pages/group"
+	"github.com/zhengkyl/review-ssh/ui/pages/groups"
+	"github.com/zhengkyl/review-ssh/ui/pages/importer"
 	"github.com/zhengkyl/review-ssh/ui/pages/lists"
+	"github.com/zhengkyl/review-ssh/ui/pages/person"
+	"github.com/zhengkyl/review-ssh/ui/pages/profile"
 	"github.com/zhengkyl/review-ssh/ui/pages/search"
-	"github.com/zhengkyl/review-ssh/ui/pages/search/filmitem"
+	"github.com/zhengkyl/review-ssh/ui/pages/settings"
+	"github.com/zhengkyl/review-ssh/ui/pages/social"
+	"github.com/zhengkyl/review-ssh/ui/pages/stats"
 	"github.com/zhengkyl/review-ssh/ui/util"
 )
 
@@ -26,9 +39,11 @@ var (
 	appStyle   = lipgloss.NewStyle().MarginBottom(1)
 	titleStyle = lipgloss.NewStyle().Background(lipgloss.Color("#F25D94")).Padding(0, 1)
 	title      = titleStyle.Render("review-ssh")
+	userStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Padding(0, 1)
 )
 
-const searchEndpoint = common.ReviewBase + "/search/Film"
+// The signed in user's name, after the search field
+const userTagWidth = 20
 
 type page int
 
@@ -37,8 +52,52 @@ const (
 	LISTS
 	FILMDETAILS
 	SEARCH
+	IMPORT
+	EXPORT
+	PERSON
+	FORYOU
+	SETTINGS
+	DISCOVER
+	SOCIAL
+	PROFILE
+	GROUPS
+	GROUP
+	STATS
+	DIARY
+	MANAGE
 )
 
+// Names for metrics
+var pageNames = [...]string{
+	ACCOUNT:     "account",
+	LISTS:       "lists",
+	FILMDETAILS: "filmdetails",
+	SEARCH:      "search",
+	IMPORT:      "import",
+	EXPORT:      "export",
+	PERSON:      "person",
+	FORYOU:      "foryou",
+	SETTINGS:    "settings",
+	DISCOVER:    "discover",
+	SOCIAL:      "social",
+	PROFILE:     "profile",
+	GROUPS:      "groups",
+	GROUP:       "group",
+	STATS:       "stats",
+	DIARY:       "diary",
+	MANAGE:      "manage",
+}
+
+func (p page) String() string {
+	return pageNames[p]
+}
+
+// A page and the film, person, user or group it shows, if any
+type location struct {
+	page page
+	id   int
+}
+
 type Model struct {
 	props           common.Props
 	searchField     *textfield.Model
@@ -46,10 +105,33 @@ type Model struct {
 	listsPage       *lists.Model
 	filmdetailsPage *filmdetails.Model
 	searchPage      *search.Model
+	importPage      *importer.Model
+	exportPage      *exporter.Model
+	personPage      *person.Model
+	forYouPage      *foryou.Model
+	settingsPage    *settings.Model
+	discoverPage    *discover.Model
+	socialPage      *social.Model
+	profilePage     *profile.Model
+	groupsPage      *groups.Model
+	groupPage       *group.Model
+	statsPage       *stats.Model
+	diaryPage       *diary.Model
+	managePage      *account.Manage
+	suggestions     *search.Suggestions
 	dialog          *dialog.Model
 	help            help.Model
 	page            page
-	backPage        page
+	pageId          int
+	history         util.Stack[location] // Where back goes, LISTS if empty
+	resume          *location            // Where a 401 interrupted, kept with history until signing in again
+	resumeUser      int
+	migrate         bool   // Keep the guest's reviews once signed in
+	notice          string // Replaces the banner until the next key
+	lastKey         time.Time
+	idleWarned      bool
+	shutdownAt      time.Time // When the server closes the session, if it's stopping
+	reportedUser    int       // Who Presence was last told about
 }
 
 func New(p common.Props) *Model {
@@ -65,10 +147,26 @@ func New(p common.Props) *Model {
 		listsPage:       lists.New(p),
 		filmdetailsPage: filmdetails.New(p),
 		searchPage:      search.New(p, searchField),
+		importPage:      importer.New(p),
+		exportPage:      exporter.New(p),
+		personPage:      person.New(p),
+		forYouPage:      foryou.New(p),
+		settingsPage:    settings.New(p),
+		discoverPage:    discover.New(p),
+		socialPage:      social.New(p),
+		profilePage:     profile.New(p),
+		groupsPage:      groups.New(p),
+		groupPage:       group.New(p),
+		statsPage:       stats.New(p),
+		diaryPage:       diary.New(p),
+		managePage:      account.NewManage(p),
 		dialog:          dialog.New(p, "Quit program?"),
 		help:            help.New(),
+		lastKey:         time.Now(),
 	}
 
+	m.suggestions = search.NewSuggestions(p, m.searchPage)
+
 	m.dialog.Buttons(
 		*button.New(p, "Yes", tea.Quit),
 		*button.New(p, "No", func() tea.Msg {
@@ -87,24 +185,212 @@ func (m *Model) SetSize(width, height int) {
 
 	viewW := width
 	viewH := height - 5 // bottom margin + help + searchfield
+	if m.banner() != "" {
+		viewH--
+	}
 
-	// title + " " + searchField = width
-	m.searchField.SetSize(width-lipgloss.Width(title)-1, 3)
+	// title + " " + searchField + userTag = width
+	m.searchField.SetSize(width-lipgloss.Width(title)-1-userTagWidth, 3)
+	m.suggestions.SetSize(width-lipgloss.Width(title)-1-userTagWidth, 0)
 
 	m.accountPage.SetSize(util.Max(viewW/2, 30), viewH)
 
 	m.listsPage.SetSize(viewW, viewH)
 	m.searchPage.SetSize(viewW, viewH)
 	m.filmdetailsPage.SetSize(viewW, viewH)
+	m.importPage.SetSize(viewW, viewH)
+	m.exportPage.SetSize(viewW, viewH)
+	m.personPage.SetSize(viewW, viewH)
+	m.forYouPage.SetSize(viewW, viewH)
+	m.settingsPage.SetSize(viewW, viewH)
+	m.discoverPage.SetSize(viewW, viewH)
+	m.socialPage.SetSize(viewW, viewH)
+	m.profilePage.SetSize(viewW, viewH)
+	m.groupsPage.SetSize(viewW, viewH)
+	m.groupPage.SetSize(viewW, viewH)
+	m.statsPage.SetSize(viewW, viewH)
+	m.diaryPage.SetSize(viewW, viewH)
+	m.managePage.SetSize(viewW, viewH)
 
 	m.help.Width = viewW
 }
 
+// navigate remembers the current page so back can return to it
+func (m *Model) navigate(to page, id int) {
+	if m.page == to && m.pageId == id {
+		return
+	}
+	m.history.Push(location{m.page, m.pageId})
+	m.page = to
+	m.pageId = id
+}
+
+func (m *Model) back() tea.Cmd {
+	prev := location{LISTS, 0}
+	if !m.history.IsEmpty() {
+		prev = m.history.Pop()
+	}
+	m.page = prev.page
+	m.pageId = prev.id
+
+	// These pages show one film/person/user/group at a time, so they may need to be put back
+	switch m.page {
+	case LISTS:
+		m.history.Clear()
+		m.listsPage.ReloadReviews()
+		m.searchField.Blur()
+		m.searchField.SetValue("")
+		m.suggestions.Clear()
+	case FILMDETAILS:
+		return m.filmdetailsPage.Init(prev.id)
+	case PERSON:
+		return m.personPage.Init(prev.id)
+	case PROFILE:
+		return m.profilePage.Init(prev.id)
+	case GROUP:
+		return m.groupPage.Init(prev.id)
+	case GROUPS:
+		// Leaving or joining from a group page changes the list
+		return m.groupsPage.Init()
+	case DIARY:
+		// Watches may have been logged on the film
+		return m.diaryPage.Refresh()
+	}
+	return nil
+}
+
+// Forgets the user and everything fetched for them, back to the account picker.
+// Guests can keep their reviews to save once they sign up.
+func (m *Model) signOut(keepGuest bool) {
+	g := m.props.Global
+	if m.guest() && keepGuest && m.guestReviews() > 0 {
+		m.migrate = true
+		defer m.accountPage.SignUp(fmt.Sprintf("Sign up or sign in to keep your %s.", reviewsText(m.guestReviews())))
+	} else if g.Guest != nil {
+		m.migrate = false
+		g.Guest.Clear()
+	}
+
+	*m.props.Global.AuthState = common.AuthState{}
+	m.props.Global.Sessions.Forget()
+	m.resume = nil
+	for id := range m.props.Global.ReviewMap {
+		delete(m.props.Global.ReviewMap, id)
+	}
+
+	m.page = ACCOUNT
+	m.pageId = 0
+	m.history.Clear()
+	m.dialog.Blur()
+	m.searchField.Blur()
+	m.searchField.SetValue("")
+	m.suggestions.Clear()
+	m.accountPage.Reset()
+	m.notice = ""
+	m.SetSize(m.props.Width, m.props.Height)
+}
+
 func (m *Model) Init() tea.Cmd {
+	metrics.PageViews.Inc(m.page.String())
+	m.report()
+	return tea.Batch(m.accountPage.Restore(), m.watchIdle())
+}
+
+// Signs out locally after review-api rejects the cookie, keeping where the user was
+func (m *Model) expire() {
+	g := m.props.Global
+	if !g.AuthState.Authed || m.page == ACCOUNT {
+		return
+	}
+	g.Sessions.Forget()
+
+	m.resume = &location{m.page, m.pageId}
+	m.resumeUser = g.AuthState.User.Id
+	g.AuthState.Authed = false
+	g.AuthState.Cookie = ""
+
+	m.page = ACCOUNT
+	m.dialog.Blur()
+	m.searchField.Blur()
+	m.suggestions.Clear()
+	m.accountPage.Expired(g.AuthState.User.Email)
+}
+
+// Puts the interrupted page back, refetching what it shows
+func (m *Model) resumePage() tea.Cmd {
+	m.page = m.resume.page
+	m.pageId = m.resume.id
+	m.resume = nil
+	return m.reloadPage()
+}
+
+// Refetches what the current page shows
+func (m *Model) reloadPage() tea.Cmd {
+	switch m.page {
+	case LISTS:
+		return m.listsPage.Init()
+	case FILMDETAILS:
+		return m.filmdetailsPage.Init(m.pageId)
+	case PERSON:
+		return m.personPage.Init(m.pageId)
+	case PROFILE:
+		return m.profilePage.Init(m.pageId)
+	case GROUP:
+		return m.groupPage.Init(m.pageId)
+	case GROUPS:
+		return m.groupsPage.Init()
+	case DIARY:
+		return m.diaryPage.Refresh()
+	case MANAGE:
+		return m.managePage.Init()
+	}
+	// Other pages keep what was entered, to try again
 	return nil
 }
 
+// Counts a view whenever Update changes the page, and tells admins about it
+func (m *Model) viewed(prev location) {
+	if m.page != prev.page || m.pageId != prev.id {
+		metrics.PageViews.Inc(m.page.String())
+		m.report()
+	} else if m.userId() != m.reportedUser {
+		m.report()
+	}
+}
+
+func (m *Model) userId() int {
+	if !m.props.Global.AuthState.Authed {
+		return 0
+	}
+	return m.props.Global.AuthState.User.Id
+}
+
+func (m *Model) report() {
+	g := m.props.Global
+	if g.Presence == nil {
+		return
+	}
+	m.reportedUser = m.userId()
+	user := ""
+	if g.AuthState.Authed {
+		user = g.AuthState.User.Name
+	}
+	g.Presence.Report(m.reportedUser, user, m.page.String(), len(g.FilmCache))
+}
+
+// An admin emptied the caches, so the page shows what's fetched again
+func (m *Model) flushCaches() tea.Cmd {
+	g := m.props.Global
+	g.FilmCache.Clear()
+	g.ProviderCache.Clear()
+	g.UserCache.Clear()
+	m.report()
+	return m.reloadPage()
+}
+
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	defer m.viewed(location{m.page, m.pageId})
+
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
@@ -122,19 +408,78 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.props.Global.AuthState.Authed = msg.Authed
 		m.props.Global.AuthState.Cookie = msg.Cookie
 		m.props.Global.AuthState.User = msg.User
+		m.props.Global.AuthState.Expires = msg.Expires
+
+		if m.resume != nil && m.resumeUser == msg.User.Id {
+			return m, m.resumePage()
+		}
+
+		// Someone else signed in, so nothing of the last user's is wanted
+		if m.resume != nil {
+			m.resume = nil
+			for id := range m.props.Global.ReviewMap {
+				delete(m.props.Global.ReviewMap, id)
+			}
+		}
 		m.page = LISTS
-		m.backPage = LISTS
+		m.history.Clear()
+		m.SetSize(m.props.Width, m.props.Height)
 
+		if m.migrate && !m.guest() {
+			m.migrate = false
+			return m, tea.Batch(m.listsPage.Init(), m.migrateGuest())
+		}
 		return m, m.listsPage.Init()
+	case common.Unauthorized:
+		m.expire()
+		return m, nil
+	case common.SignOut:
+		m.signOut(msg.KeepGuest)
+		return m, nil
+	case common.ShuttingDown:
+		return m, m.shuttingDown(msg)
+	case idleTick:
+		return m, m.checkIdle()
+	case shutdownTick:
+		return m, m.watchShutdown()
+	case common.Broadcast:
+		m.notice = "From the admins: " + string(msg)
+		m.SetSize(m.props.Width, m.props.Height)
+		return m, nil
+	case common.FlushCaches:
+		return m, m.flushCaches()
 	case tea.WindowSizeMsg:
 		m.SetSize(msg.Width, msg.Height)
 
 	case common.ShowFilm:
 		cmds = append(cmds, m.filmdetailsPage.Init(int(msg)))
-		m.backPage = m.page
-		m.page = FILMDETAILS
+		m.navigate(FILMDETAILS, int(msg))
+
+	case common.ShowPerson:
+		cmds = append(cmds, m.personPage.Init(int(msg)))
+		m.navigate(PERSON, int(msg))
+
+	case common.ShowUser:
+		cmds = append(cmds, m.profilePage.Init(int(msg)))
+		m.navigate(PROFILE, int(msg))
+
+	case common.ShowGroup:
+		cmds = append(cmds, m.groupPage.Init(int(msg)))
+		m.navigate(GROUP, int(msg))
 
 	case tea.KeyMsg:
+		m.lastKey = time.Now()
+		if m.idleWarned {
+			// The key was only to stay
+			m.idleWarned = false
+			return m, nil
+		}
+
+		if m.notice != "" {
+			m.notice = ""
+			m.SetSize(m.props.Width, m.props.Height)
+		}
+
 		var cmd tea.Cmd
 		event := &common.KeyEvent{KeyMsg: msg, Handled: false}
 
@@ -143,7 +488,19 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.dialog.Focused() {
 			_, cmd = m.dialog.Update(event)
 		} else if m.searchField.Focused() {
+			prevValue := m.searchField.Value()
 			_, cmd = m.searchField.Update(event)
+
+			if !event.Handled {
+				_, suggestCmd := m.suggestions.Update(event)
+				cmd = tea.Batch(cmd, suggestCmd)
+			}
+
+			if !m.searchField.Focused() {
+				m.suggestions.Clear()
+			} else if m.searchField.Value() != prevValue {
+				cmd = tea.Batch(cmd, m.suggestions.SetInput(m.searchField.Value()))
+			}
 		} else {
 			switch m.page {
 			case ACCOUNT:
@@ -154,6 +511,32 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				_, cmd = m.filmdetailsPage.Update(event)
 			case SEARCH:
 				_, cmd = m.searchPage.Update(event)
+			case IMPORT:
+				_, cmd = m.importPage.Update(event)
+			case EXPORT:
+				_, cmd = m.exportPage.Update(event)
+			case PERSON:
+				_, cmd = m.personPage.Update(event)
+			case FORYOU:
+				_, cmd = m.forYouPage.Update(event)
+			case SETTINGS:
+				_, cmd = m.settingsPage.Update(event)
+			case DISCOVER:
+				_, cmd = m.discoverPage.Update(event)
+			case SOCIAL:
+				_, cmd = m.socialPage.Update(event)
+			case PROFILE:
+				_, cmd = m.profilePage.Update(event)
+			case GROUPS:
+				_, cmd = m.groupsPage.Update(event)
+			case GROUP:
+				_, cmd = m.groupPage.Update(event)
+			case STATS:
+				_, cmd = m.statsPage.Update(event)
+			case DIARY:
+				_, cmd = m.diaryPage.Update(event)
+			case MANAGE:
+				_, cmd = m.managePage.Update(event)
 			}
 		}
 
@@ -165,64 +548,90 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch {
 		case key.Matches(msg, m.props.Global.KeyMap.Back):
 			if m.page == ACCOUNT || m.page == LISTS {
-				m.dialog.Focus()
+				m.confirmQuit()
 				return m, nil
 			}
 
-			m.page = m.backPage
-			m.backPage = LISTS // This works b/c UI only 2 levels deep
+			return m, m.back()
 
+		case key.Matches(msg, m.props.Global.KeyMap.Import):
 			if m.page == LISTS {
-				m.listsPage.ReloadReviews()
-				m.searchField.Blur()
-				m.searchField.SetValue("")
+				m.navigate(IMPORT, 0)
+				return m, m.importPage.Init()
+			}
+		case key.Matches(msg, m.props.Global.KeyMap.Export):
+			if m.page == LISTS {
+				m.navigate(EXPORT, 0)
+				return m, m.exportPage.Init()
+			}
+		case key.Matches(msg, m.props.Global.KeyMap.ForYou):
+			if m.page == LISTS {
+				m.navigate(FORYOU, 0)
+				return m, m.forYouPage.Init()
+			}
+		case key.Matches(msg, m.props.Global.KeyMap.Settings):
+			if m.page == LISTS {
+				m.navigate(SETTINGS, 0)
+				return m, m.settingsPage.Init()
+			}
+		case key.Matches(msg, m.props.Global.KeyMap.Discover):
+			if m.page == LISTS {
+				m.navigate(DISCOVER, 0)
+				return m, m.discoverPage.Init()
+			}
+		case key.Matches(msg, m.props.Global.KeyMap.People):
+			if m.page == LISTS {
+				m.navigate(SOCIAL, 0)
+				return m, m.socialPage.Init()
+			}
+		case key.Matches(msg, m.props.Global.KeyMap.Groups):
+			if m.page == LISTS {
+				m.navigate(GROUPS, 0)
+				return m, m.groupsPage.Init()
+			}
+		case key.Matches(msg, m.props.Global.KeyMap.Stats):
+			if m.page == LISTS {
+				m.navigate(STATS, 0)
+				return m, m.statsPage.Init()
+			}
+		case key.Matches(msg, m.props.Global.KeyMap.Diary):
+			if m.page == LISTS {
+				m.navigate(DIARY, 0)
+				return m, m.diaryPage.Init()
+			}
+		case key.Matches(msg, m.props.Global.KeyMap.Account):
+			if m.page == LISTS {
+				m.navigate(MANAGE, 0)
+				return m, m.managePage.Init()
 			}
-
 		case key.Matches(msg, m.props.Global.KeyMap.Quit):
 			if m.dialog.Focused() {
 				return m, tea.Quit
 			}
-			m.dialog.Focus()
+			m.confirmQuit()
 			return m, nil
 		case key.Matches(msg, m.props.Global.KeyMap.Search):
 			if !m.searchField.Focused() {
 				m.searchField.Focus()
 				_, cmd := m.searchField.Update(nil)
-				return m, cmd
+				return m, tea.Batch(cmd, m.suggestions.SetInput(m.searchField.Value()))
 			}
 		case key.Matches(msg, m.props.Global.KeyMap.Select):
 			if m.searchField.Focused() {
 				m.searchField.Blur()
 
-				m.backPage = m.page
-				m.page = SEARCH
-				if m.searchPage.Query != m.searchField.Value() {
-					m.searchPage.SetItems([]common.Focusable{})
-					m.searchPage.Query = m.searchField.Value()
-					cmd := common.Get[common.Paged[common.Film]](m.props.Global, searchEndpoint+"?query="+url.QueryEscape(m.searchField.Value()), func(data common.Paged[common.Film], err error) tea.Msg {
-						if err != nil {
-							return nil
-						}
-
-						inits := make([]tea.Cmd, 0, len(data.Results))
-						items := make([]common.Focusable, 0, len(data.Results))
-						for _, film := range data.Results {
-							m.props.Global.FilmCache.Set(film.Id, film)
-
-							item := filmitem.New(
-								common.Props{
-									Width:  m.props.Width,
-									Height: 6,
-									Global: m.props.Global,
-								}, film)
-							items = append(items, item)
-							inits = append(inits, item.Init())
-						}
-						m.searchPage.SetItems(items)
-						return tea.Batch(inits...)
-					})
-					return m, cmd
+				film, ok := m.suggestions.Active()
+				entry, isEntry := m.suggestions.ActiveEntry()
+				m.suggestions.Clear()
+				if ok {
+					return m, func() tea.Msg { return common.ShowFilm(film.Id) }
 				}
+
+				m.navigate(SEARCH, 0)
+				if isEntry {
+					return m, m.searchPage.Replay(entry)
+				}
+				return m, m.searchPage.Search(m.searchField.Value())
 			}
 		}
 
@@ -243,6 +652,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 	}
 
+	_, cmd = m.suggestions.Update(msg)
+	cmds = append(cmds, cmd)
+
 	switch m.page {
 	case ACCOUNT:
 		_, cmd = m.accountPage.Update(msg)
@@ -252,6 +664,32 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		_, cmd = m.filmdetailsPage.Update(msg)
 	case SEARCH:
 		_, cmd = m.searchPage.Update(msg)
+	case IMPORT:
+		_, cmd = m.importPage.Update(msg)
+	case EXPORT:
+		_, cmd = m.exportPage.Update(msg)
+	case PERSON:
+		_, cmd = m.personPage.Update(msg)
+	case FORYOU:
+		_, cmd = m.forYouPage.Update(msg)
+	case SETTINGS:
+		_, cmd = m.settingsPage.Update(msg)
+	case DISCOVER:
+		_, cmd = m.discoverPage.Update(msg)
+	case SOCIAL:
+		_, cmd = m.socialPage.Update(msg)
+	case PROFILE:
+		_, cmd = m.profilePage.Update(msg)
+	case GROUPS:
+		_, cmd = m.groupsPage.Update(msg)
+	case GROUP:
+		_, cmd = m.groupPage.Update(msg)
+	case STATS:
+		_, cmd = m.statsPage.Update(msg)
+	case DIARY:
+		_, cmd = m.diaryPage.Update(msg)
+	case MANAGE:
+		_, cmd = m.managePage.Update(msg)
 	}
 	cmds = append(cmds, cmd)
 
@@ -262,8 +700,16 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *Model) View() string {
+	start := time.Now()
+	defer func() { metrics.FrameSeconds.Observe(time.Since(start).Seconds()) }()
+
 	view := strings.Builder{}
 
+	if m.banner() != "" {
+		view.WriteString(m.bannerView())
+		view.WriteString("\n")
+	}
+
 	if !m.props.Global.AuthState.Authed {
 		// 3 tall to match search bar + fullwidth to allow centering accountPage view
 		rightPad := util.Max(m.props.Width-ansi.PrintableRuneWidth(title), 0)
@@ -272,7 +718,8 @@ func (m *Model) View() string {
 		centered := lipgloss.JoinVertical(lipgloss.Center, appBar, m.accountPage.View())
 		view.WriteString(centered)
 	} else {
-		appBar := lipgloss.JoinHorizontal(lipgloss.Center, title, " ", m.searchField.View())
+		userTag := userStyle.Render(util.TruncAndPadUnicode("A "+m.props.Global.AuthState.User.Name, userTagWidth-2))
+		appBar := lipgloss.JoinHorizontal(lipgloss.Center, title, " ", m.searchField.View(), userTag)
 		view.WriteString(appBar)
 		view.WriteString("\n")
 
@@ -283,6 +730,32 @@ func (m *Model) View() string {
 			view.WriteString(m.filmdetailsPage.View())
 		case SEARCH:
 			view.WriteString(m.searchPage.View())
+		case IMPORT:
+			view.WriteString(m.importPage.View())
+		case EXPORT:
+			view.WriteString(m.exportPage.View())
+		case PERSON:
+			view.WriteString(m.personPage.View())
+		case FORYOU:
+			view.WriteString(m.forYouPage.View())
+		case SETTINGS:
+			view.WriteString(m.settingsPage.View())
+		case DISCOVER:
+			view.WriteString(m.discoverPage.View())
+		case SOCIAL:
+			view.WriteString(m.socialPage.View())
+		case PROFILE:
+			view.WriteString(m.profilePage.View())
+		case GROUPS:
+			view.WriteString(m.groupsPage.View())
+		case GROUP:
+			view.WriteString(m.groupPage.View())
+		case STATS:
+			view.WriteString(m.statsPage.View())
+		case DIARY:
+			view.WriteString(m.diaryPage.View())
+		case MANAGE:
+			view.WriteString(m.managePage.View())
 		}
 	}
 
@@ -297,18 +770,27 @@ func (m *Model) View() string {
 
 	app := view.String()
 
-	if m.dialog.Focused() {
-		dialogView := m.dialog.View()
-
-		dialogW := lipgloss.Width(dialogView)
-		dialogH := lipgloss.Height(dialogView)
+	if m.searchField.Focused() {
+		if suggestions := m.suggestions.View(); suggestions != "" {
+			// Directly under the search field
+			app = util.RenderOverlay(app, suggestions, lipgloss.Width(title)+1, 3)
+		}
+	}
 
-		xOffset := util.Max((m.props.Width-dialogW)/2, 0)
-		yOffset := util.Max((m.props.Height-dialogH)/2-3, 0)
+	if m.dialog.Focused() {
+		app = m.centerOverlay(app, m.dialog.View())
+	}
 
-		app = util.RenderOverlay(app, m.dialog.View(), xOffset, yOffset)
+	if m.idleWarned {
+		app = m.centerOverlay(app, m.idleView())
 	}
 
 	return appStyle.Render(app)
+}
+
+func (m *Model) centerOverlay(app, overlay string) string {
+	xOffset := util.Max((m.props.Width-lipgloss.Width(overlay))/2, 0)
+	yOffset := util.Max((m.props.Height-lipgloss.Height(overlay))/2-3, 0)
 
+	return util.RenderOverlay(app, overlay, xOffset, yOffset)
 }